@@ -3,11 +3,29 @@ package client
 import (
 	"CheckUrls/pkg/logging"
 	"CheckUrls/pkg/proto"
+	"CheckUrls/pkg/repository/labels"
+	"CheckUrls/pkg/repository/slo"
+	"bufio"
 	"context"
+	"encoding/csv"
 	"flag"
 	"fmt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"os"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 )
 
@@ -20,7 +38,9 @@ type CliConfig interface {
 func StartClient(cfg CliConfig) (proto.SitesServiceClient, error) {
 	logger := logging.NewLoggers("client", "startClient")
 	logger.DebugLog().Msg("connecting to server")
-	conn, err := grpc.Dial(cfg.GetServerAddress(), grpc.WithInsecure())
+	conn, err := grpc.Dial(cfg.GetServerAddress(), grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(withActor(currentUser())),
+		grpc.WithStreamInterceptor(withStreamActor(currentUser())))
 	if err != nil {
 		logger.ErrorLog().Str("when", "dial :8000").Err(err).Msg("failed start client")
 		return nil, err
@@ -29,36 +49,131 @@ func StartClient(cfg CliConfig) (proto.SitesServiceClient, error) {
 	return proto.NewSitesServiceClient(conn), nil
 }
 
+// actorKey is the metadata key of the user
+// who makes the request, see server.ActorKey
+const actorKey = "actor"
+
+// withActor sends the name of the user with every request
+// to record who changed the sites in the audit trail
+func withActor(name string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, actorKey, name)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// withStreamActor sends the name of the user with every stream like withActor
+func withStreamActor(name string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx = metadata.AppendToOutgoingContext(ctx, actorKey, name)
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// currentUser returns the name of the OS user running the client
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// siteFlags returns the flags of the optional settings of the site
+func siteFlags(site *proto.Site) *flag.FlagSet {
+	fs := flag.NewFlagSet("site", flag.ContinueOnError)
+	fs.StringVar(&site.CheckMode, "mode", site.CheckMode, "check mode: warm or cold")
+	fs.StringVar(&site.Proxy, "proxy", site.Proxy, "proxy of the checks or \"direct\"")
+	fs.StringVar(&site.ProxySecret, "proxy-secret", site.ProxySecret, "secret with proxy password")
+	fs.StringVar(&site.ClientCert, "cert", site.ClientCert, "client certificate")
+	fs.StringVar(&site.ClientKey, "key", site.ClientKey, "client key")
+	fs.StringVar(&site.CaBundle, "ca", site.CaBundle, "CA bundle")
+	fs.BoolVar(&site.SkipVerify, "insecure", site.SkipVerify, "skip TLS verification")
+	fs.StringVar(&site.AuthSecret, "auth-secret", site.AuthSecret, "secret sent as Authorization header")
+	fs.StringVar(&site.BasicAuthUser, "basic-user", site.BasicAuthUser, "basic auth user")
+	fs.StringVar(&site.BasicAuthSecret, "basic-secret", site.BasicAuthSecret, "secret with basic auth password")
+	fs.Var(labelFlag{site}, "label", "label key=value, repeat the flag for more labels")
+	return fs
+}
+
+// labelFlag adds the labels "key=value" to the site
+type labelFlag struct {
+	site *proto.Site
+}
+
+func (f labelFlag) String() string {
+	if f.site == nil {
+		return ""
+	}
+	return labels.String(f.site.Labels)
+}
+
+func (f labelFlag) Set(value string) error {
+	key, labelValue, ok := strings.Cut(value, "=")
+	if !ok {
+		return fmt.Errorf("%w: %q, enter key=value", labels.ErrIncorrectLabel, value)
+	}
+	if f.site.Labels == nil {
+		f.site.Labels = make(map[string]string)
+	}
+	f.site.Labels[key] = labelValue
+	return nil
+}
+
+// parseSiteOptions parses the optional settings
+// of the site which follow the positional arguments
+func parseSiteOptions(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return IncorrectInput
+	}
+	return nil
+}
+
 func ReqCreateSite(ctx context.Context, cli proto.SitesServiceClient) error {
 	logger := logging.NewLoggers("client", "reqCreate")
 	logger.DebugLog().Msg("checking for the correctness of arguments")
 	if flag.NArg() < 3 {
 		err := IncorrectInput
 		logger.ErrorLog().Err(err).Str("request", "failed to process").
-			Msg("please enter \"create <url> <frequency>\"")
+			Msg("please enter \"create <url> <frequency> [options]\"")
 		return err
 	}
 
 	logger.DebugLog().Msg("getting arguments")
 	url := flag.Arg(2)
 	frequency := 0
+	options := 3
 	var err error
-	if flag.Arg(3) != "" {
+	if flag.Arg(3) != "" && !strings.HasPrefix(flag.Arg(3), "-") {
 		frequency, err = strconv.Atoi(flag.Arg(3))
 		if err != nil {
 			logger.ErrorLog().Err(err).Str("request", "failed to process").
 				Msg("cannot to convert frequency")
 			return err
 		}
+		options++
+	}
+	site := &proto.Site{
+		Url:       url,
+		Frequency: int64(frequency),
+	}
+	fs := siteFlags(site)
+	restore := fs.Bool("restore", false, "restore the last deleted site with the url")
+	if err := parseSiteOptions(fs, flag.Args()[options:]); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("cannot to parse site options")
+		return err
 	}
 
 	logger.DebugLog().Msg("create site")
-	res, err := cli.Create(ctx, &proto.CreateRequestSite{
-		Sites: &proto.Site{
-			Url:       url,
-			Frequency: int64(frequency),
-		},
-	})
+	res, err := cli.Create(ctx, &proto.CreateRequestSite{Sites: site, Restore: *restore})
 	if err != nil {
 		logger.ErrorLog().Err(err).Str("request", "failed to process").
 			Msg("unable to create site")
@@ -104,22 +219,44 @@ func ReqReadSite(ctx context.Context, cli proto.SitesServiceClient) error {
 func ReqReadAllSite(ctx context.Context, cli proto.SitesServiceClient) error {
 	logger := logging.NewLoggers("client", "reqReadAllSite")
 	logger.DebugLog().Msg("checking for the correctness of arguments")
-	if flag.NArg() != 2 {
-		err := IncorrectInput
+	req := &proto.ReadAllRequestSite{}
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.StringVar(&req.Selector, "l", "", "label selector of the sites, like team=payments")
+	fs.StringVar(&req.UrlContains, "url", "", "substring of the url")
+	fs.StringVar(&req.State, "state", "", "active (default), deleted or all")
+	fs.Int64Var(&req.MinFrequency, "min-freq", 0, "min frequency in seconds")
+	fs.Int64Var(&req.MaxFrequency, "max-freq", 0, "max frequency in seconds")
+	fs.StringVar(&req.OrderBy, "sort", "", "id (default), url or created, like \"created desc\"")
+	fs.Int64Var(&req.PageSize, "page-size", 0, "sites of one page, 100 by default")
+	fs.StringVar(&req.PageToken, "page-token", "", "token of the page to start from")
+	pages := fs.Int("pages", 0, "number of pages, 0 - all pages")
+	if err := parseSiteOptions(fs, flag.Args()[2:]); err != nil {
 		logger.ErrorLog().Err(err).Str("request", "failed to process").
-			Msg("please enter \"list\"")
+			Msg("please enter \"list [options]\"")
 		return err
 	}
 
 	logger.DebugLog().Msg("getting list of sites")
-	res, err := cli.ReadAll(ctx, &proto.ReadAllRequestSite{})
-	if err != nil {
-		logger.ErrorLog().Err(err).Str("request", "failed to process").
-			Msg("unable to get list of sites")
-		return err
+	count := 0
+	for page := 1; ; page++ {
+		res, err := cli.ReadAll(ctx, req)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("unable to get list of sites")
+			return err
+		}
+		count += len(res.GetSites())
+		logger.InfoLog().Int("page", page).Interface("list of sites: ", res.GetSites()).Msg("page")
+		req.PageToken = res.GetNextPageToken()
+		if req.PageToken == "" {
+			break
+		}
+		if page == *pages {
+			logger.InfoLog().Str("next page token", req.PageToken).Msg("more sites")
+			break
+		}
 	}
-	logger.InfoLog().Str("request", "processed successfully").
-		Interface("list of sites: ", res.GetSites()).Msg("done")
+	logger.InfoLog().Str("request", "processed successfully").Int("sites", count).Msg("done")
 
 	return nil
 }
@@ -127,10 +264,10 @@ func ReqReadAllSite(ctx context.Context, cli proto.SitesServiceClient) error {
 func ReqUpdateSite(ctx context.Context, cli proto.SitesServiceClient) error {
 	logger := logging.NewLoggers("client", "reqUpdate")
 	logger.DebugLog().Msg("checking for the correctness of arguments")
-	if flag.NArg() < 5 {
+	if flag.NArg() < 3 {
 		err := IncorrectInput
 		logger.ErrorLog().Err(err).Str("request", "failed to process").
-			Msg("please enter \"update <site_id> <url> <frequency>\"")
+			Msg("please enter \"update <site_id> [<url> <frequency>] [options]\"")
 		return err
 	}
 
@@ -141,32 +278,105 @@ func ReqUpdateSite(ctx context.Context, cli proto.SitesServiceClient) error {
 			Msg("cannot to convert site_id")
 		return err
 	}
-	url := flag.Arg(3)
-	frequency, err := strconv.Atoi(flag.Arg(4))
-	if err != nil {
-		logger.ErrorLog().Err(err).Str("request", "failed to process").
-			Msg("cannot to convert site_id")
-		return err
-	}
-	site := &proto.Site{
-		Id:        int64(id),
-		Url:       url,
-		Frequency: int64(frequency),
+	site := &proto.Site{Id: int64(id)}
+	request := &proto.UpdateRequestSite{Sites: site}
+	fs := siteFlags(site)
+	fs.Int64Var(&site.Version, "version", 0, "version of the site, the site is not updated when it has another version")
+	if flag.NArg() >= 5 && !strings.HasPrefix(flag.Arg(3), "-") {
+		// the url and the frequency replace the whole site
+		site.Url = flag.Arg(3)
+		frequency, err := strconv.Atoi(flag.Arg(4))
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to convert frequency")
+			return err
+		}
+		site.Frequency = int64(frequency)
+		if err := parseSiteOptions(fs, flag.Args()[5:]); err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to parse site options")
+			return err
+		}
+	} else {
+		// the options change only the given fields
+		var removed unlabelFlag
+		fs.StringVar(&site.Url, "url", "", "url of the site")
+		fs.Int64Var(&site.Frequency, "frequency", 0, "frequency of the checks in seconds")
+		fs.Var(&removed, "unlabel", "key of the removed label, repeat the flag for more labels")
+		if err := parseSiteOptions(fs, flag.Args()[3:]); err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to parse site options")
+			return err
+		}
+		if request.UpdateMask = updateMask(fs, site, removed); len(request.UpdateMask.Paths) == 0 {
+			err := IncorrectInput
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("please enter the changed fields, like \"update <site_id> -frequency 30\"")
+			return err
+		}
 	}
 
 	logger.DebugLog().Msg("updating site")
-	res, err := cli.Update(ctx, &proto.UpdateRequestSite{Sites: site})
+	res, err := cli.Update(ctx, request)
 	if err != nil {
 		logger.ErrorLog().Err(err).Str("request", "failed to process").
 			Msg("unable to update site")
 		return err
 	}
 	logger.InfoLog().Str("request", "processed successfully").
-		Interface("updated: ", res.GetUpdated()).Msg("done")
+		Interface("updated: ", res.GetUpdated()).Int64("version", res.GetSites().GetVersion()).Msg("done")
 
 	return nil
 }
 
+// maskPaths maps the flags of the site to the paths of the update mask
+var maskPaths = map[string]string{
+	"url":          "url",
+	"frequency":    "frequency",
+	"mode":         "check_mode",
+	"proxy":        "proxy",
+	"proxy-secret": "proxy_secret",
+	"cert":         "client_cert",
+	"key":          "client_key",
+	"ca":           "ca_bundle",
+	"insecure":     "skip_verify",
+	"auth-secret":  "auth_secret",
+	"basic-user":   "basic_auth_user",
+	"basic-secret": "basic_auth_secret",
+}
+
+// updateMask lists the fields of the flags set by the user,
+// every added and removed label is a field labels.<key>
+func updateMask(fs *flag.FlagSet, site *proto.Site, removed unlabelFlag) *fieldmaskpb.FieldMask {
+	mask := new(fieldmaskpb.FieldMask)
+	fs.Visit(func(f *flag.Flag) {
+		if path, ok := maskPaths[f.Name]; ok {
+			mask.Paths = append(mask.Paths, path)
+		}
+	})
+	keys := make([]string, 0, len(site.Labels)+len(removed))
+	for key := range site.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range append(keys, removed...) {
+		mask.Paths = append(mask.Paths, "labels."+key)
+	}
+	return mask
+}
+
+// unlabelFlag collects the keys of the removed labels
+type unlabelFlag []string
+
+func (f *unlabelFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *unlabelFlag) Set(key string) error {
+	*f = append(*f, key)
+	return nil
+}
+
 func ReqDeleteSite(ctx context.Context, cli proto.SitesServiceClient) error {
 	logger := logging.NewLoggers("client", "reqDelete")
 	logger.DebugLog().Msg("checking for the correctness of arguments")
@@ -199,22 +409,367 @@ func ReqDeleteSite(ctx context.Context, cli proto.SitesServiceClient) error {
 	return nil
 }
 
+func ReqListDeleted(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqListDeleted")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	if flag.NArg() != 2 {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"deleted\"")
+		return err
+	}
+
+	logger.DebugLog().Msg("getting list of deleted sites")
+	res, err := cli.ListDeleted(ctx, &proto.ListDeletedRequestSite{})
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to get list of deleted sites")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").
+		Interface("list of deleted sites: ", res.GetSites()).Msg("done")
+
+	return nil
+}
+
+func ReqRestoreSite(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqRestore")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	if flag.NArg() != 3 {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"restore <site_id>\"")
+		return err
+	}
+
+	logger.DebugLog().Msg("getting arguments")
+	id, err := strconv.Atoi(flag.Arg(2))
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("cannot to convert site_id")
+		return err
+	}
+
+	logger.DebugLog().Msg("restoring site")
+	res, err := cli.Restore(ctx, &proto.RestoreRequestSite{Id: int64(id)})
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to restore site")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").
+		Interface("restored: ", res.GetRestored()).Msg("done")
+
+	return nil
+}
+
+func ReqPurgeSite(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqPurge")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	if flag.NArg() != 3 {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"purge <site_id>\"")
+		return err
+	}
+
+	logger.DebugLog().Msg("getting arguments")
+	id, err := strconv.Atoi(flag.Arg(2))
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("cannot to convert site_id")
+		return err
+	}
+
+	logger.DebugLog().Msg("purging site")
+	res, err := cli.Purge(ctx, &proto.PurgeRequestSite{Id: int64(id)})
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to purge site")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").
+		Interface("purged: ", res.GetPurged()).Msg("done")
+
+	return nil
+}
+
+func ReqListAuditEvents(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqListAuditEvents")
+	logger.DebugLog().Msg("getting arguments")
+	request := &proto.ListRequestAudit{}
+	var from, to string
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.Int64Var(&request.SiteId, "site", 0, "events of the site")
+	fs.StringVar(&request.Actor, "actor", "", "events of the user")
+	fs.StringVar(&request.Operation, "op", "", "events of the operation: create, update, delete, restore or purge")
+	fs.StringVar(&from, "from", "", "events since the time in RFC 3339")
+	fs.StringVar(&to, "to", "", "events before the time in RFC 3339")
+	fs.Int64Var(&request.Limit, "limit", 0, "number of the latest events, 100 by default")
+	if err := parseSiteOptions(fs, flag.Args()[2:]); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"audit [-site <site_id>] [-actor <user>] [-op <operation>] " +
+				"[-from <time>] [-to <time>] [-limit <count>]\"")
+		return err
+	}
+	for _, t := range []struct {
+		value string
+		field **timestamppb.Timestamp
+	}{{from, &request.From}, {to, &request.To}} {
+		if t.value == "" {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, t.value)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to parse time, use RFC 3339 like 2021-05-01T12:00:00Z")
+			return err
+		}
+		*t.field = timestamppb.New(date)
+	}
+
+	logger.DebugLog().Msg("getting list of events")
+	res, err := cli.ListAuditEvents(ctx, request)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to get list of events")
+		return err
+	}
+	for _, e := range res.GetEvents() {
+		logger.InfoLog().Int64("id", e.GetId()).Time("date", e.GetDate().AsTime()).
+			Str("actor", e.GetActor()).Str("address", e.GetAddress()).Str("operation", e.GetOperation()).Int64("site_id", e.GetSiteId()).
+			Str("before", e.GetBefore()).Str("after", e.GetAfter()).Msg("event")
+	}
+	logger.InfoLog().Str("request", "processed successfully").
+		Int("events", len(res.GetEvents())).Msg("done")
+
+	return nil
+}
+
+// ReqImportSites creates or updates the sites of the CSV or JSON lines file
+func ReqImportSites(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqImportSites")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "format of the file: csv or json, by the extension by default")
+	if flag.NArg() < 3 || parseSiteOptions(fs, flag.Args()[3:]) != nil {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"import <file> [-format csv|json]\", \"-\" reads the standard input")
+		return err
+	}
+
+	logger.DebugLog().Msg("reading sites")
+	name := flag.Arg(2)
+	if *format == "" {
+		*format = importFormatCsv
+		if ext := strings.ToLower(filepath.Ext(name)); ext == ".json" || ext == ".jsonl" || ext == ".ndjson" {
+			*format = importFormatJson
+		}
+	}
+	var list []*proto.Site
+	if err := readFile(name, func(r io.Reader) (err error) {
+		list, err = readSites(r, *format)
+		return err
+	}); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("cannot to read sites")
+		return err
+	}
+
+	logger.DebugLog().Int("sites", len(list)).Msg("importing sites")
+	stream, err := cli.ImportSites(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to import sites")
+		return err
+	}
+	for _, site := range list {
+		if err := stream.Send(&proto.ImportRequestSite{Sites: site}); err != nil {
+			// the server closed the stream, its error is returned by CloseAndRecv
+			break
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to import sites")
+		return err
+	}
+	for _, result := range res.GetResults() {
+		if result.GetResult() == "failed" {
+			logger.WarnLog().Int64("index", result.GetIndex()).Str("site", result.GetUrl()).
+				Str("reason", result.GetReason()).Msg("failed")
+			continue
+		}
+		logger.InfoLog().Int64("index", result.GetIndex()).Str("site", result.GetUrl()).
+			Int64("site_id", result.GetId()).Msg(result.GetResult())
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int64("created", res.GetCreated()).
+		Int64("updated", res.GetUpdated()).Int64("failed", res.GetFailed()).Msg("done")
+
+	return nil
+}
+
+// formats of the imported sites
+const (
+	importFormatCsv  = "csv"
+	importFormatJson = "json"
+)
+
+// readFile calls read with the file, "-" is the standard input
+func readFile(name string, read func(io.Reader) error) error {
+	if name == "-" {
+		return read(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return read(f)
+}
+
+// readSites reads the sites in the format, JSON lines have a site
+// in every line, like {"url": "https://example.com", "frequency": 60}.
+// CSV has a header with the names of the fields of JSON and the
+// labels like "team=payments,env=prod".
+func readSites(r io.Reader, format string) ([]*proto.Site, error) {
+	switch format {
+	case importFormatCsv:
+		return readCsvSites(r)
+	case importFormatJson:
+		return readJsonSites(r)
+	}
+	return nil, fmt.Errorf("%w: unknown format %q", IncorrectInput, format)
+}
+
+func readJsonSites(r io.Reader) ([]*proto.Site, error) {
+	var list []*proto.Site
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		site := &proto.Site{}
+		if err := protojson.Unmarshal([]byte(text), site); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		list = append(list, site)
+	}
+	return list, scanner.Err()
+}
+
+// csvColumns set the fields of the site from the columns of CSV
+var csvColumns = map[string]func(site *proto.Site, value string) error{
+	"url":               func(site *proto.Site, value string) error { site.Url = value; return nil },
+	"frequency":         csvInt(func(site *proto.Site) *int64 { return &site.Frequency }),
+	"check_mode":        func(site *proto.Site, value string) error { site.CheckMode = value; return nil },
+	"proxy":             func(site *proto.Site, value string) error { site.Proxy = value; return nil },
+	"proxy_secret":      func(site *proto.Site, value string) error { site.ProxySecret = value; return nil },
+	"client_cert":       func(site *proto.Site, value string) error { site.ClientCert = value; return nil },
+	"client_key":        func(site *proto.Site, value string) error { site.ClientKey = value; return nil },
+	"ca_bundle":         func(site *proto.Site, value string) error { site.CaBundle = value; return nil },
+	"skip_verify":       csvBool(func(site *proto.Site) *bool { return &site.SkipVerify }),
+	"auth_secret":       func(site *proto.Site, value string) error { site.AuthSecret = value; return nil },
+	"basic_auth_user":   func(site *proto.Site, value string) error { site.BasicAuthUser = value; return nil },
+	"basic_auth_secret": func(site *proto.Site, value string) error { site.BasicAuthSecret = value; return nil },
+	"labels": func(site *proto.Site, value string) error {
+		for _, label := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' }) {
+			if err := (labelFlag{site}).Set(strings.TrimSpace(label)); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+func csvInt(field func(site *proto.Site) *int64) func(site *proto.Site, value string) error {
+	return func(site *proto.Site, value string) error {
+		if value == "" {
+			return nil
+		}
+		number, err := strconv.ParseInt(value, 10, 64)
+		*field(site) = number
+		return err
+	}
+}
+
+func csvBool(field func(site *proto.Site) *bool) func(site *proto.Site, value string) error {
+	return func(site *proto.Site, value string) error {
+		if value == "" {
+			return nil
+		}
+		enabled, err := strconv.ParseBool(value)
+		*field(site) = enabled
+		return err
+	}
+}
+
+func readCsvSites(r io.Reader) ([]*proto.Site, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if csvColumns[header[i]] == nil {
+			return nil, fmt.Errorf("%w: unknown column %q", IncorrectInput, name)
+		}
+	}
+
+	var list []*proto.Site
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return list, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		site := &proto.Site{}
+		for i, value := range record {
+			if err := csvColumns[header[i]](site, strings.TrimSpace(value)); err != nil {
+				return nil, fmt.Errorf("line %d, column %s: %w", line, header[i], err)
+			}
+		}
+		list = append(list, site)
+	}
+}
+
 func ReqReadStatus(ctx context.Context, cli proto.SitesServiceClient) error {
 	logger := logging.NewLoggers("client", "reqReadStatus")
 	logger.DebugLog().Msg("checking for the correctness of arguments")
-	if flag.NArg() < 3 {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	selector := fs.String("l", "", "label selector of the sites, like team=payments")
+	var args []string
+	if flag.NArg() > 2 {
+		if err := fs.Parse(flag.Args()[2:]); err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to parse options")
+			return err
+		}
+		args = fs.Args()
+	}
+	if (*selector == "" && len(args) == 0) || len(args) > 2 || (*selector != "" && len(args) > 1) {
 		err := IncorrectInput
 		logger.ErrorLog().Err(err).Str("request", "failed to process").
-			Msg("please enter \"<url>\"")
+			Msg("please enter \"status <url> [count]\" or \"status -l <selector> [count]\"")
 		return err
 	}
 
 	logger.DebugLog().Msg("getting arguments")
-	url := flag.Arg(2)
+	url := ""
+	if *selector == "" {
+		url, args = args[0], args[1:]
+	}
 	count := 5
 	var err error
-	if flag.Arg(3) != "" {
-		count, err = strconv.Atoi(flag.Arg(3))
+	if len(args) != 0 {
+		count, err = strconv.Atoi(args[0])
 		if err != nil {
 			logger.ErrorLog().Err(err).Str("when", "convert count").Msg("unable to convert count")
 			return err
@@ -223,21 +778,408 @@ func ReqReadStatus(ctx context.Context, cli proto.SitesServiceClient) error {
 
 	logger.DebugLog().Msg("read request processing")
 	res, err := cli.ReadStatus(ctx, &proto.ReadRequestState{
-		Url:   url,
-		Count: int64(count),
+		Url:      url,
+		Count:    int64(count),
+		Selector: *selector,
 	})
 	if err != nil {
 		logger.ErrorLog().Err(err).Str("request", "failed to process").
 			Msg("unable to get list of states")
 		return err
 	}
+	if *selector == "" {
+		logger.InfoLog().Str("request", "processed successfully").Str("site", res.GetUrl()).
+			Interface("frequency", res.GetFrequency()).Str("list of states: ", formatStates(res)).Msg("done")
+		return nil
+	}
+	for _, site := range res.GetSites() {
+		logger.InfoLog().Str("site", site.GetUrl()).Interface("frequency", site.GetFrequency()).
+			Str("list of states: ", formatStates(site)).Msg("status")
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int("sites", len(res.GetSites())).Msg("done")
+
+	return nil
+}
+
+// ReqReadHistory prints the states of the site by pages
+func ReqReadHistory(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqReadHistory")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	req := &proto.ReadRequestHistory{}
+	var since, until string
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.StringVar(&since, "since", "", "states since the time in RFC 3339 or the duration ago, like 24h")
+	fs.StringVar(&until, "until", "", "states before the time in RFC 3339 or the duration ago")
+	fs.StringVar(&req.Health, "health", "", "up or down, all states by default")
+	fs.Int64Var(&req.PageSize, "page-size", 0, "states of one page, 100 by default")
+	fs.StringVar(&req.PageToken, "page-token", "", "token of the page to start from")
+	pages := fs.Int("pages", 0, "number of pages, 0 - all pages")
+	if flag.NArg() < 3 || parseSiteOptions(fs, flag.Args()[3:]) != nil {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"history <site_id> [-since <time>] [-until <time>] [-health up|down]\"")
+		return err
+	}
+
+	logger.DebugLog().Msg("getting arguments")
+	var err error
+	if req.SiteId, err = strconv.ParseInt(flag.Arg(2), 10, 64); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("cannot to convert site_id")
+		return err
+	}
+	now := time.Now()
+	for _, t := range []struct {
+		value string
+		field **timestamppb.Timestamp
+	}{{since, &req.From}, {until, &req.To}} {
+		if t.value == "" {
+			continue
+		}
+		date, err := parseTime(t.value, now)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to parse time, use RFC 3339 like 2021-05-01T12:00:00Z or the duration like 24h")
+			return err
+		}
+		*t.field = timestamppb.New(date)
+	}
+
+	logger.DebugLog().Msg("getting history")
+	count := 0
+	for page := 1; ; page++ {
+		res, err := cli.ReadHistory(ctx, req)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("unable to get history")
+			return err
+		}
+		count += len(res.GetStates())
+		logger.InfoLog().Int("page", page).Str("site", res.GetUrl()).
+			Str("list of states: ", formatStates(&proto.StatusResponse{States: res.GetStates()})).Msg("page")
+		req.PageToken = res.GetNextPageToken()
+		if req.PageToken == "" {
+			break
+		}
+		if page == *pages {
+			logger.InfoLog().Str("next page token", req.PageToken).Msg("more states")
+			break
+		}
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int("states", count).Msg("done")
+
+	return nil
+}
+
+// ReqUptimeReport prints the uptime report of the sites as a table
+func ReqUptimeReport(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqUptimeReport")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	req := &proto.ReportRequestUptime{}
+	var ids, from, to, month string
+	fs := flag.NewFlagSet("uptime", flag.ContinueOnError)
+	fs.StringVar(&ids, "site", "", "ids of the sites separated by commas")
+	fs.StringVar(&req.Selector, "l", "", "label selector of the sites, like team=payments")
+	fs.StringVar(&from, "from", "", "start of the window in RFC 3339 or the duration ago, like 720h")
+	fs.StringVar(&to, "to", "", "end of the window in RFC 3339 or the duration ago, now by default")
+	fs.StringVar(&month, "month", "", "the month in UTC instead of the window, like 2021-05")
+	if err := parseSiteOptions(fs, flag.Args()[2:]); err != nil || (month == "") == (from == "") ||
+		(month != "" && to != "") {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"uptime -from <time> [-to <time>] [-site <site_id>,...] [-l <selector>]\" " +
+				"or \"uptime -month <yyyy-mm> [-site <site_id>,...] [-l <selector>]\"")
+		return err
+	}
+
+	logger.DebugLog().Msg("getting arguments")
+	for _, id := range strings.FieldsFunc(ids, func(r rune) bool { return r == ',' }) {
+		siteId, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to convert site_id")
+			return err
+		}
+		req.SiteIds = append(req.SiteIds, siteId)
+	}
+	if month != "" {
+		start, err := time.Parse("2006-01", month)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to parse month, use yyyy-mm like 2021-05")
+			return err
+		}
+		req.From, req.To = timestamppb.New(start), timestamppb.New(start.AddDate(0, 1, 0))
+	}
+	now := time.Now()
+	for _, t := range []struct {
+		value string
+		field **timestamppb.Timestamp
+	}{{from, &req.From}, {to, &req.To}} {
+		if t.value == "" {
+			continue
+		}
+		date, err := parseTime(t.value, now)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to parse time, use RFC 3339 like 2021-05-01T12:00:00Z or the duration like 720h")
+			return err
+		}
+		*t.field = timestamppb.New(date)
+	}
+
+	logger.DebugLog().Msg("getting report")
+	res, err := cli.GetUptimeReport(ctx, req)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to get report")
+		return err
+	}
+	if err := writeUptimeReport(os.Stdout, res); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to write report")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int("sites", len(res.GetSites())).Msg("done")
+
+	return nil
+}
+
+// writeUptimeReport writes the report as a table with a site in every row
+func writeUptimeReport(w io.Writer, res *proto.ReportResponseUptime) error {
+	ms := func(value int64) string {
+		return (time.Duration(value) * time.Millisecond).String()
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Uptime from %s to %s\n", res.GetFrom().AsTime().Format(time.RFC3339),
+		res.GetTo().AsTime().Format(time.RFC3339))
+	fmt.Fprintln(tw, "ID\tURL\tUPTIME\tDOWN\tNO CHECKS\tINCIDENTS\tMTTR\tCHECKS\tP50\tP90\tP99\t")
+	for _, site := range res.GetSites() {
+		// the site without checks was not monitored, it is neither up nor down
+		uptime := "n/a"
+		if site.GetUpMs()+site.GetDownMs() != 0 {
+			uptime = fmt.Sprintf("%.3f%%", site.GetUptimePercent())
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%d\t%s\t%s\t%s\t\n", site.GetSiteId(), site.GetUrl(),
+			uptime, ms(site.GetDownMs()), ms(site.GetGapMs()), site.GetIncidents(),
+			ms(site.GetMttrMs()), site.GetChecks(), ms(site.GetLatencyP50Ms()), ms(site.GetLatencyP90Ms()),
+			ms(site.GetLatencyP99Ms()))
+	}
+	return tw.Flush()
+}
+
+// ReqCreateObjective creates the objective of the site or of the sites selected by labels
+func ReqCreateObjective(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqCreateObjective")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	o := &proto.Objective{}
+	var threshold, window time.Duration
+	fs := flag.NewFlagSet("slo create", flag.ContinueOnError)
+	fs.Int64Var(&o.SiteId, "site", 0, "id of the site")
+	fs.StringVar(&o.Selector, "l", "", "label selector of the sites, like team=payments, all sites by default")
+	fs.StringVar(&o.Kind, "kind", "availability", "availability or latency")
+	fs.Float64Var(&o.Target, "target", 0, "percent of good checks, like 99.9")
+	fs.DurationVar(&threshold, "threshold", 0, "latency of a good check of the latency objective, like 500ms")
+	fs.DurationVar(&window, "window", 30*24*time.Hour, "rolling window of the objective")
+	if flag.NArg() < 4 || parseSiteOptions(fs, flag.Args()[4:]) != nil {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"slo create <name> -target <percent> [-kind availability|latency] " +
+				"[-threshold <latency>] [-window <duration>] [-site <site_id> | -l <selector>]\"")
+		return err
+	}
+	o.Name = flag.Arg(3)
+	o.ThresholdMs, o.WindowSeconds = threshold.Milliseconds(), int64(window/time.Second)
+
+	logger.DebugLog().Msg("creating objective")
+	res, err := cli.CreateObjective(ctx, &proto.CreateRequestObjective{Objective: o})
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to create objective")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").
+		Interface("created: ", res.GetObjective().GetId()).Msg("done")
+
+	return nil
+}
+
+// ReqListObjectives prints the objectives with their error budgets as a table
+func ReqListObjectives(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqListObjectives")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	if flag.NArg() != 3 {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"slo list\"")
+		return err
+	}
+
+	logger.DebugLog().Msg("getting list of objectives")
+	res, err := cli.ListObjectives(ctx, &proto.ListRequestObjective{})
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to get list of objectives")
+		return err
+	}
+	if err := writeObjectives(os.Stdout, res); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to write objectives")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").
+		Int("objectives", len(res.GetObjectives())).Msg("done")
+
+	return nil
+}
+
+// writeObjectives writes the objectives as a table with an objective in every row
+func writeObjectives(w io.Writer, res *proto.ListResponseObjective) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tSITES\tOBJECTIVE\tWINDOW\tGOOD\tBUDGET LEFT\tBURN 1H\tBURN 6H\tALERT\t")
+	for _, st := range res.GetObjectives() {
+		o := st.GetObjective()
+		selected := "all"
+		if o.GetSiteId() != 0 {
+			selected = fmt.Sprintf("site %d", o.GetSiteId())
+		} else if o.GetSelector() != "" {
+			selected = o.GetSelector()
+		}
+		objective := fmt.Sprintf("%g%% %s", o.GetTarget(), o.GetKind())
+		if o.GetKind() == slo.KindLatency {
+			objective += fmt.Sprintf(" < %v", time.Duration(o.GetThresholdMs())*time.Millisecond)
+		}
+		// the objective without checks has neither good nor bad checks
+		good, left, burn1h, burn6h := "n/a", "n/a", "n/a", "n/a"
+		if st.GetTracked() && st.GetChecks() != 0 {
+			good = fmt.Sprintf("%.3f%%", st.GetGoodPercent())
+			left = fmt.Sprintf("%.1f%%", st.GetBudgetRemainingPercent())
+			burn1h = fmt.Sprintf("%.2f", st.GetBurnRate_1H())
+			burn6h = fmt.Sprintf("%.2f", st.GetBurnRate_6H())
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%v\t%s\t%s\t%s\t%s\t%s\t\n", o.GetId(), o.GetName(), selected,
+			objective, time.Duration(o.GetWindowSeconds())*time.Second, good, left, burn1h, burn6h, st.GetAlert())
+	}
+	return tw.Flush()
+}
+
+// ReqDeleteObjective removes the objective
+func ReqDeleteObjective(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqDeleteObjective")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	if flag.NArg() != 4 {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"slo delete <objective_id>\"")
+		return err
+	}
+
+	logger.DebugLog().Msg("getting arguments")
+	id, err := strconv.ParseInt(flag.Arg(3), 10, 64)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("cannot to convert objective_id")
+		return err
+	}
+
+	logger.DebugLog().Msg("deleting objective")
+	res, err := cli.DeleteObjective(ctx, &proto.DeleteRequestObjective{Id: id})
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("unable to delete objective")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").
+		Interface("deleted: ", res.GetDeleted()).Msg("done")
+
+	return nil
+}
+
+// parseTime parses the time in RFC 3339 or the duration before now
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if ago, err := time.ParseDuration(value); err == nil {
+		return now.Add(-ago), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// formatStates formats the states and the rollups of the site in one line
+func formatStates(res *proto.StatusResponse) string {
 	statesStr := ""
 	for _, state := range res.GetStates() {
-		statesStr += fmt.Sprintf("%d: %s - %d; ", state.GetId(),
-			time.Unix(state.GetDate().GetSeconds(), int64(state.GetDate().GetNanos())), state.GetStatus())
+		statesStr += fmt.Sprintf("%d: %s - %d (%dms", state.GetId(),
+			time.Unix(state.GetDate().GetSeconds(), int64(state.GetDate().GetNanos())), state.GetStatus(),
+			state.GetLatencyMs())
+		if state.GetProxy() != "" {
+			statesStr += " via " + state.GetProxy()
+		}
+		if state.GetInsecure() {
+			statesStr += ", INSECURE"
+		}
+		if state.GetError() != "" {
+			statesStr += ", " + state.GetError()
+		}
+		statesStr += "); "
 	}
-	logger.InfoLog().Str("request", "processed successfully").Str("site", res.GetUrl()).
-		Interface("frequency", res.GetFrequency()).Str("list of states: ", statesStr).Msg("done")
+	for _, rollup := range res.GetRollups() {
+		statesStr += fmt.Sprintf("%s %s: %d checks, %.2f%% up (p50 %dms, p90 %dms, p99 %dms); ",
+			rollup.GetResolution(), time.Unix(rollup.GetStart().GetSeconds(), 0).UTC(), rollup.GetChecks(),
+			rollup.GetUpRatio()*100, rollup.GetLatencyP50Ms(), rollup.GetLatencyP90Ms(), rollup.GetLatencyP99Ms())
+	}
+	return statesStr
+}
 
-	return nil
+// ReqWatchStatus prints the checks of the sites as they happen, until it is interrupted
+func ReqWatchStatus(ctx context.Context, cli proto.SitesServiceClient) error {
+	logger := logging.NewLoggers("client", "reqWatchStatus")
+	logger.DebugLog().Msg("getting arguments")
+	request := &proto.WatchRequestState{}
+	var ids string
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.StringVar(&ids, "site", "", "ids of the sites separated by commas")
+	fs.StringVar(&request.Selector, "l", "", "label selector of the sites, like team=payments")
+	fs.BoolVar(&request.TransitionsOnly, "transitions", false, "only the checks when the site went up or down")
+	if err := parseSiteOptions(fs, flag.Args()[2:]); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"watch [-site <site_id>,...] [-l <selector>] [-transitions]\"")
+		return err
+	}
+	for _, id := range strings.FieldsFunc(ids, func(r rune) bool { return r == ',' }) {
+		siteId, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("request", "failed to process").
+				Msg("cannot to convert site_id")
+			return err
+		}
+		request.SiteIds = append(request.SiteIds, siteId)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	logger.DebugLog().Msg("watching checks")
+	stream, err := cli.WatchStatus(ctx, request)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to watch checks")
+		return err
+	}
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF || status.Code(err) == codes.Canceled {
+				logger.InfoLog().Str("request", "processed successfully").Msg("done")
+				return nil
+			}
+			logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to watch checks")
+			return err
+		}
+		state := res.GetState()
+		event := logger.InfoLog()
+		if res.GetTransition() {
+			event = logger.WarnLog()
+		}
+		event.Int64("site_id", state.GetSiteId()).Str("site", res.GetUrl()).
+			Time("date", state.GetDate().AsTime()).Int64("status", state.GetStatus()).
+			Int64("latency_ms", state.GetLatencyMs()).Bool("up", res.GetUp()).
+			Bool("transition", res.GetTransition()).Msg("check")
+	}
 }
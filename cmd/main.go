@@ -2,11 +2,26 @@ package main
 
 import (
 	"CheckUrls/cmd/client"
+	"CheckUrls/cmd/migrate"
+	"CheckUrls/cmd/secrets"
 	server "CheckUrls/cmd/server"
 	"CheckUrls/pkg/backendMngr"
+	"CheckUrls/pkg/budget"
+	"CheckUrls/pkg/checker"
 	"CheckUrls/pkg/config"
 	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/limiter"
 	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/metrics"
+	"CheckUrls/pkg/migrations"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/memory"
+	"CheckUrls/pkg/repository/postgres"
+	"CheckUrls/pkg/repository/sqlite"
+	"CheckUrls/pkg/retention"
+	secretStore "CheckUrls/pkg/secrets"
+	"CheckUrls/pkg/spool"
+	"CheckUrls/pkg/writer"
 	"context"
 	"flag"
 	"fmt"
@@ -14,8 +29,10 @@ import (
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"math/rand"
 	"os"
 	"os/signal"
+	"time"
 )
 
 type loggerConfig interface {
@@ -42,24 +59,95 @@ func main() {
 	case "server":
 		dbCfg := db.DbConfig(cfg)
 		serveCfg := server.ServerConfig(cfg)
+		schedCfg := backendMngr.ScheduleConfig(cfg)
+		rand.Seed(time.Now().UnixNano())
+		limiterCfg := limiter.LimiterConfig(cfg)
+		metricsCfg := metrics.MetricsConfig(cfg)
+		checkerCfg := checker.CheckerConfig(cfg)
+		secretsCfg := secretStore.SecretsConfig(cfg)
+		retentionCfg := retention.RetentionConfig(cfg)
+		writerCfg := writer.WriterConfig(cfg)
+		spoolCfg := spool.SpoolConfig(cfg)
+		budgetCfg := budget.BudgetConfig(cfg)
 
-		connMnr := db.NewConnectionManager()
+		hostLimiter, err := limiter.NewHostLimiter(limiterCfg)
+		if err != nil {
+			logger.FatalLog().Str("when", "create host limiter").Err(err).Msg("failed to create host limiter")
+		}
+		siteChecker, err := checker.NewChecker(checkerCfg, hostLimiter)
+		if err != nil {
+			logger.FatalLog().Str("when", "create checker").Err(err).Msg("failed to create checker")
+		}
 
-		if err := connMnr.Connect(dbCfg); err != nil {
-			logger.FatalLog().Str("when", "connect DB").Err(err).Msg("failed to connect DB")
+		serverFlags := flag.NewFlagSet("server", flag.ExitOnError)
+		autoMigrate := serverFlags.Bool("migrate", false, "apply pending migrations before start")
+		ephemeral := serverFlags.Bool("ephemeral", false, "keep sites and statuses in memory instead of DB")
+		if err := serverFlags.Parse(flag.Args()[1:]); err != nil {
+			logger.FatalLog().Str("when", "parse server flags").Err(err).Msg("incorrect server flags")
 		}
-		defer func() {
-			if err := connMnr.Close(); err != nil {
-				logger.FatalLog().Str("when", "close connect DB").Err(err).Msg("failed to close DB")
+
+		var siteRepo repository.SiteRepository
+		var statusRepo repository.StatusRepository
+		if *ephemeral {
+			logger.WarnLog().Str("when", "starting server").
+				Msg("ephemeral mode, sites and statuses are lost on exit")
+			if secretsCfg.GetSecretsKeyFile() != "" {
+				logger.WarnLog().Str("when", "starting server").
+					Msg("secrets are stored in DB and not available in ephemeral mode")
+			}
+			siteRepo, statusRepo = memory.NewRepositories()
+		} else {
+			connMnr := connect(dbCfg)
+			defer closeConnection(connMnr)
+			logger.InfoLog().Str("when", "starting server").Msg("connecting DB")
+
+			migrator, err := migrations.NewMigrator(connMnr, dbCfg.GetDbDriver())
+			if err != nil {
+				logger.FatalLog().Str("when", "load migrations").Err(err).Msg("failed to load migrations")
+			}
+			if *autoMigrate {
+				if _, err := migrator.Up(ctx); err != nil {
+					logger.FatalLog().Str("when", "migrate DB").Err(err).Msg("failed to migrate DB")
+				}
+			}
+			if err := migrator.Check(ctx); err != nil {
+				logger.FatalLog().Str("when", "check schema").Err(err).Msg("refusing to start")
 			}
-		}()
-		logger.InfoLog().Str("when", "starting server").Msg("connecting DB")
+
+			if secretsCfg.GetSecretsKeyFile() != "" {
+				keyring, err := secretStore.LoadKeyring(secretsCfg.GetSecretsKeyFile())
+				if err != nil {
+					logger.FatalLog().Str("when", "load keys").Err(err).Msg("failed to load secret keys")
+				}
+				siteChecker.SetSecrets(secretStore.NewStore(connMnr, keyring))
+			}
+
+			siteRepo, statusRepo = repositories(connMnr, dbCfg.GetDbDriver())
+		}
 
 		errGroup, errGroupCtx := errgroup.WithContext(ctx)
+		results := writer.NewWriter(writerCfg, statusRepo)
+		if spoolCfg.GetSpoolDir() != "" {
+			resultSpool, err := spool.Open(spoolCfg)
+			if err != nil {
+				logger.FatalLog().Str("when", "open spool").Err(err).Msg("failed to open spool")
+			}
+			defer closeSpool(resultSpool)
+			results.SetSpool(resultSpool)
+		}
 		s := grpc.NewServer()
+		backend := backendMngr.NewBackendManager(siteRepo, statusRepo, results, errGroupCtx, schedCfg, siteChecker)
+		if backend == nil {
+			logger.FatalLog().Str("when", "create backend").Msg("failed to schedule sites")
+		}
+		budgets := budget.NewTracker(budgetCfg, siteRepo, statusRepo, backend.Broker)
+		compactor := retention.NewCompactor(retentionCfg, statusRepo)
 		serve := &server.GRPCServer{
-			Backend: backendMngr.NewBackendManager(connMnr, errGroupCtx),
-			Сonn:    connMnr,
+			Backend:   backend,
+			Sites:     siteRepo,
+			Statuses:  statusRepo,
+			Budgets:   budgets,
+			Retention: compactor,
 		}
 
 		errGroup.Go(func() error {
@@ -87,6 +175,18 @@ func main() {
 			}
 			return nil
 		})
+		errGroup.Go(func() error {
+			return metrics.Serve(metricsCfg, errGroupCtx)
+		})
+		errGroup.Go(func() error {
+			return results.Run(errGroupCtx)
+		})
+		errGroup.Go(func() error {
+			return compactor.Run(errGroupCtx)
+		})
+		errGroup.Go(func() error {
+			return budgets.Run(errGroupCtx)
+		})
 		logger.InfoLog().Str("when", "start server").Msg("server is listening...")
 
 		if err := errGroup.Wait(); err != nil {
@@ -97,6 +197,65 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "migrate":
+		dbCfg := db.DbConfig(cfg)
+		connMnr := connect(dbCfg)
+		defer closeConnection(connMnr)
+
+		migrator, err := migrations.NewMigrator(connMnr, dbCfg.GetDbDriver())
+		if err != nil {
+			logger.FatalLog().Str("when", "load migrations").Err(err).Msg("failed to load migrations")
+		}
+		switch flag.Arg(1) {
+		case "up":
+			err = migrate.ReqUp(ctx, migrator)
+		case "down":
+			err = migrate.ReqDown(ctx, migrator)
+		case "status":
+			err = migrate.ReqStatus(ctx, migrator)
+		default:
+			err = client.IncorrectInput
+			logger.ErrorLog().Str("when", "entering a migrate request").Err(err).
+				Msg("please enter operation (up, down or status)")
+		}
+		if err != nil {
+			logger.ErrorLog().Str("when", "migrate request").Err(err).Msg("failed to migrate")
+			os.Exit(1)
+		}
+
+	case "secrets":
+		dbCfg := db.DbConfig(cfg)
+		secretsCfg := secretStore.SecretsConfig(cfg)
+
+		keyring, err := secretStore.LoadKeyring(secretsCfg.GetSecretsKeyFile())
+		if err != nil {
+			logger.FatalLog().Str("when", "load keys").Err(err).Msg("failed to load secret keys")
+		}
+		connMnr := connect(dbCfg)
+		defer closeConnection(connMnr)
+		store := secretStore.NewStore(connMnr, keyring)
+
+		switch flag.Arg(1) {
+		case "set":
+			err = secrets.ReqSetSecret(ctx, store)
+		case "delete":
+			err = secrets.ReqDeleteSecret(ctx, store)
+		case "list":
+			err = secrets.ReqListSecrets(ctx, store)
+		case "rotate":
+			err = secrets.ReqRotateSecrets(ctx, store)
+		case "move-proxies":
+			err = secrets.ReqMoveProxies(ctx, store)
+		default:
+			err = secrets.IncorrectInput
+			logger.ErrorLog().Str("when", "entering a secrets request").Err(err).
+				Msg("please enter operation (set, delete, list, rotate or move-proxies)")
+		}
+		if err != nil {
+			logger.ErrorLog().Str("when", "secrets request").Err(err).Msg("failed to process secrets")
+			os.Exit(1)
+		}
+
 	case "client":
 		cliCfg := client.CliConfig(cfg)
 		cli, err := client.StartClient(cliCfg)
@@ -130,20 +289,111 @@ func main() {
 			if err := client.ReqDeleteSite(ctx, cli); err != nil {
 				logger.FatalLog().Str("when", "delete site").Err(err).Msg("failed to delete site")
 			}
+		case "import":
+			logger.InfoLog().Str("when", "start client").Msg("importing sites")
+			if err := client.ReqImportSites(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "import sites").Err(err).Msg("failed to import sites")
+			}
+		case "deleted":
+			logger.InfoLog().Str("when", "start client").Msg("getting list of deleted sites")
+			if err := client.ReqListDeleted(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "get list of deleted sites").Err(err).
+					Msg("failed to get list of deleted sites")
+			}
+		case "restore":
+			logger.InfoLog().Str("when", "start client").Msg("restoring site")
+			if err := client.ReqRestoreSite(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "restore site").Err(err).Msg("failed to restore site")
+			}
+		case "purge":
+			logger.InfoLog().Str("when", "start client").Msg("purging site")
+			if err := client.ReqPurgeSite(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "purge site").Err(err).Msg("failed to purge site")
+			}
+		case "audit":
+			logger.InfoLog().Str("when", "start client").Msg("getting list of events")
+			if err := client.ReqListAuditEvents(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "get list of events").Err(err).
+					Msg("failed to get list of events")
+			}
 		case "status":
 			logger.InfoLog().Str("when", "start client").Msg("getting list of statuses")
 			if err := client.ReqReadStatus(ctx, cli); err != nil {
 				logger.FatalLog().Str("when", "get list of statuses").Err(err).
 					Msg("failed to get list of statuses")
 			}
+		case "history":
+			logger.InfoLog().Str("when", "start client").Msg("getting history of states")
+			if err := client.ReqReadHistory(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "get history of states").Err(err).
+					Msg("failed to get history of states")
+			}
+		case "uptime":
+			logger.InfoLog().Str("when", "start client").Msg("getting uptime report")
+			if err := client.ReqUptimeReport(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "get uptime report").Err(err).Msg("failed to get uptime report")
+			}
+		case "watch":
+			logger.InfoLog().Str("when", "start client").Msg("watching checks")
+			if err := client.ReqWatchStatus(ctx, cli); err != nil {
+				logger.FatalLog().Str("when", "watch checks").Err(err).Msg("failed to watch checks")
+			}
+		case "slo":
+			switch flag.Arg(2) {
+			case "create":
+				err = client.ReqCreateObjective(ctx, cli)
+			case "list":
+				err = client.ReqListObjectives(ctx, cli)
+			case "delete":
+				err = client.ReqDeleteObjective(ctx, cli)
+			default:
+				err = client.IncorrectInput
+				logger.ErrorLog().Str("when", "entering an objectives request").Err(err).
+					Msg("please enter operation (create, list or delete)")
+			}
+			if err != nil {
+				logger.FatalLog().Str("when", "objectives request").Err(err).Msg("failed to process objectives")
+			}
 		default:
 			err := client.IncorrectInput
 			logger.FatalLog().Str("when", "entering a sites request").Err(err).
-				Msg("please enter operation (create, read, update, delete or status)")
+				Msg("please enter operation (create, import, read, list, update, delete, deleted, restore, purge, audit, status, history, uptime, watch or slo)")
 		}
 	default:
 		err := client.IncorrectInput
 		logger.FatalLog().Str("when", "starting server").Err(err).
-			Msg("enter \"server\" or \"client\" operation")
+			Msg("enter \"server\", \"client\", \"migrate\" or \"secrets\" operation")
+	}
+}
+
+func connect(dbCfg db.DbConfig) *db.ConnectionManager {
+	logger := logging.NewLoggers("cmd", "connect")
+	connMnr := db.NewConnectionManager()
+	if err := connMnr.Connect(dbCfg); err != nil {
+		logger.FatalLog().Str("when", "connect DB").Err(err).Msg("failed to connect DB")
+	}
+	return connMnr
+}
+
+// repositories returns the storage of the DB driver
+func repositories(connMnr *db.ConnectionManager, driver string) (repository.SiteRepository,
+	repository.StatusRepository) {
+	if driver == db.DriverSqlite {
+		return sqlite.NewSiteRepository(connMnr), sqlite.NewStatusRepository(connMnr)
+	}
+	return postgres.NewSiteRepository(connMnr), postgres.NewStatusRepository(connMnr)
+}
+
+func closeSpool(resultSpool *spool.Spool) {
+	logger := logging.NewLoggers("cmd", "closeSpool")
+	if err := resultSpool.Close(); err != nil {
+		logger.ErrorLog().Str("when", "close spool").Err(err).Msg("failed to close spool")
+	}
+}
+
+func closeConnection(connMnr *db.ConnectionManager) {
+	logger := logging.NewLoggers("cmd", "closeConnection")
+	if err := connMnr.Close(); err != nil {
+		logger.FatalLog().Str("when", "close connect DB").Err(err).Msg("failed to close DB")
 	}
 }
@@ -0,0 +1,52 @@
+package migrate
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/migrations"
+	"context"
+	"fmt"
+	"time"
+)
+
+func ReqUp(ctx context.Context, m *migrations.Migrator) error {
+	logger := logging.NewLoggers("migrate", "reqUp")
+	count, err := m.Up(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Int("applied", count).
+			Msg("unable to apply migrations")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int("applied", count).Msg("done")
+	return nil
+}
+
+func ReqDown(ctx context.Context, m *migrations.Migrator) error {
+	logger := logging.NewLoggers("migrate", "reqDown")
+	migration, err := m.Down(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to roll back migration")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int64("version", migration.Version).
+		Str("name", migration.Name).Msg("done")
+	return nil
+}
+
+func ReqStatus(ctx context.Context, m *migrations.Migrator) error {
+	logger := logging.NewLoggers("migrate", "reqStatus")
+	list, err := m.Status(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to get migrations")
+		return err
+	}
+	statusStr := ""
+	for _, migration := range list {
+		applied := "pending"
+		if migration.Applied {
+			applied = "applied at " + migration.AppliedAt.Format(time.RFC3339)
+		}
+		statusStr += fmt.Sprintf("%d_%s: %s; ", migration.Version, migration.Name, applied)
+	}
+	logger.InfoLog().Str("request", "processed successfully").Str("migrations", statusStr).Msg("done")
+	return nil
+}
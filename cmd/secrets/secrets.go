@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"CheckUrls/pkg/logging"
+	store "CheckUrls/pkg/secrets"
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+var IncorrectInput = fmt.Errorf("incorrect input of arguments")
+
+func ReqSetSecret(ctx context.Context, s *store.Store) error {
+	logger := logging.NewLoggers("secrets", "reqSet")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	if flag.NArg() != 3 {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"set <name>\" and the value in stdin")
+		return err
+	}
+
+	logger.DebugLog().Msg("reading value from stdin")
+	value, err := readValue(os.Stdin)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to read value")
+		return err
+	}
+
+	if err := s.Set(ctx, flag.Arg(2), logging.Secret(value)); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to set secret")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Str("name", flag.Arg(2)).Msg("done")
+	return nil
+}
+
+// readValue reads the value up to the end of r, so multi-line values such as
+// PEM keys are kept whole, only the newline ending the input is dropped
+func readValue(r io.Reader) (string, error) {
+	value, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	value = bytes.TrimSuffix(value, []byte("\n"))
+	value = bytes.TrimSuffix(value, []byte("\r"))
+	return string(value), nil
+}
+
+func ReqDeleteSecret(ctx context.Context, s *store.Store) error {
+	logger := logging.NewLoggers("secrets", "reqDelete")
+	logger.DebugLog().Msg("checking for the correctness of arguments")
+	if flag.NArg() != 3 {
+		err := IncorrectInput
+		logger.ErrorLog().Err(err).Str("request", "failed to process").
+			Msg("please enter \"delete <name>\"")
+		return err
+	}
+
+	if err := s.Delete(ctx, flag.Arg(2)); err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to delete secret")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Str("name", flag.Arg(2)).Msg("done")
+	return nil
+}
+
+func ReqListSecrets(ctx context.Context, s *store.Store) error {
+	logger := logging.NewLoggers("secrets", "reqList")
+	names, err := s.List(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to list secrets")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Strs("secrets", names).Msg("done")
+	return nil
+}
+
+func ReqRotateSecrets(ctx context.Context, s *store.Store) error {
+	logger := logging.NewLoggers("secrets", "reqRotate")
+	count, err := s.Rotate(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Int("rotated", count).
+			Msg("unable to rotate secrets")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int("rotated", count).Msg("done")
+	return nil
+}
+
+func ReqMoveProxies(ctx context.Context, s *store.Store) error {
+	logger := logging.NewLoggers("secrets", "reqMoveProxies")
+	count, err := s.MoveProxies(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("request", "failed to process").Msg("unable to move proxy passwords")
+		return err
+	}
+	logger.InfoLog().Str("request", "processed successfully").Int("moved", count).Msg("done")
+	return nil
+}
@@ -2,16 +2,69 @@ package server
 
 import (
 	"CheckUrls/pkg/backendMngr"
-	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/broker"
+	"CheckUrls/pkg/budget"
 	"CheckUrls/pkg/logging"
 	"CheckUrls/pkg/proto"
+	"CheckUrls/pkg/report"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/audit"
+	"CheckUrls/pkg/repository/labels"
 	"CheckUrls/pkg/repository/sites"
+	"CheckUrls/pkg/repository/slo"
 	statuses "CheckUrls/pkg/repository/status"
+	"CheckUrls/pkg/retention"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
 	"net"
+	"strings"
+	"time"
+)
+
+// ActorKey is the metadata key of the user who makes the request
+const ActorKey = "actor"
+
+// maxAuditEvents limits the events returned by one request
+const maxAuditEvents = 1000
+
+// maxUpdateRetries limits the updates of the site by the mask, the site is read
+// again when it was changed after the read and the request has no version
+const maxUpdateRetries = 3
+
+// watchBuffer is the number of checks kept for the client of WatchStatus,
+// the client is disconnected when it falls behind by more checks
+const watchBuffer = 1000
+
+// importBatchSize is the number of imported sites saved in one transaction
+const importBatchSize = 100
+
+// results of the imported sites
+const (
+	importCreated = "created"
+	importUpdated = "updated"
+	importFailed  = "failed"
+)
+
+// maxReportWindow limits the window of the uptime report,
+// its checks are read by reportChunk
+const (
+	maxReportWindow = 366 * 24 * time.Hour
+	reportChunk     = 24 * time.Hour
+)
+
+// defaultPageSize and maxPageSize limit the sites returned by one request
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
 )
 
 type ServerConfig interface {
@@ -21,27 +74,44 @@ type ServerConfig interface {
 // GRPCServer ...
 type GRPCServer struct {
 	proto.UnimplementedSitesServiceServer
-	Backend *backendMngr.BackendManager
-	log     *logging.Loggers
-	Сonn    *db.ConnectionManager
+	Backend  *backendMngr.BackendManager
+	log      *logging.Loggers
+	Sites    repository.SiteRepository
+	Statuses repository.StatusRepository
+	// Budgets tracks the error budgets of the objectives
+	Budgets *budget.Tracker
+	// Retention compacts the raw states, the uptime report is built
+	// by the raw states only, nil if they are kept forever
+	Retention *retention.Compactor
 }
 
 // Create site...
 func (g *GRPCServer) Create(ctx context.Context, request *proto.CreateRequestSite) (*proto.CreateResponseSite, error) {
 	g.log = logging.NewLoggers("server", "create")
 	g.log.DebugLog().Msg("getting the params for operation with the site")
-	site := sites.Site{
-		Url:       request.GetSites().Url,
-		Frequency: request.GetSites().Frequency,
+	site := siteFromProto(request.GetSites())
+	site.Id = 0
+	if err := site.Validate(); err != nil {
+		g.log.WarnLog().Str("when", "validate site").Err(err).Msg("incorrect site")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	g.log.DebugLog().Msg("creating site and forming a response")
-	if err := sites.CreateSites(g.Сonn, &site); err != nil {
-		if err == sites.ErrSitesNotFound {
-			err = status.Error(codes.NotFound, "unable to create site")
+	create, operation := g.Sites.Create, audit.OperationCreate
+	if request.GetRestore() {
+		create, operation = g.Sites.Restore, audit.OperationRestore
+	}
+	if err := create(ctx, &site, newEvent(ctx, operation)); err != nil {
+		switch err {
+		case sites.ErrSitesNotFound:
+			err = status.Error(codes.NotFound, "no deleted site with the url")
 			g.log.WarnLog().Str("when", "create site").Str("request", "failed to process").
 				Err(err).Msg("unable to create site")
-		} else {
+		case sites.ErrSiteExists:
+			err = status.Error(codes.AlreadyExists, "the url is checked by another site")
+			g.log.WarnLog().Str("when", "create site").Str("request", "failed to process").
+				Err(err).Msg("unable to create site")
+		default:
 			err = status.Error(codes.Unknown, "unable to create site")
 			g.log.ErrorLog().Str("when", "create site").Str("request", "failed to process").
 				Err(err).Msg("unable to create site")
@@ -63,7 +133,7 @@ func (g *GRPCServer) Read(ctx context.Context, request *proto.ReadRequestSite) (
 	site := sites.Site{Id: request.GetId()}
 
 	g.log.DebugLog().Msg("getting site and forming a response")
-	if err := sites.ReadSites(g.Сonn, &site); err != nil {
+	if err := g.Sites.Read(ctx, &site); err != nil {
 		if err == sites.ErrSitesNotFound {
 			err = status.Error(codes.NotFound, "unable to get site")
 			g.log.WarnLog().Str("when", "get site").Str("request", "failed to process").
@@ -76,24 +146,55 @@ func (g *GRPCServer) Read(ctx context.Context, request *proto.ReadRequestSite) (
 		return nil, err
 	}
 
-	siteProto := proto.Site{
-		Id:        site.Id,
-		Url:       site.Url,
-		Frequency: site.Frequency,
-	}
-
 	g.log.DebugLog().Msg("sending response")
 	return &proto.ReadResponseSite{
-		Sites: &siteProto,
+		Sites: siteToProto(&site),
 	}, nil
 }
 
 // ReadAll is list of sites...
 func (g *GRPCServer) ReadAll(ctx context.Context, request *proto.ReadAllRequestSite) (*proto.ReadAllResponseSite, error) {
 	g.log = logging.NewLoggers("server", "readAll")
+	g.log.DebugLog().Msg("getting the params for operation with the sites")
+	selector, err := labels.Parse(request.GetSelector())
+	if err != nil {
+		g.log.WarnLog().Str("when", "parse selector").Err(err).Msg("incorrect selector")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	size := request.GetPageSize()
+	if size == 0 {
+		size = defaultPageSize
+	}
+	if size < 0 || size > maxPageSize {
+		g.log.WarnLog().Str("when", "validate filter").Int64("page_size", size).Msg("incorrect page size")
+		return nil, status.Errorf(codes.InvalidArgument, "page size must be from 0 to %d", maxPageSize)
+	}
+	filter := sites.Filter{
+		Selector:     selector,
+		UrlContains:  request.GetUrlContains(),
+		State:        request.GetState(),
+		MinFrequency: request.GetMinFrequency(),
+		MaxFrequency: request.GetMaxFrequency(),
+		// the site after the page shows whether there is the next page
+		Limit: size + 1,
+	}
+	if filter.Sort, filter.Desc, err = parseOrder(request.GetOrderBy()); err != nil {
+		g.log.WarnLog().Str("when", "parse order").Err(err).Msg("incorrect order")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := filter.Validate(); err != nil {
+		g.log.WarnLog().Str("when", "validate filter").Err(err).Msg("incorrect filter")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if request.GetPageToken() != "" {
+		if filter.After, err = decodePageToken(request.GetPageToken(), filter); err != nil {
+			g.log.WarnLog().Str("when", "decode page token").Err(err).Msg("incorrect page token")
+			return nil, status.Error(codes.InvalidArgument, "incorrect page token")
+		}
+	}
 
 	g.log.DebugLog().Msg("getting list of sites and forming a response")
-	list, err := sites.ReadAllSites(g.Сonn)
+	list, err := g.Sites.ReadAll(ctx, filter)
 	if err != nil {
 		if err == sites.ErrSitesNotFound {
 			err = status.Error(codes.NotFound, "unable to get list")
@@ -107,38 +208,117 @@ func (g *GRPCServer) ReadAll(ctx context.Context, request *proto.ReadAllRequestS
 		return nil, err
 	}
 
-	listProto := make([]*proto.Site, 0, len(list))
+	res := &proto.ReadAllResponseSite{}
+	if int64(len(list)) > size {
+		list = list[:size]
+		res.NextPageToken = encodePageToken(filter, list[len(list)-1])
+	}
+	res.Sites = make([]*proto.Site, 0, len(list))
 	for _, site := range list {
-		ls := new(proto.Site)
-		ls.Id = site.Id
-		ls.Url = site.Url
-		ls.Frequency = site.Frequency
-		listProto = append(listProto, ls)
+		res.Sites = append(res.Sites, siteToProto(site))
 	}
 
 	g.log.DebugLog().Msg("sending a response")
-	return &proto.ReadAllResponseSite{Sites: listProto}, nil
+	return res, nil
+}
+
+// parseOrder parses the order like "url" or "created desc"
+func parseOrder(orderBy string) (string, bool, error) {
+	fields := strings.Fields(orderBy)
+	switch {
+	case len(fields) == 0:
+		return "", false, nil
+	case len(fields) == 1:
+		return fields[0], false, nil
+	case len(fields) == 2 && (fields[1] == "asc" || fields[1] == "desc"):
+		return fields[0], fields[1] == "desc", nil
+	}
+	return "", false, fmt.Errorf("%w: incorrect order %q", sites.ErrIncorrectFilter, orderBy)
+}
+
+// pageToken is the last site of the page, the token is
+// accepted only with the order of the page
+type pageToken struct {
+	Sort      string    `json:"sort"`
+	Desc      bool      `json:"desc,omitempty"`
+	Id        int64     `json:"id"`
+	Url       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func encodePageToken(filter sites.Filter, last *sites.Site) string {
+	token := pageToken{Sort: filter.Sort, Desc: filter.Desc, Id: last.Id}
+	switch filter.Sort {
+	case sites.SortUrl:
+		token.Url = last.Url
+	case sites.SortCreated:
+		token.CreatedAt = last.CreatedAt
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		// the fields are strings, numbers, a boolean and a time
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(encoded string, filter sites.Filter) (*sites.Site, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var token pageToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	if token.Sort != filter.Sort || token.Desc != filter.Desc {
+		return nil, fmt.Errorf("token of the order %q is used with the order %q", token.Sort, filter.Sort)
+	}
+	return &sites.Site{Id: token.Id, Url: token.Url, CreatedAt: token.CreatedAt}, nil
 }
 
 // Update site...
 func (g *GRPCServer) Update(ctx context.Context, request *proto.UpdateRequestSite) (*proto.UpdateResponseSite, error) {
 	g.log = logging.NewLoggers("server", "update")
 	g.log.DebugLog().Msg("getting the params for operation with the site")
-	siteProto := request.GetSites()
-	site := sites.Site{
-		Id:        siteProto.GetId(),
-		Url:       siteProto.GetUrl(),
-		Frequency: siteProto.GetFrequency(),
-		Deleted:   false,
-	}
+	paths := request.GetUpdateMask().GetPaths()
+	site := siteFromProto(request.GetSites())
+	var err error
+	for retry := 0; ; retry++ {
+		if len(paths) > 0 {
+			if site, err = g.maskedSite(ctx, request.GetSites(), paths); err != nil {
+				return nil, err
+			}
+		}
+		if err = site.Validate(); err != nil {
+			g.log.WarnLog().Str("when", "validate site").Err(err).Msg("incorrect site")
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 
-	g.log.DebugLog().Msg("update site and forming a response")
-	if err := sites.UpdateSites(g.Сonn, &site); err != nil {
-		if err == sites.ErrSitesNotFound {
+		g.log.DebugLog().Msg("update site and forming a response")
+		err = g.Sites.Update(ctx, &site, newEvent(ctx, audit.OperationUpdate))
+		// the site read for the mask was changed by another request
+		if err != sites.ErrVersionConflict || len(paths) == 0 ||
+			request.GetSites().GetVersion() != 0 || retry == maxUpdateRetries {
+			break
+		}
+		g.log.DebugLog().Int64("site_id", site.Id).Msg("site was changed, read it again")
+	}
+	if err != nil {
+		switch err {
+		case sites.ErrSitesNotFound:
 			err = status.Error(codes.NotFound, "unable to update")
 			g.log.WarnLog().Str("when", "update site").Str("request", "failed to process").
 				Err(err).Msg("unable to update  site")
-		} else {
+		case sites.ErrSiteExists:
+			err = status.Error(codes.AlreadyExists, "the url is checked by another site")
+			g.log.WarnLog().Str("when", "update site").Str("request", "failed to process").
+				Err(err).Msg("unable to update site")
+		case sites.ErrVersionConflict:
+			err = status.Error(codes.Aborted, "the site was changed, read it and retry")
+			g.log.WarnLog().Str("when", "update site").Str("request", "failed to process").
+				Err(err).Msg("unable to update site")
+		default:
 			err = status.Error(codes.Unknown, "unable to update")
 			g.log.WarnLog().Str("when", "update site").Str("request", "failed to process").
 				Err(err).Msg("unable to update site")
@@ -150,7 +330,81 @@ func (g *GRPCServer) Update(ctx context.Context, request *proto.UpdateRequestSit
 	g.Backend.CreateOrUpdate(&site)
 
 	g.log.DebugLog().Msg("sending a response")
-	return &proto.UpdateResponseSite{Updated: site.Id}, nil
+	return &proto.UpdateResponseSite{Updated: site.Id, Sites: siteToProto(&site)}, nil
+}
+
+// maskedSite reads the site and changes the fields listed by the paths,
+// the version of the read site is kept to detect the changes after the read
+func (g *GRPCServer) maskedSite(ctx context.Context, from *proto.Site, paths []string) (sites.Site, error) {
+	site := sites.Site{Id: from.GetId()}
+	if err := g.Sites.Read(ctx, &site); err != nil {
+		g.log.WarnLog().Str("when", "read site").Str("request", "failed to process").
+			Err(err).Msg("unable to update site")
+		if err == sites.ErrSitesNotFound {
+			return site, status.Error(codes.NotFound, "unable to update")
+		}
+		return site, status.Error(codes.Unknown, "unable to update")
+	}
+	if from.GetVersion() != 0 && from.GetVersion() != site.Version {
+		g.log.WarnLog().Str("when", "read site").Int64("version", site.Version).Msg("site has another version")
+		return site, status.Error(codes.Aborted, "the site was changed, read it and retry")
+	}
+	if err := applyMask(&site, from, paths); err != nil {
+		g.log.WarnLog().Str("when", "apply mask").Err(err).Msg("incorrect update mask")
+		return site, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return site, nil
+}
+
+// applyMask copies the fields listed by the paths from the proto site,
+// the path labels.<key> sets the label or removes it when it is absent
+func applyMask(site *sites.Site, from *proto.Site, paths []string) error {
+	for _, path := range paths {
+		switch path {
+		case "url":
+			site.Url = from.GetUrl()
+		case "frequency":
+			site.Frequency = from.GetFrequency()
+		case "check_mode":
+			site.CheckMode = from.GetCheckMode()
+		case "proxy":
+			site.Proxy = from.GetProxy()
+		case "proxy_secret":
+			site.ProxySecret = from.GetProxySecret()
+		case "client_cert":
+			site.ClientCert = from.GetClientCert()
+		case "client_key":
+			site.ClientKey = from.GetClientKey()
+		case "ca_bundle":
+			site.CaBundle = from.GetCaBundle()
+		case "skip_verify":
+			site.SkipVerify = from.GetSkipVerify()
+		case "auth_secret":
+			site.AuthSecret = from.GetAuthSecret()
+		case "basic_auth_user":
+			site.BasicAuthUser = from.GetBasicAuthUser()
+		case "basic_auth_secret":
+			site.BasicAuthSecret = from.GetBasicAuthSecret()
+		case "labels":
+			site.Labels = from.GetLabels()
+		default:
+			key := strings.TrimPrefix(path, "labels.")
+			if key == path || key == "" {
+				return fmt.Errorf("unable to update the field %q", path)
+			}
+			changed := make(map[string]string, len(site.Labels)+1)
+			for k, v := range site.Labels {
+				changed[k] = v
+			}
+			if value, ok := from.GetLabels()[key]; ok {
+				changed[key] = value
+			} else {
+				delete(changed, key)
+			}
+			site.Labels = changed
+		}
+	}
+	return nil
 }
 
 // Delete site...
@@ -158,13 +412,13 @@ func (g *GRPCServer) Delete(ctx context.Context, request *proto.DeleteRequestSit
 	g.log = logging.NewLoggers("server", "delete")
 	g.log.DebugLog().Msg("getting the params for operation with the site")
 	site := sites.Site{Id: request.GetId()}
-	if err := sites.ReadSites(g.Сonn, &site); err != nil {
+	if err := g.Sites.Read(ctx, &site); err != nil {
 		err = status.Error(codes.NotFound, "unable to delete")
 		return nil, err
 	}
 
 	g.log.DebugLog().Msg("deleting site and forming a response")
-	if err := sites.DeleteSites(g.Сonn, &site); err != nil {
+	if err := g.Sites.Delete(ctx, &site, newEvent(ctx, audit.OperationDelete)); err != nil {
 		if err == sites.ErrSitesNotFound {
 			err = status.Error(codes.NotFound, "unable to delete")
 			g.log.WarnLog().Str("when", "delete site").Str("request", "failed to process").
@@ -184,14 +438,335 @@ func (g *GRPCServer) Delete(ctx context.Context, request *proto.DeleteRequestSit
 	return &proto.DeleteResponseSite{Deleted: site.Id}, nil
 }
 
+// ListDeleted is list of deleted sites...
+func (g *GRPCServer) ListDeleted(ctx context.Context, request *proto.ListDeletedRequestSite) (*proto.ListDeletedResponseSite, error) {
+	g.log = logging.NewLoggers("server", "listDeleted")
+
+	g.log.DebugLog().Msg("getting list of deleted sites and forming a response")
+	list, err := g.Sites.ListDeleted(ctx)
+	if err != nil {
+		err = status.Error(codes.Unknown, "unable to get list")
+		g.log.ErrorLog().Str("when", "get list of deleted sites").Str("request", "failed to process").
+			Err(err).Msg("unable to get list of deleted sites")
+		return nil, err
+	}
+
+	listProto := make([]*proto.Site, 0, len(list))
+	for _, site := range list {
+		listProto = append(listProto, siteToProto(site))
+	}
+
+	g.log.DebugLog().Msg("sending a response")
+	return &proto.ListDeletedResponseSite{Sites: listProto}, nil
+}
+
+// Restore deleted site...
+func (g *GRPCServer) Restore(ctx context.Context, request *proto.RestoreRequestSite) (*proto.RestoreResponseSite, error) {
+	g.log = logging.NewLoggers("server", "restore")
+	g.log.DebugLog().Msg("getting the params for operation with the site")
+	site := sites.Site{Id: request.GetId()}
+
+	g.log.DebugLog().Msg("restoring site and forming a response")
+	if err := g.Sites.Undelete(ctx, &site, newEvent(ctx, audit.OperationRestore)); err != nil {
+		switch err {
+		case sites.ErrSitesNotFound:
+			err = status.Error(codes.NotFound, "no deleted site with the id")
+			g.log.WarnLog().Str("when", "restore site").Str("request", "failed to process").
+				Err(err).Msg("unable to restore site")
+		case sites.ErrSiteExists:
+			err = status.Error(codes.AlreadyExists, "the url is checked by another site")
+			g.log.WarnLog().Str("when", "restore site").Str("request", "failed to process").
+				Err(err).Msg("unable to restore site")
+		default:
+			err = status.Error(codes.Unknown, "unable to restore")
+			g.log.ErrorLog().Str("when", "restore site").Str("request", "failed to process").
+				Err(err).Msg("unable to restore site")
+		}
+		return nil, err
+	}
+
+	g.log.DebugLog().Msg("starting check urls")
+	g.Backend.CreateOrUpdate(&site)
+
+	g.log.DebugLog().Msg("sending response")
+	return &proto.RestoreResponseSite{Restored: site.Id}, nil
+}
+
+// Purge deleted site with its history...
+func (g *GRPCServer) Purge(ctx context.Context, request *proto.PurgeRequestSite) (*proto.PurgeResponseSite, error) {
+	g.log = logging.NewLoggers("server", "purge")
+	g.log.DebugLog().Msg("getting the params for operation with the site")
+	site := sites.Site{Id: request.GetId()}
+	switch err := g.Sites.Read(ctx, &sites.Site{Id: site.Id}); err {
+	case nil:
+		err = status.Error(codes.FailedPrecondition, "the site is checked, delete it before purge")
+		g.log.WarnLog().Str("when", "purge site").Str("request", "failed to process").
+			Err(err).Msg("unable to purge site")
+		return nil, err
+	case sites.ErrSitesNotFound:
+	default:
+		g.log.ErrorLog().Str("when", "get site").Str("request", "failed to process").
+			Err(err).Msg("unable to purge site")
+		return nil, status.Error(codes.Unknown, "unable to purge")
+	}
+
+	objectives, err := g.Sites.ListObjectives(ctx)
+	if err != nil {
+		g.log.ErrorLog().Str("when", "list objectives").Str("request", "failed to process").
+			Err(err).Msg("unable to purge site")
+		return nil, status.Error(codes.Unknown, "unable to purge")
+	}
+
+	g.log.DebugLog().Msg("purging site and forming a response")
+	event := newEvent(ctx, audit.OperationPurge)
+	event.SiteId = site.Id
+	if err := g.Sites.Purge(ctx, &site, event); err != nil {
+		if err == sites.ErrSitesNotFound {
+			err = status.Error(codes.NotFound, "unable to purge")
+			g.log.WarnLog().Str("when", "purge site").Str("request", "failed to process").
+				Err(err).Msg("unable to purge site")
+		} else {
+			err = status.Error(codes.Unknown, "unable to purge")
+			g.log.ErrorLog().Str("when", "purge site").Str("request", "failed to process").
+				Err(err).Msg("unable to purge site")
+		}
+		return nil, err
+	}
+	g.log.InfoLog().Str("actor", event.Actor).Int64("site_id", site.Id).Msg("site was purged")
+	g.Backend.Purge(&site)
+	for _, o := range objectives {
+		if o.SiteId == site.Id {
+			g.Budgets.Remove(o)
+		}
+	}
+
+	g.log.DebugLog().Msg("sending response")
+	return &proto.PurgeResponseSite{Purged: site.Id}, nil
+}
+
+// ListAuditEvents is list of changes of sites...
+func (g *GRPCServer) ListAuditEvents(ctx context.Context, request *proto.ListRequestAudit) (*proto.ListResponseAudit, error) {
+	g.log = logging.NewLoggers("server", "listAuditEvents")
+	g.log.DebugLog().Msg("getting the params for operation with the events")
+	filter := audit.Filter{
+		SiteId:    request.GetSiteId(),
+		Actor:     request.GetActor(),
+		Operation: request.GetOperation(),
+		Limit:     request.GetLimit(),
+	}
+	if request.GetFrom() != nil {
+		filter.From = request.GetFrom().AsTime()
+	}
+	if request.GetTo() != nil {
+		filter.To = request.GetTo().AsTime()
+	}
+	if filter.Limit < 0 || filter.Limit > maxAuditEvents {
+		g.log.WarnLog().Str("when", "validate filter").Int64("limit", filter.Limit).Msg("incorrect limit")
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be from 0 to %d", maxAuditEvents)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		g.log.WarnLog().Str("when", "validate filter").Msg("incorrect range")
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	g.log.DebugLog().Msg("getting list of events and forming a response")
+	events, err := g.Sites.ListEvents(ctx, filter)
+	if err != nil {
+		err = status.Error(codes.Unknown, "unable to get events")
+		g.log.ErrorLog().Str("when", "get list of events").Str("request", "failed to process").
+			Err(err).Msg("unable to get list of events")
+		return nil, err
+	}
+
+	list := make([]*proto.AuditEvent, 0, len(events))
+	for _, e := range events {
+		list = append(list, &proto.AuditEvent{
+			Id:        e.Id,
+			Date:      timestamppb.New(e.Date),
+			Actor:     e.Actor,
+			Address:   e.Address,
+			Operation: e.Operation,
+			SiteId:    e.SiteId,
+			Before:    e.Before,
+			After:     e.After,
+		})
+	}
+
+	g.log.DebugLog().Msg("sending a response")
+	return &proto.ListResponseAudit{Events: list}, nil
+}
+
+// ImportSites creates or updates the streamed sites by their urls like Create.
+// The valid sites are saved by batches in transactions, a failed batch is saved
+// site by site, so only the sites which can not be saved fail.
+func (g *GRPCServer) ImportSites(stream proto.SitesService_ImportSitesServer) error {
+	// the stream lasts while other requests are served, so it has its own logger
+	logger := logging.NewLoggers("server", "importSites")
+	ctx := stream.Context()
+	res := &proto.ImportResponseSite{}
+	batch := make([]*sites.Site, 0, importBatchSize)
+	results := make([]*proto.ImportResult, 0, importBatchSize)
+
+	logger.DebugLog().Msg("receiving sites")
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.WarnLog().Str("when", "receive site").Err(err).Msg("unable to receive site")
+			return err
+		}
+		site := siteFromProto(request.GetSites())
+		site.Id = 0
+		result := &proto.ImportResult{Index: int64(len(res.Results)), Url: site.Url}
+		res.Results = append(res.Results, result)
+		if err := site.Validate(); err != nil {
+			logger.WarnLog().Str("when", "validate site").Int64("index", result.Index).Err(err).
+				Msg("incorrect site")
+			result.Result, result.Reason = importFailed, err.Error()
+			continue
+		}
+		batch, results = append(batch, &site), append(results, result)
+		if len(batch) == importBatchSize {
+			g.importBatch(ctx, logger, batch, results)
+			batch, results = batch[:0], results[:0]
+		}
+	}
+	g.importBatch(ctx, logger, batch, results)
+
+	for _, result := range res.Results {
+		switch result.Result {
+		case importCreated:
+			res.Created++
+		case importUpdated:
+			res.Updated++
+		default:
+			res.Failed++
+		}
+	}
+	name, address := actor(ctx)
+	logger.InfoLog().Str("actor", name).Str("address", address).Int64("created", res.Created).Int64("updated", res.Updated).
+		Int64("failed", res.Failed).Msg("sites were imported")
+
+	logger.DebugLog().Msg("sending response")
+	return stream.SendAndClose(res)
+}
+
+// importBatch saves the sites in one transaction and sets their results,
+// when the transaction fails, the sites are saved one by one
+func (g *GRPCServer) importBatch(ctx context.Context, logger *logging.Loggers, batch []*sites.Site,
+	results []*proto.ImportResult) {
+	if len(batch) == 0 {
+		return
+	}
+	created, err := g.Sites.CreateBatch(ctx, batch, importEvents(ctx, len(batch)))
+	if err != nil {
+		logger.WarnLog().Str("when", "create sites").Int("sites", len(batch)).Err(err).
+			Msg("unable to create sites, creating them one by one")
+		created = make([]bool, len(batch))
+	}
+	for i, site := range batch {
+		if err != nil {
+			one, err := g.Sites.CreateBatch(ctx, batch[i:i+1], importEvents(ctx, 1))
+			if err != nil {
+				logger.ErrorLog().Str("when", "create site").Int64("index", results[i].Index).Err(err).
+					Msg("unable to create site")
+				results[i].Result, results[i].Reason = importFailed, "unable to create site"
+				continue
+			}
+			created[i] = one[0]
+		}
+		results[i].Id, results[i].Result = site.Id, importUpdated
+		if created[i] {
+			results[i].Result = importCreated
+		}
+		g.Backend.CreateOrUpdate(site)
+	}
+}
+
+// importEvents returns the events of the imported sites
+func importEvents(ctx context.Context, count int) []*audit.Event {
+	events := make([]*audit.Event, count)
+	for i := range events {
+		events[i] = newEvent(ctx, audit.OperationCreate)
+	}
+	return events
+}
+
+// newEvent returns the event of the operation made by the caller
+func newEvent(ctx context.Context, operation string) *audit.Event {
+	name, address := actor(ctx)
+	return &audit.Event{Date: time.Now().UTC(), Actor: name, Address: address, Operation: operation}
+}
+
+// actor returns the name of the user who makes the request as the client
+// reports it, "unknown" without the name, and the address of the client
+func actor(ctx context.Context) (name, address string) {
+	name = "unknown"
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(ActorKey); len(values) != 0 && values[0] != "" {
+			name = values[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		address = p.Addr.String()
+	}
+	return name, address
+}
+
+func siteFromProto(site *proto.Site) sites.Site {
+	return sites.Site{
+		Id:              site.GetId(),
+		Url:             site.GetUrl(),
+		Frequency:       site.GetFrequency(),
+		CheckMode:       site.GetCheckMode(),
+		Proxy:           site.GetProxy(),
+		ProxySecret:     site.GetProxySecret(),
+		ClientCert:      site.GetClientCert(),
+		ClientKey:       site.GetClientKey(),
+		CaBundle:        site.GetCaBundle(),
+		SkipVerify:      site.GetSkipVerify(),
+		AuthSecret:      site.GetAuthSecret(),
+		BasicAuthUser:   site.GetBasicAuthUser(),
+		BasicAuthSecret: site.GetBasicAuthSecret(),
+		Labels:          site.GetLabels(),
+		Version:         site.GetVersion(),
+	}
+}
+
+func siteToProto(site *sites.Site) *proto.Site {
+	return &proto.Site{
+		Id:              site.Id,
+		Url:             site.Url,
+		Frequency:       site.Frequency,
+		CheckMode:       site.CheckMode,
+		Proxy:           site.RedactedProxy(),
+		ProxySecret:     site.ProxySecret,
+		ClientCert:      site.ClientCert,
+		ClientKey:       site.ClientKey,
+		CaBundle:        site.CaBundle,
+		SkipVerify:      site.SkipVerify,
+		AuthSecret:      site.AuthSecret,
+		BasicAuthUser:   site.BasicAuthUser,
+		BasicAuthSecret: site.BasicAuthSecret,
+		Labels:          site.Labels,
+		CreatedAt:       timestamppb.New(site.CreatedAt),
+		Version:         site.Version,
+	}
+}
+
 func (g GRPCServer) ReadStatus(ctx context.Context, req *proto.ReadRequestState) (*proto.StatusResponse, error) {
 	g.log = logging.NewLoggers("server", "readStatus")
 	g.log.DebugLog().Msg("getting the params for operation with the status")
 	url := req.GetUrl()
 	count := req.GetCount()
+	if req.GetSelector() != "" {
+		return g.readSelectedStatus(ctx, req)
+	}
 
 	g.log.DebugLog().Msg("getting list of states and forming a response")
-	list, err := statuses.ReadStatus(g.Сonn, url, count)
+	history, err := readHistory(ctx, g.Statuses, url, count)
 	if err != nil {
 		if err == statuses.ErrStatusNotFound {
 			err = status.Error(codes.NotFound, "unable to get statuses")
@@ -206,7 +781,550 @@ func (g GRPCServer) ReadStatus(ctx context.Context, req *proto.ReadRequestState)
 	}
 
 	g.log.DebugLog().Msg("sending a response")
-	return list, nil
+	return historyToProto(history), nil
+}
+
+// ReadHistory returns the page of the states of the site selected by the range and the health
+func (g *GRPCServer) ReadHistory(ctx context.Context, req *proto.ReadRequestHistory) (*proto.ReadResponseHistory, error) {
+	g.log = logging.NewLoggers("server", "readHistory")
+	g.log.DebugLog().Msg("getting the params for operation with the history")
+	size := req.GetPageSize()
+	if size == 0 {
+		size = defaultPageSize
+	}
+	if size < 0 || size > maxPageSize {
+		g.log.WarnLog().Str("when", "validate filter").Int64("page_size", size).Msg("incorrect page size")
+		return nil, status.Errorf(codes.InvalidArgument, "page size must be from 0 to %d", maxPageSize)
+	}
+	filter := statuses.Filter{
+		SiteId: req.GetSiteId(),
+		Health: req.GetHealth(),
+		// the state after the page shows whether there is the next page
+		Limit: size + 1,
+	}
+	if req.GetFrom() != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+	if err := filter.Validate(); err != nil {
+		g.log.WarnLog().Str("when", "validate filter").Err(err).Msg("incorrect filter")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetPageToken() != "" {
+		var err error
+		if filter.After, err = decodeStateToken(req.GetPageToken()); err != nil {
+			g.log.WarnLog().Str("when", "decode page token").Err(err).Msg("incorrect page token")
+			return nil, status.Error(codes.InvalidArgument, "incorrect page token")
+		}
+	}
+
+	g.log.DebugLog().Msg("getting the site")
+	site, err := (&anySites{repo: g.Sites}).read(ctx, filter.SiteId)
+	if err != nil {
+		if err == sites.ErrSitesNotFound {
+			err = status.Error(codes.NotFound, "unable to get site")
+			g.log.WarnLog().Str("when", "get site").Str("request", "failed to process").
+				Err(err).Msg("unable to get history")
+		} else {
+			err = status.Error(codes.Unknown, "unable to get history")
+			g.log.ErrorLog().Str("when", "get site").Str("request", "failed to process").
+				Err(err).Msg("unable to get history")
+		}
+		return nil, err
+	}
+
+	g.log.DebugLog().Msg("getting list of states and forming a response")
+	list, err := g.Statuses.ReadBySite(ctx, filter)
+	if err != nil {
+		err = status.Error(codes.Unknown, "unable to get history")
+		g.log.ErrorLog().Str("when", "getting states").Str("request", "failed to process").
+			Err(err).Msg("unable to get history")
+		return nil, err
+	}
+	res := &proto.ReadResponseHistory{Url: site.Url}
+	if int64(len(list)) > size {
+		list = list[:size]
+		res.NextPageToken = encodeStateToken(list[len(list)-1])
+	}
+	res.States = make([]*proto.State, 0, len(list))
+	for _, state := range list {
+		res.States = append(res.States, stateToProto(state))
+	}
+
+	g.log.DebugLog().Msg("sending a response")
+	return res, nil
+}
+
+// GetUptimeReport returns the uptime of the selected sites in the window
+func (g *GRPCServer) GetUptimeReport(ctx context.Context, req *proto.ReportRequestUptime) (*proto.ReportResponseUptime, error) {
+	g.log = logging.NewLoggers("server", "getUptimeReport")
+	g.log.DebugLog().Msg("getting the params for operation with the report")
+	selector, err := labels.Parse(req.GetSelector())
+	if err != nil {
+		g.log.WarnLog().Str("when", "parse selector").Err(err).Msg("incorrect selector")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.GetFrom() == nil {
+		g.log.WarnLog().Str("when", "validate window").Msg("no start of the window")
+		return nil, status.Error(codes.InvalidArgument, "from is required")
+	}
+	from, to := req.GetFrom().AsTime(), time.Now().UTC()
+	if req.GetTo() != nil {
+		to = req.GetTo().AsTime()
+	}
+	if !from.Before(to) || to.Sub(from) > maxReportWindow {
+		g.log.WarnLog().Str("when", "validate window").Time("from", from).Time("to", to).Msg("incorrect window")
+		return nil, status.Errorf(codes.InvalidArgument, "from must be before to by %v at most", maxReportWindow)
+	}
+	if g.Retention != nil {
+		if rawFrom := g.Retention.RawFrom(time.Now()); from.Before(rawFrom) {
+			g.log.WarnLog().Str("when", "validate window").Time("from", from).Time("raw_from", rawFrom).
+				Msg("window is compacted")
+			return nil, status.Errorf(codes.InvalidArgument,
+				"from must not be before %s, the older checks are compacted into rollups", rawFrom.Format(time.RFC3339))
+		}
+	}
+
+	g.log.DebugLog().Msg("getting selected sites")
+	var list []*sites.Site
+	if len(req.GetSiteIds()) == 0 {
+		list, err = g.Sites.ReadAll(ctx, sites.Filter{Selector: selector})
+	}
+	reader := &anySites{repo: g.Sites}
+	for _, id := range req.GetSiteIds() {
+		var site *sites.Site
+		if site, err = reader.read(ctx, id); err != nil {
+			break
+		}
+		if selector.Matches(site.Labels) {
+			list = append(list, site)
+		}
+	}
+	if err != nil {
+		if err == sites.ErrSitesNotFound {
+			err = status.Error(codes.NotFound, "unable to get site")
+			g.log.WarnLog().Str("when", "get sites").Str("request", "failed to process").
+				Err(err).Msg("unable to get report")
+		} else {
+			err = status.Error(codes.Unknown, "unable to get report")
+			g.log.ErrorLog().Str("when", "get sites").Str("request", "failed to process").
+				Err(err).Msg("unable to get report")
+		}
+		return nil, err
+	}
+
+	g.log.DebugLog().Int("sites", len(list)).Msg("computing uptime and forming a response")
+	res := &proto.ReportResponseUptime{From: timestamppb.New(from), To: timestamppb.New(to),
+		Sites: make([]*proto.SiteUptime, 0, len(list))}
+	for _, site := range list {
+		uptime, err := siteUptime(ctx, g.Statuses, site, from, to)
+		if err != nil {
+			err = status.Error(codes.Unknown, "unable to get report")
+			g.log.ErrorLog().Str("when", "getting states").Str("request", "failed to process").
+				Err(err).Msg("unable to get report")
+			return nil, err
+		}
+		res.Sites = append(res.Sites, &proto.SiteUptime{
+			SiteId:        uptime.SiteId,
+			Url:           uptime.Url,
+			Checks:        uptime.Checks,
+			UptimePercent: uptime.Percent(),
+			UpMs:          uptime.Up.Milliseconds(),
+			DownMs:        uptime.Down.Milliseconds(),
+			GapMs:         uptime.Gap.Milliseconds(),
+			Incidents:     uptime.Incidents,
+			MttrMs:        uptime.MTTR.Milliseconds(),
+			LatencyP50Ms:  uptime.LatencyP50.Milliseconds(),
+			LatencyP90Ms:  uptime.LatencyP90.Milliseconds(),
+			LatencyP99Ms:  uptime.LatencyP99.Milliseconds(),
+		})
+	}
+
+	g.log.DebugLog().Msg("sending a response")
+	return res, nil
+}
+
+// siteUptime reads the checks of the site in the window by
+// reportChunk with the check before it and computes the uptime
+func siteUptime(ctx context.Context, repo repository.StatusRepository, site *sites.Site,
+	from, to time.Time) (*report.Uptime, error) {
+	previous, err := repo.ReadBySite(ctx, statuses.Filter{SiteId: site.Id, To: from, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	var before *statuses.State
+	if len(previous) != 0 {
+		before = previous[0]
+	}
+	b := report.NewBuilder(site, before, from, to)
+	for start := from; start.Before(to); start = start.Add(reportChunk) {
+		end := start.Add(reportChunk)
+		if to.Before(end) {
+			end = to
+		}
+		states, err := repo.ReadBySite(ctx, statuses.Filter{SiteId: site.Id, From: start, To: end})
+		if err != nil {
+			return nil, err
+		}
+		// the states are ordered from the latest one
+		for i := len(states) - 1; i >= 0; i-- {
+			b.Add(states[i])
+		}
+	}
+	return b.Uptime(), nil
+}
+
+// anySites reads the sites by id, deleted or not. The deleted sites
+// are listed once, on the first id which is not found among the sites.
+type anySites struct {
+	repo    repository.SiteRepository
+	deleted map[int64]*sites.Site
+}
+
+// read returns the site by id, deleted or not
+func (a *anySites) read(ctx context.Context, id int64) (*sites.Site, error) {
+	site := &sites.Site{Id: id}
+	err := a.repo.Read(ctx, site)
+	if err != sites.ErrSitesNotFound {
+		return site, err
+	}
+	if a.deleted == nil {
+		list, err := a.repo.ListDeleted(ctx)
+		if err != nil {
+			return nil, err
+		}
+		a.deleted = make(map[int64]*sites.Site, len(list))
+		for _, site := range list {
+			a.deleted[site.Id] = site
+		}
+	}
+	if site, ok := a.deleted[id]; ok {
+		return site, nil
+	}
+	return nil, sites.ErrSitesNotFound
+}
+
+// stateToken is the last state of the page of the history
+type stateToken struct {
+	Id   int64     `json:"id"`
+	Date time.Time `json:"date"`
+}
+
+func encodeStateToken(last *statuses.State) string {
+	data, err := json.Marshal(stateToken{Id: last.Id, Date: last.Date})
+	if err != nil {
+		// the fields are a number and a time
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeStateToken(encoded string) (*statuses.State, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var token stateToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+	return &statuses.State{Id: token.Id, Date: token.Date}, nil
+}
+
+// readSelectedStatus returns the statuses of the sites selected by labels
+func (g GRPCServer) readSelectedStatus(ctx context.Context, req *proto.ReadRequestState) (*proto.StatusResponse, error) {
+	if req.GetUrl() != "" {
+		g.log.WarnLog().Str("when", "validate request").Msg("both url and selector")
+		return nil, status.Error(codes.InvalidArgument, "enter either the url or the selector")
+	}
+	selector, err := labels.Parse(req.GetSelector())
+	if err != nil {
+		g.log.WarnLog().Str("when", "parse selector").Err(err).Msg("incorrect selector")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	g.log.DebugLog().Msg("getting selected sites and their states")
+	list, err := g.Sites.ReadAll(ctx, sites.Filter{Selector: selector})
+	if err != nil {
+		err = status.Error(codes.Unknown, "unable to get statuses")
+		g.log.ErrorLog().Str("when", "get list of sites").Str("request", "failed to process").
+			Err(err).Msg("unable to get list of sites")
+		return nil, err
+	}
+	res := &proto.StatusResponse{Sites: make([]*proto.StatusResponse, 0, len(list))}
+	for _, site := range list {
+		history, err := readHistory(ctx, g.Statuses, site.Url, req.GetCount())
+		if err != nil && err != statuses.ErrStatusNotFound {
+			err = status.Error(codes.Unknown, "unable to get statuses")
+			g.log.ErrorLog().Str("when", "getting statuses").Str("request", "failed to process").
+				Err(err).Msg("unable to get statuses")
+			return nil, err
+		}
+		if history == nil {
+			history = &statuses.History{}
+		}
+		history.Url, history.Frequency = site.Url, site.Frequency
+		res.Sites = append(res.Sites, historyToProto(history))
+	}
+
+	g.log.DebugLog().Msg("sending a response")
+	return res, nil
+}
+
+// readHistory returns count latest states of the url
+// completed with rollups by readRollups
+func readHistory(ctx context.Context, repo repository.StatusRepository, url string,
+	count int64) (*statuses.History, error) {
+	history, err := repo.ReadByUrl(ctx, url, count)
+	if err != nil {
+		return nil, err
+	}
+	if err := readRollups(ctx, repo, history, count); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func historyToProto(history *statuses.History) *proto.StatusResponse {
+	list := make([]*proto.State, 0, len(history.States))
+	for _, state := range history.States {
+		list = append(list, stateToProto(state))
+	}
+
+	rollups := make([]*proto.Rollup, 0, len(history.Rollups))
+	for _, rollup := range history.Rollups {
+		rollups = append(rollups, &proto.Rollup{
+			SiteId:       rollup.SiteId,
+			Resolution:   rollup.Resolution,
+			Start:        timestamppb.New(rollup.Start),
+			Checks:       rollup.Checks,
+			UpRatio:      rollup.UpRatio(),
+			LatencyP50Ms: rollup.LatencyP50.Milliseconds(),
+			LatencyP90Ms: rollup.LatencyP90.Milliseconds(),
+			LatencyP99Ms: rollup.LatencyP99.Milliseconds(),
+		})
+	}
+
+	return &proto.StatusResponse{
+		Url:       history.Url,
+		Frequency: history.Frequency,
+		States:    list,
+		Rollups:   rollups,
+	}
+}
+
+func stateToProto(state *statuses.State) *proto.State {
+	return &proto.State{
+		Id:        state.Id,
+		Date:      timestamppb.New(state.Date),
+		Status:    state.Status,
+		SiteId:    state.SiteId,
+		LatencyMs: state.Latency.Milliseconds(),
+		Proxy:     state.Proxy,
+		Insecure:  state.Insecure,
+		Error:     state.Error,
+	}
+}
+
+// WatchStatus sends the results of checks of the selected sites as they happen,
+// until the client cancels the request. The stream fails with ResourceExhausted
+// when the client does not keep up with the checks.
+func (g *GRPCServer) WatchStatus(req *proto.WatchRequestState, stream proto.SitesService_WatchStatusServer) error {
+	// the stream lasts while other requests are served, so it has its own logger
+	logger := logging.NewLoggers("server", "watchStatus")
+	logger.DebugLog().Msg("getting the params for operation with the status")
+	selector, err := labels.Parse(req.GetSelector())
+	if err != nil {
+		logger.WarnLog().Str("when", "parse selector").Err(err).Msg("incorrect selector")
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	ids := make(map[int64]bool, len(req.GetSiteIds()))
+	for _, id := range req.GetSiteIds() {
+		ids[id] = true
+	}
+	match := func(e broker.Event) bool {
+		return (len(ids) == 0 || ids[e.Site.Id]) && selector.Matches(e.Site.Labels) &&
+			(!req.GetTransitionsOnly() || e.Transition)
+	}
+
+	logger.DebugLog().Msg("watching checks")
+	watcher := g.Backend.Broker.Watch(match, watchBuffer)
+	defer watcher.Close()
+	for {
+		select {
+		case <-stream.Context().Done():
+			logger.DebugLog().Msg("client stopped watching")
+			return nil
+		case e, ok := <-watcher.Events():
+			if !ok {
+				err := status.Error(codes.ResourceExhausted, "the client does not keep up with the checks")
+				logger.WarnLog().Str("when", "watching checks").Err(watcher.Err()).Msg("watcher is stopped")
+				return err
+			}
+			if err := stream.Send(&proto.WatchResponseState{
+				State:      stateToProto(e.State),
+				Url:        e.Site.Url,
+				Up:         e.State.Up(),
+				Transition: e.Transition,
+			}); err != nil {
+				logger.WarnLog().Str("when", "send check").Err(err).Msg("unable to send check")
+				return err
+			}
+		}
+	}
+}
+
+// readRollups completes the history with hourly and then daily
+// rollups, when raw states are less than count, because the older
+// states were compacted.
+func readRollups(ctx context.Context, repo repository.StatusRepository, history *statuses.History, count int64) error {
+	for _, resolution := range []string{statuses.ResolutionHour, statuses.ResolutionDay} {
+		left := count - int64(len(history.States)) - int64(len(history.Rollups))
+		if left <= 0 {
+			return nil
+		}
+		rollups, err := repo.ReadRollupsByUrl(ctx, history.Url, resolution, left)
+		if err != nil {
+			return err
+		}
+		history.Rollups = append(history.Rollups, rollups...)
+	}
+	return nil
+}
+
+// CreateObjective saves the objective and starts tracking its error budget
+func (g *GRPCServer) CreateObjective(ctx context.Context, req *proto.CreateRequestObjective) (*proto.CreateResponseObjective, error) {
+	g.log = logging.NewLoggers("server", "createObjective")
+	g.log.DebugLog().Msg("getting the params for operation with the objective")
+	o := objectiveFromProto(req.GetObjective())
+	if err := o.Validate(); err != nil {
+		g.log.WarnLog().Str("when", "validate objective").Err(err).Msg("incorrect objective")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if o.SiteId != 0 {
+		if err := g.Sites.Read(ctx, &sites.Site{Id: o.SiteId}); err != nil {
+			if err == sites.ErrSitesNotFound {
+				err = status.Error(codes.NotFound, "unable to get site")
+				g.log.WarnLog().Str("when", "get site").Str("request", "failed to process").
+					Err(err).Msg("unable to create objective")
+			} else {
+				err = status.Error(codes.Unknown, "unable to create objective")
+				g.log.ErrorLog().Str("when", "get site").Str("request", "failed to process").
+					Err(err).Msg("unable to create objective")
+			}
+			return nil, err
+		}
+	}
+
+	g.log.DebugLog().Msg("creating objective and forming a response")
+	if err := g.Sites.CreateObjective(ctx, &o); err != nil {
+		if err == slo.ErrObjectiveExists {
+			err = status.Error(codes.AlreadyExists, "the name is taken by another objective")
+			g.log.WarnLog().Str("when", "create objective").Str("request", "failed to process").
+				Err(err).Msg("unable to create objective")
+		} else {
+			err = status.Error(codes.Unknown, "unable to create objective")
+			g.log.ErrorLog().Str("when", "create objective").Str("request", "failed to process").
+				Err(err).Msg("unable to create objective")
+		}
+		return nil, err
+	}
+
+	g.log.DebugLog().Msg("starting tracking the error budget")
+	if err := g.Budgets.Add(ctx, &o, time.Now()); err != nil {
+		// the objective is tracked when the tracker loads the objectives again
+		g.log.ErrorLog().Str("when", "track objective").Err(err).Msg("unable to track objective")
+	}
+
+	g.log.DebugLog().Msg("sending response")
+	return &proto.CreateResponseObjective{Objective: objectiveToProto(&o)}, nil
+}
+
+// ListObjectives returns the objectives with their error budgets
+func (g *GRPCServer) ListObjectives(ctx context.Context, req *proto.ListRequestObjective) (*proto.ListResponseObjective, error) {
+	g.log = logging.NewLoggers("server", "listObjectives")
+
+	g.log.DebugLog().Msg("getting list of objectives and forming a response")
+	list, err := g.Sites.ListObjectives(ctx)
+	if err != nil {
+		err = status.Error(codes.Unknown, "unable to get objectives")
+		g.log.ErrorLog().Str("when", "get list of objectives").Str("request", "failed to process").
+			Err(err).Msg("unable to get list of objectives")
+		return nil, err
+	}
+
+	now := time.Now()
+	res := &proto.ListResponseObjective{Objectives: make([]*proto.ObjectiveStatus, 0, len(list))}
+	for _, o := range list {
+		st := &proto.ObjectiveStatus{Objective: objectiveToProto(o)}
+		if budgetStatus, ok := g.Budgets.Status(o.Id, now); ok {
+			st.Tracked = true
+			st.Checks = budgetStatus.Checks
+			st.Bad = budgetStatus.Bad
+			st.GoodPercent = budgetStatus.Percent()
+			st.BudgetRemainingPercent = budgetStatus.BudgetRemaining
+			st.BurnRate_5M = budgetStatus.BurnRate5m
+			st.BurnRate_30M = budgetStatus.BurnRate30m
+			st.BurnRate_1H = budgetStatus.BurnRate1h
+			st.BurnRate_6H = budgetStatus.BurnRate6h
+			st.Alert = budgetStatus.Alert
+		}
+		res.Objectives = append(res.Objectives, st)
+	}
+
+	g.log.DebugLog().Msg("sending a response")
+	return res, nil
+}
+
+// DeleteObjective removes the objective and stops tracking its error budget
+func (g *GRPCServer) DeleteObjective(ctx context.Context, req *proto.DeleteRequestObjective) (*proto.DeleteResponseObjective, error) {
+	g.log = logging.NewLoggers("server", "deleteObjective")
+	g.log.DebugLog().Msg("deleting objective and forming a response")
+	o := slo.Objective{Id: req.GetId()}
+	if err := g.Sites.DeleteObjective(ctx, &o); err != nil {
+		if err == slo.ErrObjectiveNotFound {
+			err = status.Error(codes.NotFound, "unable to delete objective")
+			g.log.WarnLog().Str("when", "delete objective").Str("request", "failed to process").
+				Err(err).Msg("unable to delete objective")
+		} else {
+			err = status.Error(codes.Unknown, "unable to delete objective")
+			g.log.ErrorLog().Str("when", "delete objective").Str("request", "failed to process").
+				Err(err).Msg("unable to delete objective")
+		}
+		return nil, err
+	}
+	g.Budgets.Remove(&o)
+
+	g.log.DebugLog().Msg("sending response")
+	return &proto.DeleteResponseObjective{Deleted: o.Id}, nil
+}
+
+func objectiveFromProto(o *proto.Objective) slo.Objective {
+	return slo.Objective{
+		Name:      o.GetName(),
+		SiteId:    o.GetSiteId(),
+		Selector:  o.GetSelector(),
+		Kind:      o.GetKind(),
+		Target:    o.GetTarget(),
+		Threshold: time.Duration(o.GetThresholdMs()) * time.Millisecond,
+		Window:    time.Duration(o.GetWindowSeconds()) * time.Second,
+	}
+}
+
+func objectiveToProto(o *slo.Objective) *proto.Objective {
+	return &proto.Objective{
+		Id:            o.Id,
+		Name:          o.Name,
+		SiteId:        o.SiteId,
+		Selector:      o.Selector,
+		Kind:          o.Kind,
+		Target:        o.Target,
+		ThresholdMs:   o.Threshold.Milliseconds(),
+		WindowSeconds: int64(o.Window / time.Second),
+		CreatedAt:     timestamppb.New(o.CreatedAt),
+	}
 }
 
 // RunServer ...
@@ -1,108 +1,145 @@
 package backendMngr
 
 import (
-	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/broker"
+	"CheckUrls/pkg/checker"
 	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository"
 	"CheckUrls/pkg/repository/sites"
 	statuses "CheckUrls/pkg/repository/status"
 	"context"
-	"database/sql"
-	"google.golang.org/protobuf/types/known/timestamppb"
-	"net/http"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"sync"
 	"time"
 )
 
-const sqlLastCheckStatus = "SELECT  s.id AS site_id, s.url, s.frequency, st.date FROM sites s LEFT JOIN " +
-	"(SELECT max(date) AS date, site_id FROM status GROUP BY site_id) st on s.id = st.site_id " +
-	"WHERE s.deleted=$1 ORDER BY s.id DESC;"
+type ScheduleConfig interface {
+	GetJitter() time.Duration
+	GetStartupRate() int
+}
+
+// ResultWriter saves the results of checks
+type ResultWriter interface {
+	Write(ctx context.Context, state *statuses.State) error
+	// Forget drops the states of the purged site, which are not saved yet
+	Forget(siteId int64)
+}
 
 type BackendManager struct {
-	checks map[string]*check
-	ctx    context.Context
-	log    *logging.Loggers
-	Conn   *db.ConnectionManager
+	checks   map[int64]*check
+	mu       sync.Mutex
+	ctx      context.Context
+	log      *logging.Loggers
+	jitter   time.Duration
+	checker  *checker.Checker
+	results  ResultWriter
+	Sites    repository.SiteRepository
+	Statuses repository.StatusRepository
+	// Broker publishes the results of checks as they happen
+	Broker *broker.Broker
 }
 
 type check struct {
-	site       *sites.Site
-	tickCheck  *time.Ticker
-	timerCheck *time.Timer
-	stop       chan struct{}
+	site    *sites.Site
+	jitter  time.Duration
+	checker *checker.Checker
+	results ResultWriter
+	broker  *broker.Broker
+	stop    chan struct{}
 }
 
-func NewBackendManager(conn *db.ConnectionManager, ctx context.Context) *BackendManager {
+func NewBackendManager(siteRepo repository.SiteRepository, statusRepo repository.StatusRepository,
+	results ResultWriter, ctx context.Context, cfg ScheduleConfig, siteChecker *checker.Checker) *BackendManager {
 	logger := logging.NewLoggers("backendMngr", "newBackendManager")
-	checkMap := make(map[string]*check)
-	logger.DebugLog().Msg("sql query get all sites with last check")
+	checkMap := make(map[int64]*check)
+	events := broker.NewBroker()
+	logger.DebugLog().Msg("get all sites with last check")
 
-	rows, cancel, err := conn.Query(sqlLastCheckStatus, false)
+	list, err := siteRepo.ReadAll(ctx, sites.Filter{})
 	if err != nil {
-		logger.ErrorLog().Err(err).Str("when", "sql request").Msg("failed sql query request")
+		logger.ErrorLog().Err(err).Str("when", "read sites").Msg("unable to get sites")
 		return nil
 	}
-	defer cancel()
-	defer func() {
-		if err := rows.Close(); err != nil {
-			logger.ErrorLog().Err(err).Str("when", "close rows").Msg("failed to close rows")
-		}
-	}()
-	for rows.Next() {
+	lastChecks, err := statusRepo.LastChecks(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "read last checks").Msg("unable to get last checks")
+		return nil
+	}
+
+	overdue := 0
+	now := time.Now()
+	for _, site := range list {
 		lastCheck := check{
-			site: &sites.Site{},
-			stop: make(chan struct{}),
-		}
-		var lastDate sql.NullTime
-		if err := rows.Scan(&lastCheck.site.Id, &lastCheck.site.Url, &lastCheck.site.Frequency, &lastDate); err != nil {
-			logger.ErrorLog().Err(err).Str("when", "scan rows").Msg("unable to scan results")
-			return nil
+			site:    site,
+			jitter:  cfg.GetJitter(),
+			checker: siteChecker,
+			results: results,
+			broker:  events,
+			stop:    make(chan struct{}),
 		}
-		subDate := time.Now().Sub(lastDate.Time)
-		switch {
-		case !lastDate.Valid || time.Duration(lastCheck.site.Frequency) < subDate:
-			go lastCheck.checkStatus(conn)
-			lastCheck.tickCheck = time.NewTicker(time.Duration(lastCheck.site.Frequency) * time.Second)
-			go lastCheck.serve(conn, ctx)
-		case time.Duration(lastCheck.site.Frequency) > subDate:
-			lastCheck.timerCheck = time.NewTimer((time.Duration(lastCheck.site.Frequency) - subDate) * time.Second)
-			go lastCheck.serveOnce(conn, ctx)
+		delay := lastCheck.untilNext(now)
+		if lastDate := lastChecks[site.Id]; lastDate.IsZero() || now.Sub(lastDate) > site.Interval() {
+			delay = startupDelay(overdue, cfg.GetStartupRate())
+			overdue++
 		}
-		checkMap[lastCheck.site.Url] = &lastCheck
-
+		go lastCheck.serve(ctx, delay)
+		checkMap[site.Id] = &lastCheck
 	}
+	logger.DebugLog().Int("overdue", overdue).Msg("sites scheduled")
 
 	return &BackendManager{
-		checks: checkMap,
-		ctx:    ctx,
-		Conn:   conn,
+		checks:   checkMap,
+		ctx:      ctx,
+		jitter:   cfg.GetJitter(),
+		checker:  siteChecker,
+		results:  results,
+		Sites:    siteRepo,
+		Statuses: statusRepo,
+		Broker:   events,
 	}
 }
 
 func (m *BackendManager) CreateOrUpdate(site *sites.Site) {
 	m.log = logging.NewLoggers("backendNanager", "createOrUpdate")
-	_, ok := m.checks[site.Url]
-	m.log.DebugLog().Msg("create new site")
-	if !ok {
-		if err := m.registerSite(site); err != nil {
-			m.log.ErrorLog().Err(err).Msg("unable to register site")
-			return
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.checks[site.Id]; ok {
+		m.log.DebugLog().Msg("update site")
+		if err := m.updateSite(site); err != nil {
+			m.log.ErrorLog().Err(err).Msg("unable to update site")
 		}
+		return
 	}
 
-	m.log.DebugLog().Msg("update site")
-	if err := m.updateSite(site); err != nil {
-		m.log.ErrorLog().Err(err).Msg("unable to update site")
-		return
+	m.log.DebugLog().Msg("create new site")
+	if err := m.registerSite(site); err != nil {
+		m.log.ErrorLog().Err(err).Msg("unable to register site")
 	}
 }
 
 func (m *BackendManager) Delete(site *sites.Site) {
 	m.log = logging.NewLoggers("backendMngr", "delete")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	check, ok := m.checks[site.Id]
+	if !ok {
+		m.log.WarnLog().Int64("site_id", site.Id).Msg("site is not checked")
+		return
+	}
 
-	m.log.DebugLog().Msg("stop ticker")
-	m.checks[site.Url].close()
+	m.log.DebugLog().Msg("stop check")
+	check.close()
 
 	m.log.DebugLog().Msg("delete site from checkUrl")
-	delete(m.checks, site.Url)
+	delete(m.checks, site.Id)
+	m.Broker.Forget(site.Id)
+}
+
+// Purge drops the results of the purged site, which are not saved yet
+func (m *BackendManager) Purge(site *sites.Site) {
+	m.results.Forget(site.Id)
 }
 
 func (m *BackendManager) registerSite(site *sites.Site) error {
@@ -110,17 +147,17 @@ func (m *BackendManager) registerSite(site *sites.Site) error {
 
 	m.log.DebugLog().Msg("filling out the site for verification")
 	check := check{
-		site:      site,
-		tickCheck: time.NewTicker(time.Duration(site.Frequency) * time.Second),
-		stop:      make(chan struct{}),
+		site:    site,
+		jitter:  m.jitter,
+		checker: m.checker,
+		results: m.results,
+		broker:  m.Broker,
+		stop:    make(chan struct{}),
 	}
-	if site.Frequency == 0 {
-		check.tickCheck = time.NewTicker(24 * time.Hour)
-	}
-	m.checks[site.Url] = &check
+	m.checks[site.Id] = &check
 
 	m.log.DebugLog().Msg("starting the check")
-	go m.checks[site.Url].serve(m.Conn, m.ctx)
+	go check.serve(m.ctx, check.untilNext(time.Now()))
 
 	return nil
 }
@@ -128,8 +165,8 @@ func (m *BackendManager) registerSite(site *sites.Site) error {
 func (m *BackendManager) updateSite(sites *sites.Site) error {
 	m.log = logging.NewLoggers("backendMngr", "updateSite")
 
-	m.log.DebugLog().Msg("stop old ticker")
-	m.checks[sites.Url].close()
+	m.log.DebugLog().Msg("stop old check")
+	m.checks[sites.Id].close()
 
 	m.log.DebugLog().Msg("update site")
 	if err := m.registerSite(sites); err != nil {
@@ -139,70 +176,87 @@ func (m *BackendManager) updateSite(sites *sites.Site) error {
 	return nil
 }
 
-func (c *check) close() {
-	close(c.stop)
+// phase returns the offset of the site checks inside
+// its interval. It depends only on site_id, so sites
+// with the same frequency are not checked in lockstep
+// and keep their schedule after restart.
+func phase(site *sites.Site) time.Duration {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(strconv.FormatInt(site.Id, 10)))
+	seconds := uint64(site.Interval() / time.Second)
+	return time.Duration(h.Sum64()%seconds) * time.Second
 }
 
-func (c *check) checkStatus(conn *db.ConnectionManager) {
-	logger := logging.NewLoggers("backendMngr", "checkStatus")
-	logger.InfoLog().Msg("start check")
-
-	logger.DebugLog().Msg("create client")
-	client := http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-		Timeout: 10 * time.Second,
-	}
-	stat := 0
+// nextSlot returns the first scheduled check of the site after t.
+func nextSlot(site *sites.Site, t time.Time) time.Time {
+	freq := site.Interval()
+	start := time.Unix(0, 0).Add(phase(site))
+	return start.Add((t.Sub(start)/freq + 1) * freq)
+}
 
-	logger.DebugLog().Msg("send GET request")
-	resp, err := client.Get(c.site.Url)
-	if err != nil {
-		logger.WarnLog().Err(err).Msg("unable to get response")
-		stat = 600
+// startupDelay returns the delay of the n-th overdue site, overdue sites
+// are not checked all at once, they are started one by one at the rate
+// per second. All of them are started at once when the rate is not set.
+func startupDelay(n, rate int) time.Duration {
+	if rate <= 0 {
+		return 0
 	}
+	return time.Duration(n) * (time.Second / time.Duration(rate))
+}
 
-	logger.DebugLog().Msg("getting params of state")
-	date := timestamppb.Now()
-	if stat != 600 {
-		stat = resp.StatusCode
+// untilNext returns the delay before the next check
+// with random jitter, which never exceeds the interval.
+func (c *check) untilNext(now time.Time) time.Duration {
+	delay := nextSlot(c.site, now).Sub(now)
+	jitter := c.jitter
+	if freq := c.site.Interval(); jitter > freq {
+		jitter = freq
 	}
-	state := &statuses.State{
-		Date:   date.AsTime(),
-		Status: int64(stat),
-		SiteId: c.site.Id,
+	if jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(jitter)))
 	}
+	return delay
+}
 
-	logger.DebugLog().Msg("create state")
-	if err := statuses.CreateStatus(conn, state); err != nil {
-		logger.ErrorLog().Err(err).Msg("unable to create status")
-		return
-	}
-	logger.InfoLog().Str("when", "start check").Msg("done")
+func (c *check) close() {
+	close(c.stop)
 }
 
-func (c *check) serveOnce(conn *db.ConnectionManager, ctx context.Context) {
-	defer c.timerCheck.Stop()
-	select {
-	case <-c.timerCheck.C:
-		go c.checkStatus(conn)
-		c.tickCheck = time.NewTicker(time.Duration(c.site.Frequency) * time.Second)
-		go c.serve(conn, ctx)
-		return
-	case <-c.stop:
+func (c *check) checkStatus(ctx context.Context) {
+	logger := logging.NewLoggers("backendMngr", "checkStatus")
+	logger.InfoLog().Msg("start check")
+
+	state, err := c.checker.Check(ctx, c.site)
+	if err != nil && ctx.Err() != nil {
+		logger.DebugLog().Err(err).Msg("check canceled")
 		return
-	case <-ctx.Done():
+	} else if err != nil {
+		// the check failed before the request, e.g. the certificate was not read,
+		// so it is recorded as a failed request with the error
+		logger.WarnLog().Err(err).Int64("site_id", c.site.Id).Msg("unable to check site")
+		state = &statuses.State{SiteId: c.site.Id, Date: time.Now(), Status: checker.StatusUnavailable,
+			Insecure: c.site.SkipVerify, Error: err.Error()}
+	}
+
+	// the watchers get a copy, as the writer sets the id of the state
+	published := *state
+	logger.DebugLog().Msg("write state")
+	if err := c.results.Write(ctx, state); err != nil {
+		logger.ErrorLog().Err(err).Msg("unable to write status")
 		return
 	}
+	c.broker.Publish(c.site, &published)
+	logger.InfoLog().Str("when", "start check").Msg("done")
 }
 
-func (c *check) serve(conn *db.ConnectionManager, ctx context.Context) {
-	defer c.tickCheck.Stop()
+func (c *check) serve(ctx context.Context, delay time.Duration) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
 	for {
 		select {
-		case <-c.tickCheck.C:
-			c.checkStatus(conn)
+		case <-timer.C:
+			c.checkStatus(ctx)
+			timer.Reset(c.untilNext(time.Now()))
 		case <-c.stop:
 			return
 		case <-ctx.Done():
@@ -0,0 +1,180 @@
+package backendMngr
+
+import (
+	"CheckUrls/pkg/broker"
+	"CheckUrls/pkg/checker"
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPhaseSpread(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency int64
+		sites     int
+		// minSlots is the least number of distinct seconds taken by the sites
+		minSlots int
+	}{
+		{"minute", 60, 600, 55},
+		{"hour", 3600, 600, 500},
+		{"second", 1, 10, 1},
+		{"without frequency", 0, 100, 95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots := make(map[time.Duration]int)
+			for id := int64(1); id <= int64(tt.sites); id++ {
+				site := &sites.Site{Id: id, Frequency: tt.frequency}
+				p := phase(site)
+				if p < 0 || p >= site.Interval() || p%time.Second != 0 {
+					t.Fatalf("phase of site %d = %v, want whole seconds within %v", id, p, site.Interval())
+				}
+				if again := phase(&sites.Site{Id: id, Frequency: tt.frequency}); again != p {
+					t.Fatalf("phase of site %d = %v and %v, want the same", id, p, again)
+				}
+				slots[p]++
+			}
+			if len(slots) < tt.minSlots {
+				t.Errorf("%d sites take %d slots, want at least %d", tt.sites, len(slots), tt.minSlots)
+			}
+		})
+	}
+}
+
+func TestNextSlot(t *testing.T) {
+	site := &sites.Site{Id: 7, Frequency: 60}
+	start := time.Unix(0, 0).Add(phase(site))
+	slot := start.Add(1000 * time.Minute)
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{"on the slot", slot, slot.Add(time.Minute)},
+		{"after the slot", slot.Add(time.Second), slot.Add(time.Minute)},
+		{"before the slot", slot.Add(-time.Nanosecond), slot},
+		{"a minute before", slot.Add(-time.Minute), slot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextSlot(site, tt.t); !got.Equal(tt.want) {
+				t.Errorf("nextSlot(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUntilNextJitter(t *testing.T) {
+	now := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		frequency int64
+		jitter    time.Duration
+		// max is the largest jitter added to the delay
+		max time.Duration
+	}{
+		{"without jitter", 60, 0, 0},
+		{"jitter", 60, 10 * time.Second, 10 * time.Second},
+		{"jitter above interval", 60, time.Hour, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &check{site: &sites.Site{Id: 3, Frequency: tt.frequency}, jitter: tt.jitter}
+			base := nextSlot(c.site, now).Sub(now)
+			var largest time.Duration
+			for i := 0; i < 1000; i++ {
+				delay := c.untilNext(now)
+				if delay < base || delay > base+tt.max || (tt.max > 0 && delay == base+tt.max) {
+					t.Fatalf("untilNext = %v, want from %v below %v", delay, base, base+tt.max)
+				}
+				if delay-base > largest {
+					largest = delay - base
+				}
+			}
+			// the jitter is random, 1000 delays cover the most of it
+			if tt.max > 0 && largest < tt.max/2 {
+				t.Errorf("largest jitter = %v of %v", largest, tt.max)
+			}
+		})
+	}
+}
+
+func TestStartupDelay(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		rate int
+		want time.Duration
+	}{
+		{"first site", 0, 10, 0},
+		{"tenth site", 10, 10, time.Second},
+		{"slow rate", 3, 1, 3 * time.Second},
+		{"fast rate", 5, 1000, 5 * time.Millisecond},
+		{"without rate", 100, 0, 0},
+		{"negative rate", 100, -1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := startupDelay(tt.n, tt.rate); got != tt.want {
+				t.Errorf("startupDelay(%d, %d) = %v, want %v", tt.n, tt.rate, got, tt.want)
+			}
+		})
+	}
+}
+
+type checkerConfig struct{}
+
+func (checkerConfig) GetCheckTimeout() time.Duration      { return time.Second }
+func (checkerConfig) GetMaxBodySize() int64               { return 1024 }
+func (checkerConfig) GetHostConcurrency() int             { return 0 }
+func (checkerConfig) GetProxy() string                    { return "" }
+func (checkerConfig) GetCertDir() string                  { return "" }
+func (checkerConfig) GetTLSReloadInterval() time.Duration { return 0 }
+
+// results keeps the written states
+type results struct {
+	states []*statuses.State
+}
+
+func (r *results) Write(ctx context.Context, state *statuses.State) error {
+	r.states = append(r.states, state)
+	return nil
+}
+
+func (r *results) Forget(siteId int64) {}
+
+func TestCheckErrorRecorded(t *testing.T) {
+	siteChecker, err := checker.NewChecker(checkerConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+	site := &sites.Site{Id: 7, Url: "https://127.0.0.1:1", CaBundle: missing, SkipVerify: true}
+	written := &results{}
+	c := &check{site: site, checker: siteChecker, results: written, broker: broker.NewBroker()}
+
+	c.checkStatus(context.Background())
+	if len(written.states) != 1 {
+		t.Fatalf("%d states are written, want 1", len(written.states))
+	}
+	state := written.states[0]
+	if state.SiteId != 7 || state.Status != checker.StatusUnavailable || !state.Insecure {
+		t.Errorf("state = %+v, want the failed check of the site", state)
+	}
+	if !strings.Contains(state.Error, "missing.pem") {
+		t.Errorf("error = %q, want the error of the CA bundle", state.Error)
+	}
+
+	// the canceled check is not recorded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.checkStatus(ctx)
+	if len(written.states) != 1 {
+		t.Errorf("%d states are written after the canceled check, want 1", len(written.states))
+	}
+}
@@ -0,0 +1,111 @@
+// Package broker delivers the results of checks to the watchers as they
+// happen. The checks never wait for the watchers: a watcher which does not
+// keep up with the checks is stopped.
+package broker
+
+import (
+	"CheckUrls/pkg/metrics"
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"fmt"
+	"sync"
+)
+
+var ErrSlowWatcher = fmt.Errorf("watcher does not keep up with the checks")
+
+// Event is the result of the check of the site
+type Event struct {
+	Site  *sites.Site
+	State *statuses.State
+	// Transition is set when the site went up or down since the previous
+	// check, the first check of the site after the start is not a transition
+	Transition bool
+}
+
+// Broker sends the events to the watchers
+type Broker struct {
+	mu       sync.Mutex
+	watchers map[*Watcher]struct{}
+	// up is the state of the last check of every site
+	up map[int64]bool
+}
+
+// Watcher receives the events matched by its filter
+type Watcher struct {
+	broker *Broker
+	match  func(Event) bool
+	events chan Event
+	err    error
+}
+
+func NewBroker() *Broker {
+	return &Broker{watchers: make(map[*Watcher]struct{}), up: make(map[int64]bool)}
+}
+
+// Watch returns the watcher of the events matched by match, nil matches every event.
+// The watcher is stopped with ErrSlowWatcher when size events are not received.
+func (b *Broker) Watch(match func(Event) bool, size int) *Watcher {
+	w := &Watcher{broker: b, match: match, events: make(chan Event, size)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.watchers[w] = struct{}{}
+	metrics.Watchers.Add(1)
+	return w
+}
+
+// Publish sends the result of the check to the watchers without waiting for them
+func (b *Broker) Publish(site *sites.Site, state *statuses.State) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	up, known := b.up[site.Id]
+	b.up[site.Id] = state.Up()
+	e := Event{Site: site, State: state, Transition: known && up != state.Up()}
+	for w := range b.watchers {
+		if w.match != nil && !w.match(e) {
+			continue
+		}
+		select {
+		case w.events <- e:
+		default:
+			w.err = ErrSlowWatcher
+			b.stop(w)
+			metrics.WatchersDropped.Add(1)
+		}
+	}
+}
+
+// Forget drops the state of the site, which is not checked anymore
+func (b *Broker) Forget(siteId int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.up, siteId)
+}
+
+// stop closes the events of the watcher, b.mu must be held
+func (b *Broker) stop(w *Watcher) {
+	if _, ok := b.watchers[w]; !ok {
+		return
+	}
+	delete(b.watchers, w)
+	close(w.events)
+	metrics.Watchers.Add(-1)
+}
+
+// Events returns the events, the channel is closed when the watcher is stopped
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Err returns ErrSlowWatcher after the events are closed because the watcher was slow
+func (w *Watcher) Err() error {
+	w.broker.mu.Lock()
+	defer w.broker.mu.Unlock()
+	return w.err
+}
+
+// Close stops the watcher, it can be called more than once
+func (w *Watcher) Close() {
+	w.broker.mu.Lock()
+	defer w.broker.mu.Unlock()
+	w.broker.stop(w)
+}
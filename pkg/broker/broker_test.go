@@ -0,0 +1,84 @@
+package broker
+
+import (
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"testing"
+)
+
+func publish(b *Broker, site *sites.Site, codes ...int64) {
+	for _, code := range codes {
+		b.Publish(site, &statuses.State{SiteId: site.Id, Status: code})
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	b := NewBroker()
+	w := b.Watch(nil, 10)
+	defer w.Close()
+	site := &sites.Site{Id: 1, Url: "https://example.com"}
+	publish(b, site, 200, 301, 500, 0, 200)
+
+	want := []bool{false, false, true, false, true}
+	for i, transition := range want {
+		e := <-w.Events()
+		if e.Site != site || e.Transition != transition {
+			t.Errorf("event #%d = %+v, want transition %v", i, e, transition)
+		}
+	}
+
+	// the forgotten site starts over
+	b.Forget(site.Id)
+	publish(b, site, 500)
+	if e := <-w.Events(); e.Transition {
+		t.Errorf("first check after Forget is a transition")
+	}
+}
+
+func TestMatch(t *testing.T) {
+	b := NewBroker()
+	w := b.Watch(func(e Event) bool { return e.Site.Id == 2 }, 10)
+	defer w.Close()
+	publish(b, &sites.Site{Id: 1}, 200)
+	publish(b, &sites.Site{Id: 2}, 404)
+	if e := <-w.Events(); e.Site.Id != 2 || e.State.Status != 404 {
+		t.Errorf("event = %+v, want the check of site 2", e)
+	}
+	select {
+	case e := <-w.Events():
+		t.Errorf("unexpected event %+v", e)
+	default:
+	}
+}
+
+func TestSlowWatcher(t *testing.T) {
+	b := NewBroker()
+	slow := b.Watch(nil, 2)
+	fast := b.Watch(nil, 10)
+	defer fast.Close()
+	publish(b, &sites.Site{Id: 1}, 200, 200, 200)
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received != 2 || slow.Err() != ErrSlowWatcher {
+		t.Errorf("slow watcher received %d events with %v, want 2 with %v", received, slow.Err(), ErrSlowWatcher)
+	}
+	if len(fast.Events()) != 3 || fast.Err() != nil {
+		t.Errorf("fast watcher has %d events with %v, want 3", len(fast.Events()), fast.Err())
+	}
+	// the stopped watcher can be closed
+	slow.Close()
+}
+
+func TestClose(t *testing.T) {
+	b := NewBroker()
+	w := b.Watch(nil, 1)
+	w.Close()
+	w.Close()
+	publish(b, &sites.Site{Id: 1}, 200)
+	if _, ok := <-w.Events(); ok || w.Err() != nil {
+		t.Errorf("closed watcher received the event or failed with %v", w.Err())
+	}
+}
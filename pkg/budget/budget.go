@@ -0,0 +1,555 @@
+// Package budget tracks the error budgets of the objectives by the results
+// of checks as they happen and alerts when a budget burns too fast.
+//
+// The burn rate is the share of bad checks divided by the share allowed by
+// the objective, the budget lasts exactly the window at the rate 1. An alert
+// fires when the burn rate is over its threshold both in the long window and
+// in the short one, so it resolves soon after the burn stops: the fast burn
+// is checked over 1h and 5m, the slow burn over 6h and 30m.
+package budget
+
+import (
+	"CheckUrls/pkg/broker"
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/metrics"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/labels"
+	"CheckUrls/pkg/repository/sites"
+	"CheckUrls/pkg/repository/slo"
+	statuses "CheckUrls/pkg/repository/status"
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Alerts of the objectives
+const (
+	AlertNone     = ""
+	AlertFastBurn = "fast_burn"
+	AlertSlowBurn = "slow_burn"
+)
+
+// States of the alert in the notices
+const (
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// watchBuffer is the number of checks waiting for the tracker
+const watchBuffer = 10000
+
+// loadChunk is the part of the window which raw checks are read at once
+const loadChunk = 24 * time.Hour
+
+// webhookTimeout limits the delivery of a notice
+const webhookTimeout = 10 * time.Second
+
+// longestBurnWindow is the longest window of the burn rates,
+// the checks are counted by minutes within it
+const longestBurnWindow = 6 * time.Hour
+
+type BudgetConfig interface {
+	GetBudgetInterval() time.Duration
+	GetFastBurnRate() float64
+	GetSlowBurnRate() float64
+	GetAlertWebhook() string
+}
+
+// Status is the error budget of the objective at the time.
+// The window is rounded to hours and the burn windows to minutes.
+type Status struct {
+	Objective slo.Objective
+	// Checks and Bad are the checks of the selected sites in the window
+	Checks int64
+	Bad    int64
+	// BudgetRemaining is the percent of the error budget left,
+	// it is below 0 when the budget is exhausted
+	BudgetRemaining float64
+	BurnRate5m      float64
+	BurnRate30m     float64
+	BurnRate1h      float64
+	BurnRate6h      float64
+	// Alert is the alert which fires at the rates
+	Alert string
+}
+
+// Percent returns the percent of good checks in the window, 0 if there were no checks
+func (s *Status) Percent() float64 {
+	if s.Checks == 0 {
+		return 0
+	}
+	return float64(s.Checks-s.Bad) * 100 / float64(s.Checks)
+}
+
+// Notice is sent to the webhook when the alert starts firing or resolves
+type Notice struct {
+	ObjectiveId int64  `json:"objective_id"`
+	Objective   string `json:"objective"`
+	Alert       string `json:"alert"`
+	State       string `json:"state"`
+	// BurnRate is the rate in the long window of the alert
+	BurnRate        float64   `json:"burn_rate"`
+	BudgetRemaining float64   `json:"budget_remaining_percent"`
+	Date            time.Time `json:"date"`
+}
+
+// counts are the checks in a minute or in an hour
+type counts struct {
+	checks int64
+	bad    int64
+}
+
+// tracked is the objective with its checks counted by minutes
+// for the burn rates and by hours for the window
+type tracked struct {
+	objective slo.Objective
+	selector  labels.Selector
+	// since is the time of loading the checks from the repository,
+	// the earlier checks are not counted again as they happen
+	since   time.Time
+	minutes map[time.Time]*counts
+	hours   map[time.Time]*counts
+	alert   string
+}
+
+// Tracker counts the checks of the objectives
+type Tracker struct {
+	mu         sync.Mutex
+	sites      repository.SiteRepository
+	statuses   repository.StatusRepository
+	broker     *broker.Broker
+	interval   time.Duration
+	fastRate   float64
+	slowRate   float64
+	webhook    string
+	client     *http.Client
+	objectives map[int64]*tracked
+}
+
+func NewTracker(cfg BudgetConfig, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository,
+	events *broker.Broker) *Tracker {
+	return &Tracker{
+		sites:      siteRepo,
+		statuses:   statusRepo,
+		broker:     events,
+		interval:   cfg.GetBudgetInterval(),
+		fastRate:   cfg.GetFastBurnRate(),
+		slowRate:   cfg.GetSlowBurnRate(),
+		webhook:    cfg.GetAlertWebhook(),
+		client:     &http.Client{Timeout: webhookTimeout},
+		objectives: make(map[int64]*tracked),
+	}
+}
+
+// Run loads the objectives with the checks in their windows, then counts
+// the checks as they happen and raises the alerts every interval until ctx
+// is done. Nothing is tracked if the interval is not positive. The objectives
+// are loaded again when the tracker does not keep up with the checks.
+func (t *Tracker) Run(ctx context.Context) error {
+	logger := logging.NewLoggers("budget", "run")
+	if t.interval <= 0 {
+		logger.DebugLog().Msg("error budgets are not tracked")
+		return nil
+	}
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		err := t.Load(ctx, time.Now())
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("when", "load objectives").Msg("unable to load objectives")
+		} else {
+			// the checks are watched after loading, so the slow loading does not stop
+			// the watcher, the checks saved meanwhile are read before following
+			watched := time.Now()
+			watcher := t.broker.Watch(nil, watchBuffer)
+			if err = t.catchUp(ctx, watched); err != nil {
+				logger.ErrorLog().Err(err).Str("when", "catch up").Msg("unable to read checks since loading")
+			} else {
+				err = t.follow(ctx, watcher, ticker.C)
+			}
+			watcher.Close()
+		}
+		if err == broker.ErrSlowWatcher {
+			logger.WarnLog().Err(err).Str("when", "follow checks").Msg("loading objectives again")
+			continue
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// follow counts the watched checks and raises the alerts on every tick,
+// it returns ErrSlowWatcher when the watcher is stopped and nil when ctx is done
+func (t *Tracker) follow(ctx context.Context, watcher *broker.Watcher, tick <-chan time.Time) error {
+	for {
+		select {
+		case e, ok := <-watcher.Events():
+			if !ok {
+				return watcher.Err()
+			}
+			t.Observe(e.Site, e.State)
+		case now := <-tick:
+			t.Alert(ctx, now)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Load replaces the tracked objectives by the saved ones with their checks
+// in the windows before now, the firing alerts are kept
+func (t *Tracker) Load(ctx context.Context, now time.Time) error {
+	list, err := t.sites.ListObjectives(ctx)
+	if err != nil {
+		return err
+	}
+	loaded := make(map[int64]*tracked, len(list))
+	for _, o := range list {
+		if loaded[o.Id], err = t.load(ctx, o, now); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, tr := range loaded {
+		if old, ok := t.objectives[id]; ok {
+			tr.alert = old.alert
+		}
+	}
+	for id, old := range t.objectives {
+		if _, ok := loaded[id]; !ok {
+			metrics.SloBudgetRemaining.Delete(old.objective.Name)
+		}
+	}
+	t.objectives = loaded
+	return nil
+}
+
+// Add starts tracking the new objective with its checks in the window before now
+func (t *Tracker) Add(ctx context.Context, o *slo.Objective, now time.Time) error {
+	tr, err := t.load(ctx, o, now)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objectives[o.Id] = tr
+	return nil
+}
+
+// Remove stops tracking the objective
+func (t *Tracker) Remove(o *slo.Objective) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.objectives, o.Id)
+	metrics.SloBudgetRemaining.Delete(o.Name)
+}
+
+// load reads the checks of the sites selected by the objective in its window before now,
+// the compacted checks are counted by their rollups
+func (t *Tracker) load(ctx context.Context, o *slo.Objective, now time.Time) (*tracked, error) {
+	selector, err := labels.Parse(o.Selector)
+	if err != nil {
+		return nil, err
+	}
+	tr := &tracked{objective: *o, selector: selector, since: now,
+		minutes: make(map[time.Time]*counts), hours: make(map[time.Time]*counts)}
+	list, err := t.selected(ctx, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	// the compaction removes the raw checks which it rolls up,
+	// so the rollups and the raw checks are counted together
+	from := statuses.Bucket(now.Add(-o.Window), statuses.ResolutionHour)
+	for _, site := range list {
+		for _, resolution := range []string{statuses.ResolutionDay, statuses.ResolutionHour} {
+			rollups, err := t.statuses.ReadSiteRollups(ctx, site.Id, resolution, from, now)
+			if err != nil {
+				return nil, err
+			}
+			for _, rollup := range rollups {
+				tr.addRollup(rollup)
+			}
+		}
+		for start := from; start.Before(now); start = start.Add(loadChunk) {
+			end := start.Add(loadChunk)
+			if now.Before(end) {
+				end = now
+			}
+			states, err := t.statuses.ReadBySite(ctx, statuses.Filter{SiteId: site.Id, From: start, To: end})
+			if err != nil {
+				return nil, err
+			}
+			for _, state := range states {
+				tr.add(state)
+			}
+		}
+	}
+	return tr, nil
+}
+
+// selected returns the sites selected by the objective
+func (t *Tracker) selected(ctx context.Context, tr *tracked) ([]*sites.Site, error) {
+	if tr.objective.SiteId == 0 {
+		return t.sites.ReadAll(ctx, sites.Filter{Selector: tr.selector})
+	}
+	site := &sites.Site{Id: tr.objective.SiteId}
+	switch err := t.sites.Read(ctx, site); err {
+	case nil:
+		return []*sites.Site{site}, nil
+	case sites.ErrSitesNotFound:
+		return nil, nil
+	default:
+		return nil, err
+	}
+}
+
+// catchUp counts the saved checks of the tracked objectives
+// from the time of their loading until to, which is the time
+// the checks are watched since
+func (t *Tracker) catchUp(ctx context.Context, to time.Time) error {
+	t.mu.Lock()
+	list := make([]*tracked, 0, len(t.objectives))
+	for _, tr := range t.objectives {
+		if tr.since.Before(to) {
+			list = append(list, tr)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, tr := range list {
+		selected, err := t.selected(ctx, tr)
+		if err != nil {
+			return err
+		}
+		var states []*statuses.State
+		for _, site := range selected {
+			saved, err := t.statuses.ReadBySite(ctx, statuses.Filter{SiteId: site.Id, From: tr.since, To: to})
+			if err != nil {
+				return err
+			}
+			states = append(states, saved...)
+		}
+		t.mu.Lock()
+		for _, state := range states {
+			tr.add(state)
+		}
+		tr.since = to
+		t.mu.Unlock()
+	}
+	return nil
+}
+
+// Observe counts the check of the site in the objectives which select the site
+func (t *Tracker) Observe(site *sites.Site, state *statuses.State) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tr := range t.objectives {
+		if tr.selects(site) && !state.Date.Before(tr.since) {
+			tr.add(state)
+		}
+	}
+}
+
+// Status returns the error budget of the objective at now,
+// false if the objective is not tracked
+func (t *Tracker) Status(id int64, now time.Time) (*Status, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tr, ok := t.objectives[id]
+	if !ok {
+		return nil, false
+	}
+	return t.status(tr, now), true
+}
+
+// Alert computes the error budgets at now and notifies
+// about the alerts which started firing or resolved
+func (t *Tracker) Alert(ctx context.Context, now time.Time) {
+	logger := logging.NewLoggers("budget", "alert")
+	t.mu.Lock()
+	ids := make([]int64, 0, len(t.objectives))
+	for id := range t.objectives {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	var notices []*Notice
+	for _, id := range ids {
+		tr := t.objectives[id]
+		s := t.status(tr, now)
+		remaining := new(expvar.Float)
+		remaining.Set(s.BudgetRemaining)
+		metrics.SloBudgetRemaining.Set(tr.objective.Name, remaining)
+		if s.Alert == tr.alert {
+			continue
+		}
+		if tr.alert != AlertNone {
+			notices = append(notices, newNotice(s, tr.alert, StateResolved, now))
+		}
+		if s.Alert != AlertNone {
+			notices = append(notices, newNotice(s, s.Alert, StateFiring, now))
+			metrics.SloAlerts.Add(1)
+		}
+		tr.alert = s.Alert
+	}
+	t.mu.Unlock()
+
+	for _, n := range notices {
+		if n.State == StateFiring {
+			logger.WarnLog().Str("objective", n.Objective).Str("alert", n.Alert).
+				Float64("burn_rate", n.BurnRate).Float64("budget_remaining", n.BudgetRemaining).
+				Msg("error budget burns too fast")
+		} else {
+			logger.InfoLog().Str("objective", n.Objective).Str("alert", n.Alert).Msg("alert resolved")
+		}
+		if err := t.notify(ctx, n); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "send notice").Str("objective", n.Objective).
+				Msg("unable to notify about the alert")
+		}
+	}
+}
+
+// notify posts the notice in JSON to the webhook, if it is set
+func (t *Tracker) notify(ctx context.Context, n *Notice) error {
+	if t.webhook == "" {
+		return nil
+	}
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+	return nil
+}
+
+func newNotice(s *Status, alert, state string, now time.Time) *Notice {
+	rate := s.BurnRate6h
+	if alert == AlertFastBurn {
+		rate = s.BurnRate1h
+	}
+	return &Notice{ObjectiveId: s.Objective.Id, Objective: s.Objective.Name, Alert: alert, State: state,
+		BurnRate: rate, BudgetRemaining: s.BudgetRemaining, Date: now.UTC()}
+}
+
+// status computes the error budget of the objective at now
+// and drops the counts out of its windows, t.mu must be held
+func (t *Tracker) status(tr *tracked, now time.Time) *Status {
+	tr.prune(now)
+	o := &tr.objective
+	s := &Status{Objective: *o, BudgetRemaining: 100}
+	windowStart := statuses.Bucket(now.Add(-o.Window), statuses.ResolutionHour)
+	for start, c := range tr.hours {
+		if !start.Before(windowStart) {
+			s.Checks += c.checks
+			s.Bad += c.bad
+		}
+	}
+	if s.Checks != 0 {
+		s.BudgetRemaining = 100 * (1 - float64(s.Bad)/(o.Budget()*float64(s.Checks)))
+	}
+	s.BurnRate5m = tr.burnRate(now, 5*time.Minute)
+	s.BurnRate30m = tr.burnRate(now, 30*time.Minute)
+	s.BurnRate1h = tr.burnRate(now, time.Hour)
+	s.BurnRate6h = tr.burnRate(now, longestBurnWindow)
+	switch {
+	case s.BurnRate1h >= t.fastRate && s.BurnRate5m >= t.fastRate:
+		s.Alert = AlertFastBurn
+	case s.BurnRate6h >= t.slowRate && s.BurnRate30m >= t.slowRate:
+		s.Alert = AlertSlowBurn
+	}
+	return s
+}
+
+// selects reports whether the objective counts the checks of the site
+func (tr *tracked) selects(site *sites.Site) bool {
+	if tr.objective.SiteId != 0 {
+		return site.Id == tr.objective.SiteId
+	}
+	return tr.selector.Matches(site.Labels)
+}
+
+// add counts the check in its minute and hour
+func (tr *tracked) add(state *statuses.State) {
+	good := tr.objective.Good(state)
+	for _, c := range []*counts{
+		bucket(tr.minutes, state.Date.UTC().Truncate(time.Minute)),
+		bucket(tr.hours, statuses.Bucket(state.Date, statuses.ResolutionHour)),
+	} {
+		c.checks++
+		if !good {
+			c.bad++
+		}
+	}
+}
+
+// addRollup counts the checks of the rollup in the hour of its start,
+// the rollups are older than the burn windows
+func (tr *tracked) addRollup(r *statuses.Rollup) {
+	c := bucket(tr.hours, r.Start.UTC())
+	c.checks += r.Checks
+	c.bad += r.Checks - tr.objective.GoodChecks(r)
+}
+
+// burnRate returns the burn rate of the checks in the window before now, 0 without checks
+func (tr *tracked) burnRate(now time.Time, window time.Duration) float64 {
+	var sum counts
+	from := now.Add(-window).Truncate(time.Minute)
+	for start, c := range tr.minutes {
+		if !start.Before(from) {
+			sum.checks += c.checks
+			sum.bad += c.bad
+		}
+	}
+	if sum.checks == 0 {
+		return 0
+	}
+	return float64(sum.bad) / float64(sum.checks) / tr.objective.Budget()
+}
+
+// prune drops the minutes older than the longest burn window and the hours out of the window
+func (tr *tracked) prune(now time.Time) {
+	minutesFrom := now.Add(-longestBurnWindow).Truncate(time.Minute)
+	for start := range tr.minutes {
+		if start.Before(minutesFrom) {
+			delete(tr.minutes, start)
+		}
+	}
+	hoursFrom := statuses.Bucket(now.Add(-tr.objective.Window), statuses.ResolutionHour)
+	for start := range tr.hours {
+		if start.Before(hoursFrom) {
+			delete(tr.hours, start)
+		}
+	}
+}
+
+func bucket(buckets map[time.Time]*counts, start time.Time) *counts {
+	c, ok := buckets[start]
+	if !ok {
+		c = new(counts)
+		buckets[start] = c
+	}
+	return c
+}
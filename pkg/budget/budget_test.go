@@ -0,0 +1,256 @@
+package budget
+
+import (
+	"CheckUrls/pkg/broker"
+	"CheckUrls/pkg/repository/memory"
+	"CheckUrls/pkg/repository/sites"
+	"CheckUrls/pkg/repository/slo"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	webhook string
+}
+
+func (testConfig) GetBudgetInterval() time.Duration { return time.Minute }
+func (testConfig) GetFastBurnRate() float64         { return 14.4 }
+func (testConfig) GetSlowBurnRate() float64         { return 6 }
+func (c testConfig) GetAlertWebhook() string        { return c.webhook }
+
+var now = time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func minutesAgo(n int) time.Time {
+	return now.Add(-time.Duration(n) * time.Minute)
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// newTracker saves the objective and the site 1 labeled team=payments
+// and returns the tracker of them
+func newTracker(t *testing.T, cfg testConfig, o *slo.Objective) (*Tracker, *sites.Site) {
+	t.Helper()
+	siteRepo, statusRepo := memory.NewRepositories()
+	site := &sites.Site{Url: "https://example.com", Frequency: 60, Labels: map[string]string{"team": "payments"}}
+	if err := siteRepo.Create(context.Background(), site, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := siteRepo.CreateObjective(context.Background(), o); err != nil {
+		t.Fatal(err)
+	}
+	return NewTracker(cfg, siteRepo, statusRepo, broker.NewBroker()), site
+}
+
+func TestStatus(t *testing.T) {
+	o := &slo.Objective{Name: "api", Selector: "team=payments", Kind: slo.KindAvailability,
+		Target: 99, Window: 24 * time.Hour}
+	tracker, site := newTracker(t, testConfig{}, o)
+	if err := tracker.Load(context.Background(), minutesAgo(600)); err != nil {
+		t.Fatal(err)
+	}
+	// a check a minute for 10 hours, the last 3 minutes are down
+	for n := 600; n >= 1; n-- {
+		status := int64(200)
+		if n <= 3 {
+			status = 503
+		}
+		tracker.Observe(site, &statuses.State{Date: minutesAgo(n), Status: status, SiteId: site.Id})
+	}
+	// the checks of other sites and the checks before loading are not counted
+	tracker.Observe(&sites.Site{Id: 2}, &statuses.State{Date: minutesAgo(1), Status: 500, SiteId: 2})
+	tracker.Observe(site, &statuses.State{Date: minutesAgo(601), Status: 500, SiteId: site.Id})
+
+	s, ok := tracker.Status(o.Id, now)
+	if !ok {
+		t.Fatal("objective is not tracked")
+	}
+	if s.Checks != 600 || s.Bad != 3 {
+		t.Fatalf("checks = %d, bad = %d, want 600 and 3", s.Checks, s.Bad)
+	}
+	// 3 bad checks of 6 allowed
+	if !near(s.BudgetRemaining, 50) || !near(s.Percent(), 99.5) {
+		t.Errorf("budget remaining = %v, percent = %v, want 50 and 99.5", s.BudgetRemaining, s.Percent())
+	}
+	// 3 of 5 checks are bad in 5 minutes, 3 of 60 in an hour, 3 of 360 in 6 hours
+	if !near(s.BurnRate5m, 60) || !near(s.BurnRate30m, 10) || !near(s.BurnRate1h, 5) || !near(s.BurnRate6h, 100.0/120) {
+		t.Errorf("burn rates = %v %v %v %v", s.BurnRate5m, s.BurnRate30m, s.BurnRate1h, s.BurnRate6h)
+	}
+	if s.Alert != AlertNone {
+		t.Errorf("alert = %q, want none", s.Alert)
+	}
+
+	// the burn in an hour fires the fast burn alert
+	later := now.Add(10 * time.Minute)
+	for n := 0; n < 10; n++ {
+		tracker.Observe(site, &statuses.State{Date: now.Add(time.Duration(n) * time.Minute), Status: 0, SiteId: site.Id})
+	}
+	if s, _ = tracker.Status(o.Id, later); s.Alert != AlertFastBurn {
+		t.Errorf("alert = %q with the burn rates %v and %v, want fast burn", s.Alert, s.BurnRate1h, s.BurnRate5m)
+	}
+	if s.BudgetRemaining >= 0 {
+		t.Errorf("budget remaining = %v, want exhausted", s.BudgetRemaining)
+	}
+
+	// the counts out of the window are dropped
+	if s, _ = tracker.Status(o.Id, later.Add(48*time.Hour)); s.Checks != 0 || s.BudgetRemaining != 100 {
+		t.Errorf("status after the window = %+v", *s)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	o := &slo.Objective{Name: "fast", SiteId: 1, Kind: slo.KindLatency, Target: 90,
+		Threshold: 100 * time.Millisecond, Window: time.Hour}
+	tracker, site := newTracker(t, testConfig{}, o)
+	states := []*statuses.State{
+		// out of the window
+		{Date: minutesAgo(150), Status: 500, Latency: 10 * time.Millisecond},
+		{Date: minutesAgo(30), Status: 200, Latency: 10 * time.Millisecond},
+		{Date: minutesAgo(20), Status: 200, Latency: 200 * time.Millisecond},
+		// fast, but down
+		{Date: minutesAgo(10), Status: 500, Latency: 10 * time.Millisecond},
+		{Date: minutesAgo(1), Status: 200, Latency: 100 * time.Millisecond},
+	}
+	for _, state := range states {
+		state.SiteId = site.Id
+		if err := tracker.statuses.Create(context.Background(), state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tracker.Load(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	s, ok := tracker.Status(o.Id, now)
+	if !ok || s.Checks != 4 || s.Bad != 2 {
+		t.Fatalf("status = %+v, want 2 bad checks of 4", s)
+	}
+
+	tracker.Remove(o)
+	if _, ok := tracker.Status(o.Id, now); ok {
+		t.Error("removed objective is tracked")
+	}
+	if err := tracker.Add(context.Background(), o, now); err != nil {
+		t.Fatal(err)
+	}
+	if s, ok = tracker.Status(o.Id, now); !ok || s.Checks != 4 {
+		t.Errorf("status of the added objective = %+v", s)
+	}
+}
+
+func TestLoadRollups(t *testing.T) {
+	o := &slo.Objective{Name: "fast", SiteId: 1, Kind: slo.KindLatency, Target: 90,
+		Threshold: 100 * time.Millisecond, Window: 72 * time.Hour}
+	tracker, site := newTracker(t, testConfig{}, o)
+	day := time.Date(2021, 4, 29, 0, 0, 0, 0, time.UTC)
+	rollup := func(resolution string, start time.Time, checks, up int64, p90 time.Duration) *statuses.Rollup {
+		return &statuses.Rollup{SiteId: site.Id, Resolution: resolution, Start: start, Checks: checks, Up: up,
+			LatencyP50: 10 * time.Millisecond, LatencyP90: p90, LatencyP99: 500 * time.Millisecond}
+	}
+	if err := tracker.statuses.SaveRollups(context.Background(), []*statuses.Rollup{
+		// the day started before the window is not counted
+		rollup(statuses.ResolutionDay, day.AddDate(0, 0, -1), 1440, 0, 50*time.Millisecond),
+		// 1% of the up checks are estimated as slow
+		rollup(statuses.ResolutionDay, day, 1440, 1400, 50*time.Millisecond),
+		// 10% of the up checks are estimated as slow
+		rollup(statuses.ResolutionHour, day.AddDate(0, 0, 1), 60, 60, 200*time.Millisecond),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.statuses.Create(context.Background(), &statuses.State{Date: minutesAgo(30), Status: 500,
+		SiteId: site.Id}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.Load(context.Background(), now); err != nil {
+		t.Fatal(err)
+	}
+	s, ok := tracker.Status(o.Id, now)
+	if !ok || s.Checks != 1501 || s.Bad != 40+14+6+1 {
+		t.Fatalf("status = %+v, want 61 bad checks of 1501", s)
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	o := &slo.Objective{Name: "api", SiteId: 1, Kind: slo.KindAvailability, Target: 99, Window: time.Hour}
+	tracker, site := newTracker(t, testConfig{}, o)
+	if err := tracker.Load(context.Background(), minutesAgo(10)); err != nil {
+		t.Fatal(err)
+	}
+	// the check saved while the watcher was not started is read, the watched one is counted once
+	for _, state := range []*statuses.State{
+		{Date: minutesAgo(8), Status: 500, SiteId: site.Id},
+		{Date: minutesAgo(5), Status: 200, SiteId: site.Id},
+	} {
+		if err := tracker.statuses.Create(context.Background(), state); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tracker.catchUp(context.Background(), minutesAgo(5)); err != nil {
+		t.Fatal(err)
+	}
+	tracker.Observe(site, &statuses.State{Date: minutesAgo(8), Status: 500, SiteId: site.Id})
+	tracker.Observe(site, &statuses.State{Date: minutesAgo(5), Status: 200, SiteId: site.Id})
+	s, ok := tracker.Status(o.Id, now)
+	if !ok || s.Checks != 2 || s.Bad != 1 {
+		t.Fatalf("status = %+v, want 1 bad check of 2", s)
+	}
+}
+
+func TestAlert(t *testing.T) {
+	var mu sync.Mutex
+	var notices []Notice
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var n Notice
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			t.Errorf("decode notice: %v", err)
+		}
+		mu.Lock()
+		notices = append(notices, n)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	o := &slo.Objective{Name: "api", SiteId: 1, Kind: slo.KindAvailability, Target: 99.9, Window: 24 * time.Hour}
+	tracker, site := newTracker(t, testConfig{webhook: hook.URL}, o)
+	if err := tracker.Load(context.Background(), minutesAgo(60)); err != nil {
+		t.Fatal(err)
+	}
+	for n := 60; n >= 1; n-- {
+		status := int64(200)
+		if n <= 2 {
+			status = 503
+		}
+		tracker.Observe(site, &statuses.State{Date: minutesAgo(n), Status: status, SiteId: site.Id})
+	}
+	tracker.Alert(context.Background(), now)
+	// the alert is sent once
+	tracker.Alert(context.Background(), now)
+	// the burn stopped 10 minutes ago, so the fast burn resolves,
+	// but the budget still burns in the windows of the slow burn
+	for n := 0; n < 10; n++ {
+		tracker.Observe(site, &statuses.State{Date: now.Add(time.Duration(n) * time.Minute), Status: 200, SiteId: site.Id})
+	}
+	tracker.Alert(context.Background(), now.Add(10*time.Minute))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notices) != 3 {
+		t.Fatalf("got %d notices, want 3: %+v", len(notices), notices)
+	}
+	if n := notices[0]; n.Objective != "api" || n.Alert != AlertFastBurn || n.State != StateFiring || n.BurnRate < 14.4 {
+		t.Errorf("first notice = %+v, want the fast burn firing", n)
+	}
+	if n := notices[1]; n.Alert != AlertFastBurn || n.State != StateResolved {
+		t.Errorf("second notice = %+v, want the fast burn resolved", n)
+	}
+	if n := notices[2]; n.Alert != AlertSlowBurn || n.State != StateFiring {
+		t.Errorf("third notice = %+v, want the slow burn firing", n)
+	}
+}
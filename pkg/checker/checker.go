@@ -0,0 +1,364 @@
+package checker
+
+import (
+	"CheckUrls/pkg/limiter"
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StatusUnavailable is saved as status code
+// when no response was received
+const StatusUnavailable = 600
+
+type CheckerConfig interface {
+	GetCheckTimeout() time.Duration
+	GetMaxBodySize() int64
+	GetHostConcurrency() int
+	GetProxy() string
+	GetCertDir() string
+	GetTLSReloadInterval() time.Duration
+}
+
+// Checker sends check requests through shared transports:
+// warm checks reuse keep-alive connections, cold checks
+// dial and handshake on every request.
+type Checker struct {
+	mu          sync.Mutex
+	clients     map[clientKey]*clientEntry
+	loaders     map[string]Loader
+	certDir     string
+	reload      time.Duration
+	swept       time.Time
+	secrets     SecretStore
+	timeout     time.Duration
+	idlePerHost int
+	limiter     *limiter.HostLimiter
+	maxBody     int64
+	proxy       *url.URL
+}
+
+// clientKey identifies the transport: sites with
+// the same mode and TLS settings share connections
+type clientKey struct {
+	cold bool
+	tls  tlsProfile
+}
+
+// failedRetry is how long the failed TLS settings are not read again
+const failedRetry = 30 * time.Second
+
+// clientEntry is the shared client of the mode and TLS settings,
+// it is created by the first check, the other checks wait for it
+type clientEntry struct {
+	// ready is closed when client, err and loaded are set
+	ready  chan struct{}
+	client *http.Client
+	err    error
+	// loaded is when the TLS settings of the client were read
+	loaded time.Time
+	// used is when the client was used by the last check, guarded by Checker.mu
+	used time.Time
+}
+
+// done reports whether the client is created
+func (e *clientEntry) done() bool {
+	select {
+	case <-e.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+type proxyKey struct{}
+
+func NewChecker(cfg CheckerConfig, hostLimiter *limiter.HostLimiter) (*Checker, error) {
+	logger := logging.NewLoggers("checker", "newChecker")
+	var proxy *url.URL
+	if cfg.GetProxy() != "" {
+		var err error
+		if proxy, err = sites.ParseProxy(cfg.GetProxy()); err != nil {
+			logger.ErrorLog().Str("when", "parse proxy").Err(err).Msg("incorrect proxy")
+			return nil, err
+		}
+	}
+
+	var certDir string
+	if cfg.GetCertDir() != "" {
+		var err error
+		if certDir, err = filepath.Abs(cfg.GetCertDir()); err != nil {
+			logger.ErrorLog().Str("when", "find certificate directory").Err(err).
+				Msg("incorrect certificate directory")
+			return nil, err
+		}
+	}
+
+	idlePerHost := cfg.GetHostConcurrency()
+	if idlePerHost < 2 {
+		idlePerHost = 2
+	}
+
+	c := &Checker{
+		clients:     make(map[clientKey]*clientEntry),
+		certDir:     certDir,
+		reload:      cfg.GetTLSReloadInterval(),
+		timeout:     cfg.GetCheckTimeout(),
+		idlePerHost: idlePerHost,
+		limiter:     hostLimiter,
+		maxBody:     cfg.GetMaxBodySize(),
+		proxy:       proxy,
+	}
+	c.loaders = map[string]Loader{"file": c.readFile}
+	return c, nil
+}
+
+// client returns the shared client of the site mode and TLS settings,
+// it is created on the first use and again when the TLS settings
+// are read longer than the reload interval ago. The files and secrets
+// are read without the lock and with the context of the check, so a slow
+// secret store delays only the checks of the sites with these settings.
+func (c *Checker) client(ctx context.Context, site *sites.Site) (*http.Client, error) {
+	key := clientKey{
+		cold: site.CheckMode == sites.CheckModeCold,
+		tls: tlsProfile{
+			cert:       site.ClientCert,
+			key:        site.ClientKey,
+			ca:         site.CaBundle,
+			skipVerify: site.SkipVerify,
+		},
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	c.evict(now)
+	entry, ok := c.clients[key]
+	if ok && c.stale(key, entry, now) {
+		if entry.client != nil {
+			entry.client.CloseIdleConnections()
+		}
+		ok = false
+	}
+	if !ok {
+		entry = &clientEntry{ready: make(chan struct{})}
+		c.clients[key] = entry
+	}
+	entry.used = now
+	c.mu.Unlock()
+
+	if !ok {
+		entry.client, entry.err = c.newClient(ctx, key)
+		entry.loaded = time.Now()
+		if ctx.Err() != nil {
+			// the check was canceled, the next check reads the settings again
+			entry.loaded = time.Time{}
+		}
+		close(entry.ready)
+	}
+	select {
+	case <-entry.ready:
+		return entry.client, entry.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// newClient creates the client of the mode and TLS settings
+func (c *Checker) newClient(ctx context.Context, key clientKey) (*http.Client, error) {
+	tlsConfig, err := c.tlsConfig(ctx, key.tls)
+	if err != nil {
+		return nil, err
+	}
+	transport := newTransport()
+	transport.TLSClientConfig = tlsConfig
+	if key.cold {
+		transport.DisableKeepAlives = true
+	} else {
+		transport.MaxIdleConns = 100
+		transport.MaxIdleConnsPerHost = c.idlePerHost
+	}
+	return newClient(transport, c.timeout), nil
+}
+
+// stale reports whether the files or secrets of the client must be read
+// again, the failed ones are read again after failedRetry. It must be
+// called with c.mu held.
+func (c *Checker) stale(key clientKey, entry *clientEntry, now time.Time) bool {
+	if !entry.done() {
+		return false
+	}
+	if entry.err != nil {
+		return now.Sub(entry.loaded) >= failedRetry
+	}
+	return c.reload > 0 && key.tls.references() && now.Sub(entry.loaded) >= c.reload
+}
+
+// evict closes the clients which were not used for the reload interval,
+// like the clients of deleted sites, it must be called with c.mu held
+func (c *Checker) evict(now time.Time) {
+	if c.reload <= 0 || now.Sub(c.swept) < c.reload {
+		return
+	}
+	c.swept = now
+	for key, entry := range c.clients {
+		if now.Sub(entry.used) >= c.reload && entry.done() {
+			if entry.client != nil {
+				entry.client.CloseIdleConnections()
+			}
+			delete(c.clients, key)
+		}
+	}
+}
+
+// proxyFor returns the proxy of the site check:
+// the proxy of the site, the global one or
+// the proxy from environment. The password of
+// the site proxy is read from the secret store.
+func (c *Checker) proxyFor(site *sites.Site, req *http.Request) (*url.URL, error) {
+	switch {
+	case site.Proxy == sites.ProxyDirect:
+		return nil, nil
+	case site.Proxy != "":
+		proxy, err := sites.ParseProxy(site.Proxy)
+		if err != nil || site.ProxySecret == "" {
+			return proxy, err
+		}
+		password, err := c.secret(req.Context(), site.ProxySecret)
+		if err != nil {
+			return nil, err
+		}
+		proxy.User = url.UserPassword(proxy.User.Username(), string(password))
+		return proxy, nil
+	case c.proxy != nil:
+		return c.proxy, nil
+	default:
+		return http.ProxyFromEnvironment(req)
+	}
+}
+
+// authorize adds the credentials of the site from the secret store
+func (c *Checker) authorize(site *sites.Site, req *http.Request) error {
+	if site.AuthSecret != "" {
+		value, err := c.secret(req.Context(), site.AuthSecret)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", string(value))
+	}
+	if site.BasicAuthUser != "" {
+		password, err := c.secret(req.Context(), site.BasicAuthSecret)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(site.BasicAuthUser, string(password))
+	}
+	return nil
+}
+
+func newTransport() *http.Transport {
+	return &http.Transport{
+		// the proxy is chosen by Check for every request,
+		// connections through different proxies are not shared
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			proxy, _ := req.Context().Value(proxyKey{}).(*url.URL)
+			return proxy, nil
+		},
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   5 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+func newClient(transport http.RoundTripper, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		Timeout: timeout,
+	}
+}
+
+// Check sends GET request to the site and returns its state,
+// errors of the request are saved as StatusUnavailable.
+func (c *Checker) Check(ctx context.Context, site *sites.Site) (*statuses.State, error) {
+	logger := logging.NewLoggers("checker", "check")
+	u, err := url.Parse(site.Url)
+	if err != nil {
+		logger.WarnLog().Err(err).Str("when", "parse url").Msg("unable to parse url")
+		return nil, err
+	}
+
+	if c.limiter != nil {
+		logger.DebugLog().Msg("wait for host limiter")
+		release, err := c.limiter.Wait(ctx, u.Hostname())
+		if err != nil {
+			logger.WarnLog().Err(err).Msg("check canceled")
+			return nil, err
+		}
+		defer release()
+	}
+
+	client, err := c.client(ctx, site)
+	if err != nil {
+		logger.WarnLog().Err(err).Str("when", "load tls settings").Msg("unable to create client")
+		return nil, err
+	}
+	if site.SkipVerify {
+		logger.WarnLog().Int64("site_id", site.Id).Msg("tls verification is skipped")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, site.Url, nil)
+	if err != nil {
+		logger.WarnLog().Err(err).Str("when", "create request").Msg("unable to create request")
+		return nil, err
+	}
+	if err := c.authorize(site, req); err != nil {
+		logger.WarnLog().Err(err).Str("when", "authorize request").Int64("site_id", site.Id).
+			Msg("unable to get secret")
+		return nil, err
+	}
+	proxy, err := c.proxyFor(site, req)
+	if err != nil {
+		logger.WarnLog().Err(err).Str("when", "choose proxy").Msg("incorrect proxy")
+		return nil, err
+	}
+	req = req.WithContext(context.WithValue(ctx, proxyKey{}, proxy))
+
+	logger.DebugLog().Msg("send GET request")
+	state := &statuses.State{SiteId: site.Id, Date: time.Now(), Insecure: site.SkipVerify}
+	if proxy != nil {
+		state.Proxy = proxy.Redacted()
+	}
+	resp, err := client.Do(req)
+	state.Latency = time.Since(state.Date)
+	if err != nil {
+		logger.WarnLog().Err(err).Msg("unable to get response")
+		state.Status = StatusUnavailable
+		return state, nil
+	}
+	state.Status = int64(resp.StatusCode)
+
+	logger.DebugLog().Msg("drain response body")
+	if _, err := io.Copy(ioutil.Discard, io.LimitReader(resp.Body, c.maxBody)); err != nil {
+		logger.DebugLog().Err(err).Msg("unable to drain response body")
+	}
+	if err := resp.Body.Close(); err != nil {
+		logger.DebugLog().Err(err).Msg("unable to close response body")
+	}
+
+	return state, nil
+}
@@ -0,0 +1,438 @@
+package checker
+
+import (
+	"CheckUrls/pkg/limiter"
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository/sites"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	proxy   string
+	certDir string
+	reload  time.Duration
+}
+
+func (c testConfig) GetCheckTimeout() time.Duration      { return 5 * time.Second }
+func (c testConfig) GetMaxBodySize() int64               { return 1024 }
+func (c testConfig) GetHostConcurrency() int             { return 2 }
+func (c testConfig) GetProxy() string                    { return c.proxy }
+func (c testConfig) GetHostRate() float64                { return 0 }
+func (c testConfig) GetHostLimits() string               { return "" }
+func (c testConfig) GetCertDir() string                  { return c.certDir }
+func (c testConfig) GetTLSReloadInterval() time.Duration { return c.reload }
+
+// httpProxy is an in-process stand-in of a forward proxy
+// for plain http requests which requires basic auth.
+func httpProxy(t *testing.T, user, password string) (*httptest.Server, *int64) {
+	var hits int64
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+password))
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") != auth {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		atomic.AddInt64(&hits, 1)
+		r.RequestURI = ""
+		r.Header.Del("Proxy-Authorization")
+		resp, err := http.DefaultTransport.RoundTrip(r)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		_, _ = io.Copy(w, resp.Body)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy, &hits
+}
+
+// socks5Proxy is an in-process stand-in of a socks5 proxy
+// with username/password authentication.
+func socks5Proxy(t *testing.T, user, password string) (string, *int64) {
+	var hits int64
+	listen, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listen.Close() })
+
+	go func() {
+		for {
+			conn, err := listen.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				target, ok := socks5Handshake(conn, user, password)
+				if !ok {
+					return
+				}
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					_, _ = conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer upstream.Close()
+				atomic.AddInt64(&hits, 1)
+				_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+	return listen.Addr().String(), &hits
+}
+
+func socks5Handshake(conn net.Conn, user, password string) (string, bool) {
+	buf := make([]byte, 262)
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", false
+	}
+	if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+		return "", false
+	}
+	_, _ = conn.Write([]byte{5, 2})
+
+	// username/password sub-negotiation
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", false
+	}
+	u := make([]byte, buf[1])
+	if _, err := io.ReadFull(conn, u); err != nil {
+		return "", false
+	}
+	if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+		return "", false
+	}
+	p := make([]byte, buf[0])
+	if _, err := io.ReadFull(conn, p); err != nil {
+		return "", false
+	}
+	if string(u) != user || string(p) != password {
+		_, _ = conn.Write([]byte{1, 1})
+		return "", false
+	}
+	_, _ = conn.Write([]byte{1, 0})
+
+	// connect request
+	if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+		return "", false
+	}
+	var host string
+	switch buf[3] {
+	case 1:
+		if _, err := io.ReadFull(conn, buf[:4]); err != nil {
+			return "", false
+		}
+		host = net.IP(buf[:4]).String()
+	case 3:
+		if _, err := io.ReadFull(conn, buf[:1]); err != nil {
+			return "", false
+		}
+		name := make([]byte, buf[0])
+		if _, err := io.ReadFull(conn, name); err != nil {
+			return "", false
+		}
+		host = string(name)
+	default:
+		return "", false
+	}
+	if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+		return "", false
+	}
+	port := binary.BigEndian.Uint16(buf[:2])
+	return net.JoinHostPort(host, strconv.Itoa(int(port))), true
+}
+
+func target(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCheckModes(t *testing.T) {
+	tests := []struct {
+		mode  string
+		conns int64
+	}{
+		// warm checks reuse the keep-alive connection
+		{sites.CheckModeWarm, 1},
+		// cold checks dial on every check
+		{sites.CheckModeCold, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var conns int64
+			srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			}))
+			srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt64(&conns, 1)
+				}
+			}
+			srv.Start()
+			t.Cleanup(srv.Close)
+
+			c, err := NewChecker(testConfig{}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the sites of the same mode share the transport
+			for id := int64(1); id <= 3; id++ {
+				state, err := c.Check(context.Background(), &sites.Site{Id: id, Url: srv.URL, CheckMode: tt.mode})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if state.Status != http.StatusOK || state.Latency <= 0 {
+					t.Errorf("state = %+v", *state)
+				}
+			}
+			if got := atomic.LoadInt64(&conns); got != tt.conns {
+				t.Errorf("%d connections for 3 checks, want %d", got, tt.conns)
+			}
+		})
+	}
+}
+
+func TestSharedClients(t *testing.T) {
+	c, err := NewChecker(testConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := func(site *sites.Site) *http.Client {
+		t.Helper()
+		cl, err := c.client(context.Background(), site)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cl
+	}
+	warm := client(&sites.Site{Id: 1, CheckMode: sites.CheckModeWarm})
+	if other := client(&sites.Site{Id: 2, CheckMode: sites.CheckModeWarm}); other != warm {
+		t.Error("warm sites do not share the client")
+	}
+	if cold := client(&sites.Site{Id: 3, CheckMode: sites.CheckModeCold}); cold == warm {
+		t.Error("cold site shares the client of warm sites")
+	}
+	if insecure := client(&sites.Site{Id: 4, CheckMode: sites.CheckModeWarm, SkipVerify: true}); insecure == warm {
+		t.Error("site with other TLS settings shares the client")
+	}
+}
+
+func TestCheckBodyCap(t *testing.T) {
+	// the target sends the body until the checker closes the connection
+	written := make(chan int64, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := make([]byte, 32*1024)
+		var n int64
+		for n < 1<<30 {
+			m, err := w.Write(chunk)
+			n += int64(m)
+			if err != nil {
+				break
+			}
+		}
+		written <- n
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewChecker(testConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	state, err := c.Check(context.Background(), &sites.Site{Id: 1, Url: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != http.StatusOK {
+		t.Errorf("status = %d, want %d", state.Status, http.StatusOK)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("check of endless body took %v", elapsed)
+	}
+	select {
+	case n := <-written:
+		if n >= 1<<30 {
+			t.Errorf("the whole body of %d bytes is read", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("the connection of the capped body is not closed")
+	}
+}
+
+func TestCheckThroughSiteProxy(t *testing.T) {
+	srv := target(t)
+	proxy, hits := httpProxy(t, "user", "secret")
+
+	c, err := NewChecker(testConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyURL := "http://user:secret@" + proxy.Listener.Addr().String()
+	state, err := c.Check(context.Background(), &sites.Site{Id: 1, Url: srv.URL, Proxy: proxyURL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Status != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", state.Status, http.StatusAccepted)
+	}
+	if atomic.LoadInt64(hits) != 1 {
+		t.Errorf("proxy hits = %d, want 1", atomic.LoadInt64(hits))
+	}
+	want := "http://user:xxxxx@" + proxy.Listener.Addr().String()
+	if state.Proxy != want {
+		t.Errorf("proxy = %q, want %q", state.Proxy, want)
+	}
+}
+
+// secretStore keeps the secrets by name
+type secretStore map[string]logging.Secret
+
+func (s secretStore) Get(ctx context.Context, name string) (logging.Secret, error) {
+	value, ok := s[name]
+	if !ok {
+		return "", errors.New("secret not found")
+	}
+	return value, nil
+}
+
+func TestCheckThroughProxyWithSecret(t *testing.T) {
+	srv := target(t)
+	proxy, hits := httpProxy(t, "user", "secret")
+
+	c, err := NewChecker(testConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	site := &sites.Site{Id: 1, Url: srv.URL, Proxy: "http://user@" + proxy.Listener.Addr().String(),
+		ProxySecret: "proxy"}
+	if _, err := c.Check(context.Background(), site); err != ErrNoSecrets {
+		t.Errorf("check without secret store = %v, want %v", err, ErrNoSecrets)
+	}
+
+	c.SetSecrets(secretStore{"proxy": "secret"})
+	state, err := c.Check(context.Background(), site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Status != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", state.Status, http.StatusAccepted)
+	}
+	if atomic.LoadInt64(hits) != 1 {
+		t.Errorf("proxy hits = %d, want 1", atomic.LoadInt64(hits))
+	}
+	want := "http://user:xxxxx@" + proxy.Listener.Addr().String()
+	if state.Proxy != want {
+		t.Errorf("proxy = %q, want %q", state.Proxy, want)
+	}
+	if site.Proxy != "http://user@"+proxy.Listener.Addr().String() {
+		t.Errorf("proxy of the site is changed to %q", site.Proxy)
+	}
+}
+
+func TestCheckThroughGlobalSocks5Proxy(t *testing.T) {
+	srv := target(t)
+	addr, hits := socks5Proxy(t, "user", "secret")
+
+	c, err := NewChecker(testConfig{proxy: "socks5://user:secret@" + addr}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := c.Check(context.Background(), &sites.Site{Id: 1, Url: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Status != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", state.Status, http.StatusAccepted)
+	}
+	if atomic.LoadInt64(hits) != 1 {
+		t.Errorf("proxy hits = %d, want 1", atomic.LoadInt64(hits))
+	}
+	if state.Proxy != "socks5://user:xxxxx@"+addr {
+		t.Errorf("proxy = %q", state.Proxy)
+	}
+}
+
+func TestCheckDirectBypassesGlobalProxy(t *testing.T) {
+	srv := target(t)
+	proxy, hits := httpProxy(t, "user", "secret")
+
+	c, err := NewChecker(testConfig{proxy: "http://user:secret@" + proxy.Listener.Addr().String()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := c.Check(context.Background(), &sites.Site{Id: 1, Url: srv.URL, Proxy: sites.ProxyDirect})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Status != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", state.Status, http.StatusAccepted)
+	}
+	if atomic.LoadInt64(hits) != 0 {
+		t.Errorf("proxy hits = %d, want 0", atomic.LoadInt64(hits))
+	}
+	if state.Proxy != "" {
+		t.Errorf("proxy = %q, want empty", state.Proxy)
+	}
+}
+
+func TestCheckWrongProxyCredentials(t *testing.T) {
+	srv := target(t)
+	proxy, _ := httpProxy(t, "user", "secret")
+
+	c, err := NewChecker(testConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyURL := &url.URL{Scheme: "http", User: url.UserPassword("user", "wrong"), Host: proxy.Listener.Addr().String()}
+	state, err := c.Check(context.Background(), &sites.Site{Id: 1, Url: srv.URL, Proxy: proxyURL.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Status != http.StatusProxyAuthRequired {
+		t.Errorf("status = %d, want %d", state.Status, http.StatusProxyAuthRequired)
+	}
+}
+
+func TestNewCheckerIncorrectProxy(t *testing.T) {
+	if _, err := NewChecker(testConfig{proxy: "ftp://proxy:21"}, nil); err == nil {
+		t.Error("expected error for ftp proxy")
+	}
+}
+
+func TestCheckIncorrectUrl(t *testing.T) {
+	hostLimiter, err := limiter.NewHostLimiter(testConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewChecker(testConfig{}, hostLimiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Check(context.Background(), &sites.Site{Id: 1, Url: "http://[::1"}); err == nil {
+		t.Error("expected error for incorrect url")
+	}
+}
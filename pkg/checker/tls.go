@@ -0,0 +1,162 @@
+package checker
+
+import (
+	"CheckUrls/pkg/logging"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// SecretScheme is the scheme of references to the secret store
+const SecretScheme = "secret"
+
+var (
+	ErrIncorrectCA    = fmt.Errorf("no certificates in CA bundle")
+	ErrNoSecrets      = fmt.Errorf("secret store is not configured")
+	ErrFileNotAllowed = fmt.Errorf("file is outside of the certificate directory")
+)
+
+type SecretStore interface {
+	Get(ctx context.Context, name string) (logging.Secret, error)
+}
+
+// Loader returns the content of a certificate,
+// a key or a CA bundle by its reference.
+type Loader func(ctx context.Context, name string) ([]byte, error)
+
+// tlsProfile is the TLS settings of the site
+type tlsProfile struct {
+	cert       string
+	key        string
+	ca         string
+	skipVerify bool
+}
+
+// references reports whether the profile is read from files or secrets
+func (p tlsProfile) references() bool {
+	return p.cert != "" || p.ca != ""
+}
+
+// RegisterLoader adds the loader of references
+// in the form "<scheme>:<name>". References without
+// registered scheme are read as files by readFile.
+func (c *Checker) RegisterLoader(scheme string, loader Loader) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loaders[scheme] = loader
+}
+
+// SetSecrets enables references "secret:<name>"
+// and authorization of the checks with secrets
+func (c *Checker) SetSecrets(store SecretStore) {
+	c.RegisterLoader(SecretScheme, func(ctx context.Context, name string) ([]byte, error) {
+		value, err := store.Get(ctx, name)
+		return []byte(value), err
+	})
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.secrets = store
+}
+
+// secret returns the value of the secret by name
+func (c *Checker) secret(ctx context.Context, name string) (logging.Secret, error) {
+	c.mu.Lock()
+	store := c.secrets
+	c.mu.Unlock()
+	if store == nil {
+		return "", ErrNoSecrets
+	}
+	return store.Get(ctx, name)
+}
+
+// load reads the reference by the loader of its scheme
+func (c *Checker) load(ctx context.Context, ref string) ([]byte, error) {
+	if i := strings.Index(ref, ":"); i > 0 {
+		c.mu.Lock()
+		loader, ok := c.loaders[ref[:i]]
+		c.mu.Unlock()
+		if ok {
+			return loader(ctx, ref[i+1:])
+		}
+	}
+	return c.readFile(ctx, ref)
+}
+
+// readFile reads the file inside the certificate directory, relative
+// paths are relative to it. No file is read when it is not set,
+// so the callers of the API can not read any file of the server.
+// The symlinks are followed before the check, so a link inside the
+// directory can't point to a file outside of it.
+func (c *Checker) readFile(_ context.Context, name string) ([]byte, error) {
+	if c.certDir == "" {
+		return nil, fmt.Errorf("%w: %q", ErrFileNotAllowed, name)
+	}
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.certDir, path)
+	}
+	// the path is checked before it is resolved too, so
+	// the files outside are not even looked up
+	if !inside(c.certDir, filepath.Clean(path)) {
+		return nil, fmt.Errorf("%w: %q", ErrFileNotAllowed, name)
+	}
+	dir, err := filepath.EvalSymlinks(c.certDir)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	if !inside(dir, resolved) {
+		return nil, fmt.Errorf("%w: %q", ErrFileNotAllowed, name)
+	}
+	return ioutil.ReadFile(resolved)
+}
+
+// inside reports whether the clean path is inside the directory
+func inside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// tlsConfig reads the files and secrets of the TLS settings
+func (c *Checker) tlsConfig(ctx context.Context, profile tlsProfile) (*tls.Config, error) {
+	if profile == (tlsProfile{}) {
+		return nil, nil
+	}
+	cfg := &tls.Config{InsecureSkipVerify: profile.skipVerify}
+
+	if profile.cert != "" {
+		certPEM, err := c.load(ctx, profile.cert)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := c.load(ctx, profile.key)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if profile.ca != "" {
+		caPEM, err := c.load(ctx, profile.ca)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, ErrIncorrectCA
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
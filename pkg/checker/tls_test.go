@@ -0,0 +1,329 @@
+package checker
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository/sites"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	parentCert, parentKey := template, key
+	if parent != nil {
+		parentCert, parentKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func TestCheckMutualTLS(t *testing.T) {
+	ca := newCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	server := newCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	client := newCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "checker"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	serverCert, err := tls.X509KeyPair(server.certPEM, server.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	c, err := NewChecker(testConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := map[string][]byte{"cert": client.certPEM, "key": client.keyPEM, "ca": ca.certPEM}
+	c.RegisterLoader("mem", func(_ context.Context, name string) ([]byte, error) {
+		return store[name], nil
+	})
+
+	tests := []struct {
+		name     string
+		site     *sites.Site
+		status   int64
+		insecure bool
+	}{
+		{
+			name:   "client certificate and CA bundle",
+			site:   &sites.Site{Url: srv.URL, ClientCert: "mem:cert", ClientKey: "mem:key", CaBundle: "mem:ca"},
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "unknown CA",
+			site:   &sites.Site{Url: srv.URL, ClientCert: "mem:cert", ClientKey: "mem:key"},
+			status: StatusUnavailable,
+		},
+		{
+			name:     "skip verify",
+			site:     &sites.Site{Url: srv.URL, ClientCert: "mem:cert", ClientKey: "mem:key", SkipVerify: true},
+			status:   http.StatusNoContent,
+			insecure: true,
+		},
+		{
+			name:   "without client certificate",
+			site:   &sites.Site{Url: srv.URL, CaBundle: "mem:ca"},
+			status: StatusUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := c.Check(context.Background(), tt.site)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state.Status != tt.status {
+				t.Errorf("status = %d, want %d", state.Status, tt.status)
+			}
+			if state.Insecure != tt.insecure {
+				t.Errorf("insecure = %v, want %v", state.Insecure, tt.insecure)
+			}
+		})
+	}
+}
+
+func TestFileReferences(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ca.pem"), []byte("ca"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(outside, []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "escape.pem")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("ca.pem", filepath.Join(dir, "link.pem")); err != nil {
+		t.Fatal(err)
+	}
+	// the certificate directory itself may be a link
+	linkedDir := filepath.Join(t.TempDir(), "certs")
+	if err := os.Symlink(dir, linkedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		certDir string
+		ref     string
+		err     error
+	}{
+		{"relative path", dir, "ca.pem", nil},
+		{"file reference", dir, "file:ca.pem", nil},
+		{"absolute path inside", dir, filepath.Join(dir, "ca.pem"), nil},
+		{"missing file inside", dir, "missing.pem", os.ErrNotExist},
+		{"parent directory", dir, "../" + filepath.Base(filepath.Dir(outside)) + "/key.pem", ErrFileNotAllowed},
+		{"absolute path outside", dir, outside, ErrFileNotAllowed},
+		{"file reference outside", dir, "file:" + outside, ErrFileNotAllowed},
+		{"link outside", dir, "escape.pem", ErrFileNotAllowed},
+		{"file reference to link outside", dir, "file:escape.pem", ErrFileNotAllowed},
+		{"link inside", dir, "link.pem", nil},
+		{"linked directory", linkedDir, "ca.pem", nil},
+		{"link outside in linked directory", linkedDir, "escape.pem", ErrFileNotAllowed},
+		{"without directory", "", outside, ErrFileNotAllowed},
+		{"missing file without directory", "", "/missing.pem", ErrFileNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewChecker(testConfig{certDir: tt.certDir}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := c.load(context.Background(), tt.ref)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Errorf("load(%q) = %q, %v, want %v", tt.ref, data, err, tt.err)
+				}
+				return
+			}
+			if err != nil || string(data) != "ca" {
+				t.Errorf("load(%q) = %q, %v", tt.ref, data, err)
+			}
+		})
+	}
+}
+
+func TestClientReload(t *testing.T) {
+	ca := newCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	c, err := NewChecker(testConfig{reload: 200 * time.Millisecond}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loads int64
+	c.RegisterLoader("mem", func(_ context.Context, name string) ([]byte, error) {
+		atomic.AddInt64(&loads, 1)
+		return ca.certPEM, nil
+	})
+	client := func(site *sites.Site) *http.Client {
+		t.Helper()
+		cl, err := c.client(context.Background(), site)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cl
+	}
+
+	site := &sites.Site{Id: 1, CaBundle: "mem:ca"}
+	first := client(site)
+	if second := client(site); second != first || atomic.LoadInt64(&loads) != 1 {
+		t.Errorf("CA bundle is read %d times by 2 checks", loads)
+	}
+	plain := client(&sites.Site{Id: 2})
+
+	// the CA bundle is read again after the interval,
+	// the client without TLS settings is kept while it is used
+	time.Sleep(120 * time.Millisecond)
+	client(&sites.Site{Id: 2})
+	time.Sleep(120 * time.Millisecond)
+	if client(&sites.Site{Id: 2}) != plain {
+		t.Error("client without TLS settings is created again")
+	}
+	if reloaded := client(site); reloaded == first || atomic.LoadInt64(&loads) != 2 {
+		t.Errorf("CA bundle is read %d times after the interval", loads)
+	}
+
+	// the clients not used for the interval are evicted
+	time.Sleep(250 * time.Millisecond)
+	client(site)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.clients) != 1 {
+		t.Errorf("%d clients are kept, want 1", len(c.clients))
+	}
+}
+
+// slowStore waits for the end of the check for the secret "slow"
+// and fails for the other secrets
+type slowStore struct {
+	gets int64
+}
+
+func (s *slowStore) Get(ctx context.Context, name string) (logging.Secret, error) {
+	atomic.AddInt64(&s.gets, 1)
+	if name == "slow" {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	return "", errors.New("secret not found")
+}
+
+func TestSlowSecretStore(t *testing.T) {
+	srv := target(t)
+	c, err := NewChecker(testConfig{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &slowStore{}
+	c.SetSecrets(store)
+
+	// the check waiting for the secret store does not stall the other checks
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	slow := make(chan error, 1)
+	go func() {
+		_, err := c.Check(ctx, &sites.Site{Id: 1, Url: srv.URL, CaBundle: "secret:slow"})
+		slow <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	if _, err := c.Check(context.Background(), &sites.Site{Id: 2, Url: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("check of another site waited %v", elapsed)
+	}
+	if err := <-slow; err != context.DeadlineExceeded {
+		t.Errorf("check waiting for the secret = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// the canceled read is not kept, the failed one is
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Check(ctx, &sites.Site{Id: 1, Url: srv.URL, CaBundle: "secret:slow"}); err == nil {
+		t.Error("expected error of canceled check")
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := c.Check(context.Background(), &sites.Site{Id: 3, Url: srv.URL, CaBundle: "secret:bad"}); err == nil {
+			t.Error("expected error of missing secret")
+		}
+	}
+	if gets := atomic.LoadInt64(&store.gets); gets != 3 {
+		t.Errorf("secret store is asked %d times, want 3", gets)
+	}
+}
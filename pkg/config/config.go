@@ -1,16 +1,50 @@
 package config
 
-import "github.com/rs/zerolog"
+import (
+	"github.com/rs/zerolog"
+	"time"
+)
 
 type EnvCache struct {
-	ServerAddress string `envconfig:"SERVERADDRESS" required:true`
-	LogLevel      string `envconfig:"LOGLEVEL" required:true`
-	DbHost        string `envconfig:"HOST" required:true`
-	DbPort        string `envconfig:"PORT" required:true`
-	DbUser        string `envconfig:"USER" required:true`
-	DbPassword    string `envconfig:"PASSWORD" required:true`
-	DbName        string `envconfig:"NAME" required:true`
-	DbSslmode     string `envconfig:"SSLMODE" required:true`
+	ServerAddress string        `envconfig:"SERVERADDRESS" required:true`
+	LogLevel      string        `envconfig:"LOGLEVEL" required:true`
+	DbDriver      string        `envconfig:"DBDRIVER" default:"postgres"`
+	DbPath        string        `envconfig:"DBPATH" default:"checkUrls.db"`
+	DbHost        string        `envconfig:"HOST" required:true`
+	DbPort        string        `envconfig:"PORT" required:true`
+	DbUser        string        `envconfig:"USER" required:true`
+	DbPassword    string        `envconfig:"PASSWORD" required:true`
+	DbName        string        `envconfig:"NAME" required:true`
+	DbSslmode     string        `envconfig:"SSLMODE" required:true`
+	DbMaxOpen     int           `envconfig:"DBMAXOPENCONNS" default:"20"`
+	DbMaxIdle     int           `envconfig:"DBMAXIDLECONNS" default:"5"`
+	DbLifetime    time.Duration `envconfig:"DBCONNMAXLIFETIME" default:"30m"`
+	DbIdleTime    time.Duration `envconfig:"DBCONNMAXIDLETIME" default:"5m"`
+	DbTimeout     time.Duration `envconfig:"DBQUERYTIMEOUT" default:"5s"`
+	Jitter        time.Duration `envconfig:"JITTER" default:"0s"`
+	StartupRate   int           `envconfig:"STARTUPRATE" default:"10"`
+	HostConcur    int           `envconfig:"HOSTCONCURRENCY" default:"4"`
+	HostRate      float64       `envconfig:"HOSTRATE" default:"2"`
+	HostLimits    string        `envconfig:"HOSTLIMITS"`
+	MetricsAddr   string        `envconfig:"METRICSADDRESS"`
+	CheckTimeout  time.Duration `envconfig:"CHECKTIMEOUT" default:"10s"`
+	MaxBodySize   int64         `envconfig:"MAXBODYSIZE" default:"1048576"`
+	Proxy         string        `envconfig:"CHECKPROXY"`
+	CertDir       string        `envconfig:"CERTDIR"`
+	TLSReload     time.Duration `envconfig:"TLSRELOADINTERVAL" default:"5m"`
+	SecretsKey    string        `envconfig:"SECRETSKEYFILE"`
+	RawDays       int           `envconfig:"RAWRETENTIONDAYS" default:"30"`
+	HourlyMonths  int           `envconfig:"HOURLYRETENTIONMONTHS" default:"3"`
+	CompactEvery  time.Duration `envconfig:"COMPACTINTERVAL" default:"1h"`
+	WriteBatch    int           `envconfig:"WRITEBATCHSIZE" default:"500"`
+	WriteInterval time.Duration `envconfig:"WRITEFLUSHINTERVAL" default:"1s"`
+	WriteQueue    int           `envconfig:"WRITEQUEUESIZE" default:"10000"`
+	SpoolDir      string        `envconfig:"SPOOLDIR"`
+	SpoolMaxBytes int64         `envconfig:"SPOOLMAXBYTES" default:"104857600"`
+	SloInterval   time.Duration `envconfig:"SLOINTERVAL" default:"1m"`
+	SloFastBurn   float64       `envconfig:"SLOFASTBURN" default:"14.4"`
+	SloSlowBurn   float64       `envconfig:"SLOSLOWBURN" default:"6"`
+	SloWebhook    string        `envconfig:"SLOWEBHOOK"`
 }
 
 // GetServerAddress get server and client address
@@ -34,6 +68,16 @@ func (e EnvCache) GetLogLevel() zerolog.Level {
 	}
 }
 
+// GetDbDriver returns DB driver: postgres or sqlite
+func (e *EnvCache) GetDbDriver() string {
+	return e.DbDriver
+}
+
+// GetDbPath returns path of SQLite database file
+func (e *EnvCache) GetDbPath() string {
+	return e.DbPath
+}
+
 // GetDbHost returns DB host
 func (e *EnvCache) GetDbHost() string {
 	return e.DbHost
@@ -63,3 +107,164 @@ func (e *EnvCache) GetDbName() string {
 func (e *EnvCache) GetDbSslmode() string {
 	return e.DbSslmode
 }
+
+// GetDbMaxOpenConns returns max open connections of the pool
+func (e *EnvCache) GetDbMaxOpenConns() int {
+	return e.DbMaxOpen
+}
+
+// GetDbMaxIdleConns returns max idle connections of the pool
+func (e *EnvCache) GetDbMaxIdleConns() int {
+	return e.DbMaxIdle
+}
+
+// GetDbConnMaxLifetime returns the time after which a connection is reopened
+func (e *EnvCache) GetDbConnMaxLifetime() time.Duration {
+	return e.DbLifetime
+}
+
+// GetDbConnMaxIdleTime returns the time after which an idle connection is closed
+func (e *EnvCache) GetDbConnMaxIdleTime() time.Duration {
+	return e.DbIdleTime
+}
+
+// GetDbQueryTimeout returns the timeout of a single statement
+func (e *EnvCache) GetDbQueryTimeout() time.Duration {
+	return e.DbTimeout
+}
+
+// GetJitter returns the maximum random delay
+// added to every scheduled check
+func (e *EnvCache) GetJitter() time.Duration {
+	return e.Jitter
+}
+
+// GetStartupRate returns how many overdue checks
+// per second are started after restart
+func (e *EnvCache) GetStartupRate() int {
+	return e.StartupRate
+}
+
+// GetHostConcurrency returns the default number
+// of concurrent checks of a single host
+func (e *EnvCache) GetHostConcurrency() int {
+	return e.HostConcur
+}
+
+// GetHostRate returns the default number
+// of checks per second of a single host
+func (e *EnvCache) GetHostRate() float64 {
+	return e.HostRate
+}
+
+// GetHostLimits returns per-host overrides
+// of concurrency and rate
+func (e *EnvCache) GetHostLimits() string {
+	return e.HostLimits
+}
+
+// GetMetricsAddress returns address of metrics endpoint
+func (e *EnvCache) GetMetricsAddress() string {
+	return e.MetricsAddr
+}
+
+// GetCheckTimeout returns timeout of a single check
+func (e *EnvCache) GetCheckTimeout() time.Duration {
+	return e.CheckTimeout
+}
+
+// GetMaxBodySize returns how many bytes of
+// response body are read before the connection is reused
+func (e *EnvCache) GetMaxBodySize() int64 {
+	return e.MaxBodySize
+}
+
+// GetProxy returns the proxy of all checks
+func (e *EnvCache) GetProxy() string {
+	return e.Proxy
+}
+
+// GetCertDir returns the directory of certificates, keys
+// and CA bundles, files are not read if it is empty
+func (e *EnvCache) GetCertDir() string {
+	return e.CertDir
+}
+
+// GetTLSReloadInterval returns how often certificates, keys
+// and CA bundles are read again, they are read once if it is not positive
+func (e *EnvCache) GetTLSReloadInterval() time.Duration {
+	return e.TLSReload
+}
+
+// GetSecretsKeyFile returns path of the file
+// with keys of the secret store
+func (e *EnvCache) GetSecretsKeyFile() string {
+	return e.SecretsKey
+}
+
+// GetRawRetentionDays returns how many days raw states
+// are kept, they are kept forever if it is not positive
+func (e *EnvCache) GetRawRetentionDays() int {
+	return e.RawDays
+}
+
+// GetHourlyRetentionMonths returns how many months hourly
+// rollups are kept, they are kept forever if it is not positive
+func (e *EnvCache) GetHourlyRetentionMonths() int {
+	return e.HourlyMonths
+}
+
+// GetCompactInterval returns how often the history is compacted
+func (e *EnvCache) GetCompactInterval() time.Duration {
+	return e.CompactEvery
+}
+
+// GetWriteBatchSize returns the max number of states saved at once
+func (e *EnvCache) GetWriteBatchSize() int {
+	return e.WriteBatch
+}
+
+// GetWriteFlushInterval returns how long states
+// wait for the batch to be filled
+func (e *EnvCache) GetWriteFlushInterval() time.Duration {
+	return e.WriteInterval
+}
+
+// GetWriteQueueSize returns the number of states queued
+// for saving, checks wait when the queue is full
+func (e *EnvCache) GetWriteQueueSize() int {
+	return e.WriteQueue
+}
+
+// GetSpoolDir returns the directory of results
+// which were not saved in DB, spool is disabled if empty
+func (e *EnvCache) GetSpoolDir() string {
+	return e.SpoolDir
+}
+
+// GetSpoolMaxBytes returns the size limit of the spool, 0 - without limit
+func (e *EnvCache) GetSpoolMaxBytes() int64 {
+	return e.SpoolMaxBytes
+}
+
+// GetBudgetInterval returns how often the error budgets are
+// checked for alerts, they are not tracked if it is not positive
+func (e *EnvCache) GetBudgetInterval() time.Duration {
+	return e.SloInterval
+}
+
+// GetFastBurnRate returns the burn rate of the fast burn alert
+func (e *EnvCache) GetFastBurnRate() float64 {
+	return e.SloFastBurn
+}
+
+// GetSlowBurnRate returns the burn rate of the slow burn alert
+func (e *EnvCache) GetSlowBurnRate() float64 {
+	return e.SloSlowBurn
+}
+
+// GetAlertWebhook returns the url which receives
+// the burn rate alerts, alerts are only logged if empty
+func (e *EnvCache) GetAlertWebhook() string {
+	return e.SloWebhook
+}
@@ -6,26 +6,42 @@ import (
 	"database/sql"
 	"fmt"
 	_ "github.com/jackc/pgx/v4/stdlib"
+	_ "modernc.org/sqlite"
 	"time"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSqlite   = "sqlite"
+)
+
 var (
-	ErrNothingDone = fmt.Errorf("sql query did nothing")
+	ErrNothingDone   = fmt.Errorf("sql query did nothing")
+	ErrUnknownDriver = fmt.Errorf("unknown DB driver")
 	//ConnMnr = &ConnectionManager{}
 )
 
 type DbConfig interface {
+	GetDbDriver() string
+	GetDbPath() string
 	GetDbHost() string
 	GetDbPort() string
 	GetDbUser() string
 	GetDbPassword() string
 	GetDbName() string
 	GetDbSslmode() string
+	GetDbMaxOpenConns() int
+	GetDbMaxIdleConns() int
+	GetDbConnMaxLifetime() time.Duration
+	GetDbConnMaxIdleTime() time.Duration
+	GetDbQueryTimeout() time.Duration
 }
 
 type ConnectionManager struct {
 	Conn *sql.DB
 	log  *logging.Loggers
+	// queryTimeout limits a single statement, 0 - only by the context
+	queryTimeout time.Duration
 }
 
 func NewConnectionManager() *ConnectionManager {
@@ -43,6 +59,61 @@ type sqlInfo struct {
 
 func (c *ConnectionManager) Connect(cfg DbConfig) error {
 	c.log = logging.NewLoggers("db", "connect")
+	var err error
+	switch cfg.GetDbDriver() {
+	case DriverPostgres:
+		err = c.connectPostgres(cfg)
+	case DriverSqlite:
+		err = c.connectSqlite(cfg)
+	default:
+		c.log.ErrorLog().Str("when", "choose driver").Str("driver", cfg.GetDbDriver()).
+			Msg("unknown DB driver")
+		return ErrUnknownDriver
+	}
+	if err != nil {
+		return err
+	}
+	// zero settings keep the defaults of database/sql
+	if n := cfg.GetDbMaxOpenConns(); n > 0 {
+		c.Conn.SetMaxOpenConns(n)
+	}
+	if n := cfg.GetDbMaxIdleConns(); n > 0 {
+		c.Conn.SetMaxIdleConns(n)
+	}
+	if d := cfg.GetDbConnMaxLifetime(); d > 0 {
+		c.Conn.SetConnMaxLifetime(d)
+	}
+	if d := cfg.GetDbConnMaxIdleTime(); d > 0 {
+		c.Conn.SetConnMaxIdleTime(d)
+	}
+	c.queryTimeout = cfg.GetDbQueryTimeout()
+	return nil
+}
+
+// connectSqlite opens the database file, foreign keys are
+// enabled and times are stored in UTC text format, so they
+// can be compared and sorted in queries. Transactions take
+// the write lock on begin, so the rows they read are not
+// changed by other connections until the commit.
+func (c *ConnectionManager) connectSqlite(cfg DbConfig) error {
+	connector := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"+
+		"&_pragma=journal_mode(WAL)&_time_format=sqlite&_txlock=immediate", cfg.GetDbPath())
+
+	var err error
+	c.Conn, err = sql.Open("sqlite", connector)
+	if err != nil {
+		c.log.ErrorLog().Str("when", "open connection").Err(err).Msg("failed to open connection")
+		return err
+	}
+	if err := c.Conn.Ping(); err != nil {
+		c.log.ErrorLog().Str("when", "ping connection").Err(err).Msg("failed to ping connection")
+		return err
+	}
+
+	return nil
+}
+
+func (c *ConnectionManager) connectPostgres(cfg DbConfig) error {
 	sq := sqlInfo{
 		host:     cfg.GetDbHost(),
 		port:     cfg.GetDbPort(),
@@ -79,66 +150,148 @@ func (c *ConnectionManager) Close() error {
 	return nil
 }
 
-func (c *ConnectionManager) Exec(query string, args ...interface{}) error {
-	c.log = logging.NewLoggers("db", "exec")
-	if err := c.Conn.Ping(); err != nil {
-		c.log.ErrorLog().Str("when", "ping connection").Err(err).Msg("failed to ping connection")
-		return err
-	}
-	ctx := context.TODO()
-	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+// Exec runs the statement, it returns ErrNothingDone if no rows were affected
+func (c *ConnectionManager) Exec(ctx context.Context, query string, args ...interface{}) error {
+	logger := logging.NewLoggers("db", "exec")
+	queryCtx, cancel := c.withTimeout(ctx)
 	defer cancel()
 
 	result, err := c.Conn.ExecContext(queryCtx, query, args...)
 	if err != nil {
-		c.log.ErrorLog().Str("when", "exec").Err(err).Msg("error at exec")
+		logger.ErrorLog().Str("when", "exec").Err(err).Msg("error at exec")
 		return err
 	}
 	rows, err := result.RowsAffected()
 	if err != nil {
-		c.log.ErrorLog().Str("when", "get rows").Err(err).Msg("failed to get rows")
+		logger.ErrorLog().Str("when", "get rows").Err(err).Msg("failed to get rows")
 		return err
 	}
 	if rows == 0 {
-		c.log.WarnLog().Msg("no rows")
+		logger.WarnLog().Msg("no rows")
 		return ErrNothingDone
 	}
 	return nil
 }
 
-func (c *ConnectionManager) QueryRow(query string, args ...interface{}) (*sql.Row, func(), error) {
-	c.log = logging.NewLoggers("db", "queryRow")
-	if err := c.Conn.Ping(); err != nil {
-		c.log.ErrorLog().Str("when", "ping connection").Err(err).Msg("failed to ping connection")
-		return nil, nil, err
-	}
-	ctx := context.TODO()
-	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+// QueryRow runs the query, cancel must be called after the row is scanned
+func (c *ConnectionManager) QueryRow(ctx context.Context, query string, args ...interface{}) (*sql.Row, func(), error) {
+	queryCtx, cancel := c.withTimeout(ctx)
 
 	row := c.Conn.QueryRowContext(queryCtx, query, args...)
 
 	return row, cancel, nil
 }
 
-func (c *ConnectionManager) Query(query string, args ...interface{}) (*sql.Rows, func(), error) {
-	c.log = logging.NewLoggers("db", "query")
-	if err := c.Conn.Ping(); err != nil {
-		c.log.ErrorLog().Str("when", "ping connection").Err(err).Msg("failed to ping connection")
-		return nil, nil, err
-	}
-	ctx := context.TODO()
-	queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+// Query runs the query, cancel must be called after the rows are closed
+func (c *ConnectionManager) Query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, func(), error) {
+	logger := logging.NewLoggers("db", "query")
+	queryCtx, cancel := c.withTimeout(ctx)
 
 	rows, err := c.Conn.QueryContext(queryCtx, query, args...)
 	if err != nil {
-		c.log.ErrorLog().Str("when", "get rows").Err(err).Msg("failed to get rows")
+		logger.ErrorLog().Str("when", "get rows").Err(err).Msg("failed to get rows")
 		defer cancel()
 		return nil, nil, err
 	}
 	if rows == nil {
-		c.log.WarnLog().Msg("no rows")
+		logger.WarnLog().Msg("no rows")
 		return nil, cancel, ErrNothingDone
 	}
 
 	return rows, cancel, nil
 }
+
+// Tx is the transaction of ConnectionManager.Tx, every statement
+// is limited by the context it is given and by the query timeout
+type Tx struct {
+	*sql.Tx
+	queryTimeout time.Duration
+}
+
+// Row is the row of Tx.QueryRowContext, the statement is cancelled
+// after the row is scanned
+type Row struct {
+	*sql.Row
+	cancel context.CancelFunc
+}
+
+// Scan copies the columns of the row into dest
+func (r *Row) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	return r.Row.Scan(dest...)
+}
+
+// Rows are the rows of Tx.QueryContext, the statement is cancelled
+// after the rows are closed
+type Rows struct {
+	*sql.Rows
+	cancel context.CancelFunc
+}
+
+// Close closes the rows
+func (r *Rows) Close() error {
+	defer r.cancel()
+	return r.Rows.Close()
+}
+
+// ExecContext runs the statement in the transaction
+func (t *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	defer cancel()
+	return t.Tx.ExecContext(queryCtx, query, args...)
+}
+
+// QueryRowContext runs the query in the transaction
+func (t *Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *Row {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	return &Row{Row: t.Tx.QueryRowContext(queryCtx, query, args...), cancel: cancel}
+}
+
+// QueryContext runs the query in the transaction, the rows must be closed
+func (t *Tx) QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	queryCtx, cancel := withTimeout(ctx, t.queryTimeout)
+	rows, err := t.Tx.QueryContext(queryCtx, query, args...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &Rows{Rows: rows, cancel: cancel}, nil
+}
+
+// Tx runs fn in a transaction, which is committed if fn returns
+// nil and rolled back otherwise. The transaction is limited by ctx,
+// the statements of fn are also limited by the query timeout.
+func (c *ConnectionManager) Tx(ctx context.Context, fn func(tx *Tx) error) error {
+	logger := logging.NewLoggers("db", "tx")
+	sqlTx, err := c.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		logger.ErrorLog().Str("when", "begin transaction").Err(err).Msg("failed to begin transaction")
+		return err
+	}
+	tx := &Tx{Tx: sqlTx, queryTimeout: c.queryTimeout}
+	if err := fn(tx); err != nil {
+		// the transaction is rolled back by the driver when ctx is done
+		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
+			logger.ErrorLog().Str("when", "rollback").Err(rbErr).Msg("failed to rollback transaction")
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		logger.ErrorLog().Str("when", "commit").Err(err).Msg("failed to commit transaction")
+		return err
+	}
+	return nil
+}
+
+// withTimeout limits ctx by the query timeout
+func (c *ConnectionManager) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, c.queryTimeout)
+}
+
+// withTimeout limits ctx by timeout, 0 - only by ctx
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,219 @@
+package limiter
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/metrics"
+	"context"
+	"fmt"
+	"golang.org/x/time/rate"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrIncorrectLimit = fmt.Errorf("incorrect host limit")
+
+// idleHost is how long the limits of a host without requests are kept,
+// the hosts of deleted sites are forgotten after it
+const idleHost = 10 * time.Minute
+
+type LimiterConfig interface {
+	GetHostConcurrency() int
+	GetHostRate() float64
+	GetHostLimits() string
+}
+
+// Limit of requests to a single host
+type Limit struct {
+	Concurrency int
+	Rate        float64
+}
+
+// HostLimiter limits concurrent requests
+// and requests per second for every host.
+type HostLimiter struct {
+	mu        sync.Mutex
+	hosts     map[string]*host
+	def       Limit
+	overrides map[string]Limit
+	// idle is how long the idle hosts are kept, swept is when they were evicted
+	idle  time.Duration
+	swept time.Time
+}
+
+type host struct {
+	slots chan struct{}
+	rate  *rate.Limiter
+	// keep is how long the host is kept after the last request,
+	// so its rate limiter refills before it is evicted
+	keep time.Duration
+	// active counts the requests waiting or holding the host,
+	// used is when the last one was done, guarded by HostLimiter.mu
+	active int
+	used   time.Time
+}
+
+func NewHostLimiter(cfg LimiterConfig) (*HostLimiter, error) {
+	logger := logging.NewLoggers("limiter", "newHostLimiter")
+	overrides, err := ParseLimits(cfg.GetHostLimits())
+	if err != nil {
+		logger.ErrorLog().Str("when", "parse host limits").Err(err).Msg("unable to parse host limits")
+		return nil, err
+	}
+	// the default limits are disabled by 0
+	if cfg.GetHostConcurrency() < 0 || !(cfg.GetHostRate() >= 0) || math.IsInf(cfg.GetHostRate(), 1) {
+		err := fmt.Errorf("%w: concurrency %d and rate %v must not be negative", ErrIncorrectLimit,
+			cfg.GetHostConcurrency(), cfg.GetHostRate())
+		logger.ErrorLog().Str("when", "check default limits").Err(err).Msg("incorrect default limits")
+		return nil, err
+	}
+
+	return &HostLimiter{
+		hosts:     make(map[string]*host),
+		def:       Limit{Concurrency: cfg.GetHostConcurrency(), Rate: cfg.GetHostRate()},
+		overrides: overrides,
+		idle:      idleHost,
+	}, nil
+}
+
+// ParseLimits parses per-host limits in the form
+// "example.com=2/0.5;api.example.com=10/20",
+// where 2 is concurrency and 0.5 is requests per second,
+// both of them must be above 0.
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	for _, item := range strings.Split(s, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("%w: %q", ErrIncorrectLimit, item)
+		}
+		values := strings.SplitN(parts[1], "/", 2)
+		if len(values) != 2 {
+			return nil, fmt.Errorf("%w: %q", ErrIncorrectLimit, item)
+		}
+		hostname := strings.ToLower(strings.TrimSpace(parts[0]))
+		if hostname == "" {
+			return nil, fmt.Errorf("%w: %q has no host", ErrIncorrectLimit, item)
+		}
+		concurrency, err := strconv.Atoi(values[0])
+		if err != nil || concurrency <= 0 {
+			return nil, fmt.Errorf("%w: %q, concurrency must be above 0", ErrIncorrectLimit, item)
+		}
+		perSecond, err := strconv.ParseFloat(values[1], 64)
+		if err != nil || !(perSecond > 0) || math.IsInf(perSecond, 1) {
+			return nil, fmt.Errorf("%w: %q, rate must be above 0", ErrIncorrectLimit, item)
+		}
+		limits[hostname] = Limit{Concurrency: concurrency, Rate: perSecond}
+	}
+	return limits, nil
+}
+
+// Wait blocks until a request to the host is allowed.
+// The returned function must be called when the request is done.
+func (l *HostLimiter) Wait(ctx context.Context, hostname string) (func(), error) {
+	hostname = strings.ToLower(hostname)
+	h := l.host(hostname)
+	start := time.Now()
+	delayed := false
+
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		default:
+			delayed = true
+			select {
+			case h.slots <- struct{}{}:
+			case <-ctx.Done():
+				l.done(h)
+				return nil, ctx.Err()
+			}
+		}
+	}
+	release := func() {
+		if h.slots != nil {
+			<-h.slots
+		}
+		l.done(h)
+	}
+
+	if h.rate != nil {
+		r := h.rate.Reserve()
+		if wait := r.Delay(); wait > 0 {
+			delayed = true
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				r.Cancel()
+				release()
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	if delayed {
+		metrics.ChecksDelayed.Add(1)
+		metrics.ChecksDelayedByHost.Add(hostname, 1)
+		metrics.CheckDelayMs.Add(time.Since(start).Milliseconds())
+	}
+	return release, nil
+}
+
+// host returns the limits of the host, l.done must be called when the request is done
+func (l *HostLimiter) host(hostname string) *host {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.evict(time.Now())
+	if h, ok := l.hosts[hostname]; ok {
+		h.active++
+		return h
+	}
+
+	limit, ok := l.overrides[hostname]
+	if !ok {
+		limit = l.def
+	}
+	h := &host{}
+	if limit.Concurrency > 0 {
+		h.slots = make(chan struct{}, limit.Concurrency)
+	}
+	h.keep = l.idle
+	if limit.Rate > 0 {
+		h.rate = rate.NewLimiter(rate.Limit(limit.Rate), 1)
+		if refill := time.Duration(float64(time.Second) / limit.Rate); refill > h.keep {
+			h.keep = refill
+		}
+	}
+	h.active = 1
+	l.hosts[hostname] = h
+	return h
+}
+
+// done marks the end of the request to the host
+func (l *HostLimiter) done(h *host) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	h.active--
+	h.used = time.Now()
+}
+
+// evict forgets the hosts without requests for their keep time, it runs
+// at most once per idle time and must be called with l.mu held
+func (l *HostLimiter) evict(now time.Time) {
+	if now.Sub(l.swept) < l.idle {
+		return
+	}
+	l.swept = now
+	for hostname, h := range l.hosts {
+		if h.active == 0 && now.Sub(h.used) >= h.keep {
+			delete(l.hosts, hostname)
+		}
+	}
+}
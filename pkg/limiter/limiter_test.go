@@ -0,0 +1,254 @@
+package limiter
+
+import (
+	"CheckUrls/pkg/metrics"
+	"context"
+	"errors"
+	"expvar"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	concurrency int
+	rate        float64
+	limits      string
+}
+
+func (c testConfig) GetHostConcurrency() int { return c.concurrency }
+func (c testConfig) GetHostRate() float64    { return c.rate }
+func (c testConfig) GetHostLimits() string   { return c.limits }
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]Limit
+		err   bool
+	}{
+		{"empty", "", map[string]Limit{}, false},
+		{"hosts", "Example.com=2/0.5; api.example.com=10/20;", map[string]Limit{
+			"example.com":     {Concurrency: 2, Rate: 0.5},
+			"api.example.com": {Concurrency: 10, Rate: 20},
+		}, false},
+		{"without value", "example.com", nil, true},
+		{"without rate", "example.com=2", nil, true},
+		{"without host", "=2/1", nil, true},
+		{"text", "example.com=two/1", nil, true},
+		{"zero concurrency", "example.com=0/1", nil, true},
+		{"negative concurrency", "example.com=-1/1", nil, true},
+		{"zero rate", "example.com=1/0", nil, true},
+		{"negative rate", "example.com=1/-0.5", nil, true},
+		{"infinite rate", "example.com=1/Inf", nil, true},
+		{"not a number", "example.com=1/NaN", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLimits(tt.input)
+			if tt.err {
+				if !errors.Is(err, ErrIncorrectLimit) {
+					t.Errorf("ParseLimits(%q) = %v, %v, want %v", tt.input, got, err, ErrIncorrectLimit)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLimits(%q) = %v, %v, want %v", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHostLimiterIncorrect(t *testing.T) {
+	for _, cfg := range []testConfig{
+		{concurrency: -1},
+		{rate: -1},
+		{limits: "example.com=0/1"},
+	} {
+		if _, err := NewHostLimiter(cfg); !errors.Is(err, ErrIncorrectLimit) {
+			t.Errorf("NewHostLimiter(%+v) = %v, want %v", cfg, err, ErrIncorrectLimit)
+		}
+	}
+}
+
+func TestWaitConcurrency(t *testing.T) {
+	l, err := NewHostLimiter(testConfig{concurrency: 2, limits: "one.example.com=1/1000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		host string
+		want int64
+	}{
+		{"example.com", 2},
+		// the override is found by any case of the host
+		{"ONE.example.com", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			var running, most int64
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					release, err := l.Wait(context.Background(), tt.host)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					n := atomic.AddInt64(&running, 1)
+					for {
+						m := atomic.LoadInt64(&most)
+						if n <= m || atomic.CompareAndSwapInt64(&most, m, n) {
+							break
+						}
+					}
+					time.Sleep(10 * time.Millisecond)
+					atomic.AddInt64(&running, -1)
+					release()
+				}()
+			}
+			wg.Wait()
+			if most != tt.want {
+				t.Errorf("%d checks of %s at once, want %d", most, tt.host, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitRate(t *testing.T) {
+	l, err := NewHostLimiter(testConfig{rate: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		release, err := l.Wait(context.Background(), "example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	// the first check goes at once, the next 5 every 50ms
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > time.Second {
+		t.Errorf("6 checks at 20 per second took %v, want about 250ms", elapsed)
+	}
+
+	// another host has its own rate
+	start = time.Now()
+	release, err := l.Wait(context.Background(), "other.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("first check of another host waited %v", elapsed)
+	}
+}
+
+func TestWaitCanceled(t *testing.T) {
+	l, err := NewHostLimiter(testConfig{concurrency: 1, rate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, err := l.Wait(context.Background(), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the slot is taken
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Wait(ctx, "example.com"); err != context.DeadlineExceeded {
+		t.Errorf("Wait of taken slot = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// the slot is free, but the next check is allowed in a second,
+	// the canceled wait gives the slot back
+	release()
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Wait(ctx, "example.com"); err != context.DeadlineExceeded {
+		t.Errorf("Wait of the rate = %v, want %v", err, context.DeadlineExceeded)
+	}
+	select {
+	case l.host("example.com").slots <- struct{}{}:
+	default:
+		t.Error("canceled wait keeps the slot")
+	}
+}
+
+func TestDelayedByHostMetric(t *testing.T) {
+	l, err := NewHostLimiter(testConfig{concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delayed := func(key string) int64 {
+		if v, ok := metrics.ChecksDelayedByHost.Get(key).(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	before := delayed("case.example.com")
+
+	release, err := l.Wait(context.Background(), "Case.Example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		release()
+	}()
+	second, err := l.Wait(context.Background(), "CASE.example.COM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second()
+
+	// the delayed check is counted by the host in lower case
+	if got := delayed("case.example.com") - before; got != 1 {
+		t.Errorf("delayed checks of case.example.com = %d, want 1", got)
+	}
+	if v := metrics.ChecksDelayedByHost.Get("CASE.example.COM"); v != nil {
+		t.Errorf("delayed checks are counted by the host as written: %v", v)
+	}
+}
+
+func TestIdleHostsEvicted(t *testing.T) {
+	l, err := NewHostLimiter(testConfig{concurrency: 1, rate: 1000, limits: "slow.example.com=1/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.idle = 20 * time.Millisecond
+	wait := func(hostname string) func() {
+		t.Helper()
+		release, err := l.Wait(context.Background(), hostname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return release
+	}
+	wait("idle.example.com")()
+	wait("slow.example.com")()
+	busy := wait("busy.example.com")
+	defer busy()
+
+	time.Sleep(30 * time.Millisecond)
+	wait("new.example.com")()
+
+	// the idle host is evicted, the busy one is in use and
+	// the slow one is kept until its rate limiter refills
+	l.mu.Lock()
+	hosts := make(map[string]bool)
+	for hostname := range l.hosts {
+		hosts[hostname] = true
+	}
+	l.mu.Unlock()
+	want := map[string]bool{"slow.example.com": true, "busy.example.com": true, "new.example.com": true}
+	if !reflect.DeepEqual(hosts, want) {
+		t.Errorf("hosts = %v, want %v", hosts, want)
+	}
+}
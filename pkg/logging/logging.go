@@ -53,3 +53,24 @@ func (l *Loggers) ErrorLog() *zerolog.Event {
 func (l *Loggers) FatalLog() *zerolog.Event {
 	return l.addMetadata(log.Fatal())
 }
+
+const redacted = "[REDACTED]"
+
+// Secret is a string which is never written to logs:
+// it is redacted by fmt, json and zerolog.
+type Secret string
+
+// String returns redacted value
+func (Secret) String() string {
+	return redacted
+}
+
+// GoString returns redacted value
+func (Secret) GoString() string {
+	return redacted
+}
+
+// MarshalText returns redacted value
+func (Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
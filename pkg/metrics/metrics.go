@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"CheckUrls/pkg/logging"
+	"context"
+	"expvar"
+	"net"
+	"net/http"
+	"time"
+)
+
+var (
+	// ChecksDelayed counts checks which waited for the host limiter
+	ChecksDelayed = expvar.NewInt("checks_delayed")
+	// ChecksDelayedByHost counts delayed checks per host
+	ChecksDelayedByHost = expvar.NewMap("checks_delayed_by_host")
+	// CheckDelayMs is the total time checks waited for the host limiter
+	CheckDelayMs = expvar.NewInt("check_delay_ms")
+	// StatesCompacted counts raw states replaced by hourly rollups
+	StatesCompacted = expvar.NewInt("states_compacted")
+	// StatesWritten counts states saved by the result writer
+	StatesWritten = expvar.NewInt("states_written")
+	// StatesDropped counts states which the result writer failed to save
+	StatesDropped = expvar.NewInt("states_dropped")
+	// StatesRejected counts states dropped as the database rejects them, e.g. of purged sites
+	StatesRejected = expvar.NewInt("states_rejected")
+	// WriteQueueDepth is the number of states waiting for the result writer
+	WriteQueueDepth = expvar.NewInt("write_queue_depth")
+	// StatesSpooled counts states written to the spool
+	StatesSpooled = expvar.NewInt("states_spooled")
+	// SpoolDepth is the number of states in the spool
+	SpoolDepth = expvar.NewInt("spool_depth")
+	// SpoolBytes is the size of the spool on disk
+	SpoolBytes = expvar.NewInt("spool_bytes")
+	// Watchers is the number of clients watching the checks
+	Watchers = expvar.NewInt("watchers")
+	// WatchersDropped counts watchers stopped for not keeping up with the checks
+	WatchersDropped = expvar.NewInt("watchers_dropped")
+	// SloBudgetRemaining is the percent of the error budget left by the names of objectives
+	SloBudgetRemaining = expvar.NewMap("slo_budget_remaining")
+	// SloAlerts counts the burn rate alerts which started firing
+	SloAlerts = expvar.NewInt("slo_alerts")
+)
+
+type MetricsConfig interface {
+	GetMetricsAddress() string
+}
+
+// Serve exposes metrics in json on /debug/vars
+// until ctx is done. Nothing is served if the address is empty.
+func Serve(cfg MetricsConfig, ctx context.Context) error {
+	logger := logging.NewLoggers("metrics", "serve")
+	if cfg.GetMetricsAddress() == "" {
+		logger.DebugLog().Msg("metrics address is not set")
+		return nil
+	}
+
+	listen, err := net.Listen("tcp", cfg.GetMetricsAddress())
+	if err != nil {
+		logger.ErrorLog().Str("when", "listening metrics").Err(err).Msg("error listening metrics")
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/debug/vars", expvar.Handler())
+	srv := &http.Server{Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.ErrorLog().Str("when", "shutdown metrics").Err(err).Msg("unable to shutdown metrics")
+		}
+	}()
+
+	if err := srv.Serve(listen); err != nil && err != http.ErrServerClosed {
+		logger.ErrorLog().Str("when", "serve metrics").Err(err).Msg("failed to serve metrics")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+package migrations
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/logging"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sqlCreateTable = "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT PRIMARY KEY, " +
+		"name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL);"
+	sqlApplied = "SELECT version, applied_at FROM schema_migrations ORDER BY version;"
+	sqlInsert  = "INSERT INTO schema_migrations (version, name, applied_at) VALUES ($1, $2, $3);"
+	sqlDelete  = "DELETE FROM schema_migrations WHERE version=$1;"
+	// sqlLock and sqlUnlock take and release the advisory lock of PostgreSQL,
+	// so the migrations of several servers run one after another
+	sqlLock   = "SELECT pg_advisory_lock($1);"
+	sqlUnlock = "SELECT pg_advisory_unlock($1);"
+)
+
+// lockKey is the key of the advisory lock of migrations
+const lockKey int64 = 0x636865636b75726c
+
+// Dialects of migrations, they are named as DB drivers
+const (
+	DialectPostgres = "postgres"
+	DialectSqlite   = "sqlite"
+)
+
+var (
+	ErrOutdatedSchema = fmt.Errorf("database schema is outdated, run \"migrate up\"")
+	ErrUnknownVersion = fmt.Errorf("database schema is newer than the binary")
+	ErrNoMigrations   = fmt.Errorf("no migrations to roll back")
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Migration is a versioned change of the schema,
+// files are named "<version>_<name>.up.sql" and "<version>_<name>.down.sql"
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status of a migration in the database
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	conn       *db.ConnectionManager
+	dialect    string
+	migrations []Migration
+}
+
+// querier runs queries on the pool or on one connection of it
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func NewMigrator(conn *db.ConnectionManager, dialect string) (*Migrator, error) {
+	migrations, err := Load(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{conn: conn, dialect: dialect, migrations: migrations}, nil
+}
+
+// Load returns embedded migrations of the dialect ordered by version
+func Load(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		var base string
+		up := strings.HasSuffix(name, ".up.sql")
+		switch {
+		case up:
+			base = strings.TrimSuffix(name, ".up.sql")
+		case strings.HasSuffix(name, ".down.sql"):
+			base = strings.TrimSuffix(name, ".down.sql")
+		default:
+			continue
+		}
+		parts := strings.SplitN(base, "_", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("incorrect migration file name %q", name)
+		}
+		version, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect migration version %q: %w", name, err)
+		}
+		content, err := fs.ReadFile(files, path.Join(dialect, name))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: parts[1]}
+			byVersion[version] = m
+		}
+		if up {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Status returns all known migrations and
+// whether they were applied to the database
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx, m.conn.Conn)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		at, ok := applied[migration.Version]
+		list = append(list, Status{Migration: migration, Applied: ok, AppliedAt: at})
+	}
+	return list, nil
+}
+
+// Check returns ErrOutdatedSchema if some
+// migrations were not applied yet.
+func (m *Migrator) Check(ctx context.Context) error {
+	applied, err := m.applied(ctx, m.conn.Conn)
+	if err != nil {
+		return err
+	}
+	for version := range applied {
+		if !m.known(version) {
+			return fmt.Errorf("%w: version %d", ErrUnknownVersion, version)
+		}
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			return fmt.Errorf("%w: version %d is not applied", ErrOutdatedSchema, migration.Version)
+		}
+	}
+	return nil
+}
+
+// Up applies all pending migrations, every
+// migration runs in its own transaction.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	logger := logging.NewLoggers("migrations", "up")
+	count := 0
+	err := m.session(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			migration := migration
+			err := tx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, sqlInsert, migration.Version, migration.Name, time.Now().UTC())
+				return err
+			})
+			if err != nil {
+				logger.ErrorLog().Err(err).Int64("version", migration.Version).Msg("unable to apply migration")
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			logger.InfoLog().Int64("version", migration.Version).Str("name", migration.Name).Msg("applied")
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down rolls back the last applied migration
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	logger := logging.NewLoggers("migrations", "down")
+	var done *Migration
+	err := m.session(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err := tx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, sqlDelete, migration.Version)
+				return err
+			})
+			if err != nil {
+				logger.ErrorLog().Err(err).Int64("version", migration.Version).Msg("unable to roll back migration")
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			logger.InfoLog().Int64("version", migration.Version).Str("name", migration.Name).Msg("rolled back")
+			done = &migration
+			return nil
+		}
+		return ErrNoMigrations
+	})
+	return done, err
+}
+
+// session runs fn on one connection of the pool. On PostgreSQL the
+// connection holds the advisory lock until fn returns, so concurrent
+// "migrate" of several servers wait for each other and every one reads
+// the migrations applied before it.
+func (m *Migrator) session(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	logger := logging.NewLoggers("migrations", "session")
+	conn, err := m.conn.Conn.Conn(ctx)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "get connection").Msg("unable to get connection")
+		return err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "close connection").Msg("unable to close connection")
+		}
+	}()
+
+	if m.dialect == DialectPostgres {
+		if _, err := conn.ExecContext(ctx, sqlLock, lockKey); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "take lock").Msg("unable to lock migrations")
+			return err
+		}
+		defer func() {
+			// the lock is released even if ctx is done, as
+			// the connection is returned to the pool with it
+			if _, err := conn.ExecContext(context.Background(), sqlUnlock, lockKey); err != nil {
+				logger.ErrorLog().Err(err).Str("when", "release lock").Msg("unable to unlock migrations")
+			}
+		}()
+	}
+	return fn(conn)
+}
+
+// tx runs fn in a transaction on the connection, which is
+// committed if fn returns nil and rolled back otherwise
+func tx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) known(version int64) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Migrator) applied(ctx context.Context, q querier) (map[int64]time.Time, error) {
+	logger := logging.NewLoggers("migrations", "applied")
+	if _, err := q.ExecContext(ctx, sqlCreateTable); err != nil {
+		logger.ErrorLog().Err(err).Str("when", "create schema_migrations").Msg("unable to create table")
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, sqlApplied)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to get migrations")
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	applied := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to scan results")
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,379 @@
+package migrations
+
+import (
+	"CheckUrls/pkg/config"
+	"CheckUrls/pkg/db"
+	"context"
+	"errors"
+	"github.com/kelseyhightower/envconfig"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func connectSqlite(t *testing.T) *db.ConnectionManager {
+	conn := db.NewConnectionManager()
+	cfg := &config.EnvCache{DbDriver: db.DriverSqlite, DbPath: filepath.Join(t.TempDir(), "checkUrls.db")}
+	if err := conn.Connect(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestLoad(t *testing.T) {
+	postgres, err := Load(DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlite, err := Load(DialectSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(postgres) == 0 || len(postgres) != len(sqlite) {
+		t.Fatalf("%d postgres and %d sqlite migrations, want the same", len(postgres), len(sqlite))
+	}
+	for i, m := range postgres {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %d has version %d, want versions from 1 without gaps", i, m.Version)
+		}
+		if s := sqlite[i]; s.Version != m.Version || s.Name != m.Name {
+			t.Errorf("sqlite migration %d_%s, want %d_%s", s.Version, s.Name, m.Version, m.Name)
+		}
+		for _, dialect := range []Migration{m, sqlite[i]} {
+			if dialect.Up == "" || dialect.Down == "" {
+				t.Errorf("migration %d_%s has no up or down", dialect.Version, dialect.Name)
+			}
+		}
+	}
+
+	if _, err := Load("mysql"); err == nil {
+		t.Error("Load of unknown dialect succeeded")
+	}
+}
+
+func TestUpDownUp(t *testing.T) {
+	conn := connectSqlite(t)
+	m, err := NewMigrator(conn, DialectSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Check(context.Background()); !errors.Is(err, ErrOutdatedSchema) {
+		t.Errorf("Check of empty database = %v, want %v", err, ErrOutdatedSchema)
+	}
+
+	count, err := m.Up(context.Background())
+	if err != nil || count != len(m.migrations) {
+		t.Fatalf("Up = %d, %v, want %d", count, err, len(m.migrations))
+	}
+	if err := m.Check(context.Background()); err != nil {
+		t.Errorf("Check after Up = %v", err)
+	}
+	// the settings of checks of 0002_check_settings are saved
+	if _, err := conn.Conn.Exec("INSERT INTO sites (url, check_mode, proxy) VALUES ('https://example.com', 'cold', '');"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Conn.Exec("INSERT INTO status (date, status_code, site_id, latency, proxy, insecure) " +
+		"VALUES (CURRENT_TIMESTAMP, 200, 1, 150, '', false);"); err != nil {
+		t.Fatal(err)
+	}
+	var mode string
+	var latency int64
+	if err := conn.Conn.QueryRow("SELECT s.check_mode, st.latency FROM sites s JOIN status st ON st.site_id=s.id;").
+		Scan(&mode, &latency); err != nil || mode != "cold" || latency != 150 {
+		t.Errorf("check_mode, latency = %q, %d, %v, want cold, 150", mode, latency, err)
+	}
+	if count, err := m.Up(context.Background()); err != nil || count != 0 {
+		t.Errorf("second Up = %d, %v, want nothing applied", count, err)
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration, err := m.Down(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if migration.Version != m.migrations[i].Version {
+			t.Fatalf("Down rolled back %d, want %d", migration.Version, m.migrations[i].Version)
+		}
+		if err := m.Check(context.Background()); !errors.Is(err, ErrOutdatedSchema) {
+			t.Errorf("Check after Down of %d = %v, want %v", migration.Version, err, ErrOutdatedSchema)
+		}
+	}
+	if _, err := m.Down(context.Background()); err != ErrNoMigrations {
+		t.Errorf("Down of empty schema = %v, want %v", err, ErrNoMigrations)
+	}
+
+	// every down undoes its up, so the schema is created again
+	if count, err := m.Up(context.Background()); err != nil || count != len(m.migrations) {
+		t.Fatalf("Up after Down = %d, %v, want %d", count, err, len(m.migrations))
+	}
+	list, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range list {
+		if !s.Applied || s.AppliedAt.IsZero() {
+			t.Errorf("migration %d_%s is not applied", s.Version, s.Name)
+		}
+	}
+}
+
+// indexes returns the names of the indexes of the table in SQLite
+func indexes(t *testing.T, conn *db.ConnectionManager, table string) map[string]bool {
+	rows, err := conn.Conn.Query("SELECT name FROM sqlite_master WHERE type='index' AND tbl_name=$1;", table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names[name] = true
+	}
+	return names
+}
+
+func TestStatusIndex(t *testing.T) {
+	conn := connectSqlite(t)
+	m, err := NewMigrator(conn, DialectSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// 0009_status_site_date replaces the index of 0001_init
+	if names := indexes(t, conn, "status"); !names["status_site_id_date_id_idx"] || names["status_site_id_date_idx"] {
+		t.Errorf("indexes after Up = %v, want status_site_id_date_id_idx only", names)
+	}
+
+	downTo(t, m, 9)
+	if names := indexes(t, conn, "status"); names["status_site_id_date_id_idx"] || !names["status_site_id_date_idx"] {
+		t.Errorf("indexes after Down = %v, want status_site_id_date_idx only", names)
+	}
+
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if names := indexes(t, conn, "status"); !names["status_site_id_date_id_idx"] || names["status_site_id_date_idx"] {
+		t.Errorf("indexes after second Up = %v, want status_site_id_date_id_idx only", names)
+	}
+}
+
+// downTo rolls back the migrations down to the version, including it
+func downTo(t *testing.T, m *Migrator, version int64) {
+	t.Helper()
+	for {
+		migration, err := m.Down(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if migration.Version == version {
+			return
+		}
+	}
+}
+
+func TestUniqueUrlDuplicates(t *testing.T) {
+	conn := connectSqlite(t)
+	m, err := NewMigrator(conn, DialectSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	downTo(t, m, 5)
+	for _, site := range []struct {
+		url     string
+		deleted bool
+	}{{"http://a", false}, {"http://a", false}, {"http://a", true}, {"http://b", false}, {"http://b", false}} {
+		if _, err := conn.Conn.Exec("INSERT INTO sites (url, deleted) VALUES ($1, $2);",
+			site.url, site.deleted); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deleted := func() []bool {
+		rows, err := conn.Conn.Query("SELECT deleted FROM sites ORDER BY id;")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		var list []bool
+		for rows.Next() {
+			var d bool
+			if err := rows.Scan(&d); err != nil {
+				t.Fatal(err)
+			}
+			list = append(list, d)
+		}
+		return list
+	}
+
+	// 0005_sites_unique_url deletes the duplicates and records them
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := deleted(), []bool{false, true, true, false, true}; !reflect.DeepEqual(got, want) {
+		t.Errorf("deleted after Up = %v, want %v", got, want)
+	}
+	var retired []int64
+	rows, err := conn.Conn.Query("SELECT site_id FROM sites_retired_duplicates ORDER BY site_id;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		retired = append(retired, id)
+	}
+	if want := []int64{2, 5}; !reflect.DeepEqual(retired, want) {
+		t.Errorf("retired duplicates = %v, want %v", retired, want)
+	}
+
+	// down restores the duplicates, the site deleted before stays deleted
+	downTo(t, m, 5)
+	if got, want := deleted(), []bool{false, false, true, false, false}; !reflect.DeepEqual(got, want) {
+		t.Errorf("deleted after Down = %v, want %v", got, want)
+	}
+}
+
+func TestAuditAddress(t *testing.T) {
+	conn := connectSqlite(t)
+	m, err := NewMigrator(conn, DialectSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	downTo(t, m, 12)
+	for _, actor := range []string{"alice@10.0.0.5:51234", "unknown", "bob@example.com@[::1]:1"} {
+		if _, err := conn.Conn.Exec("INSERT INTO audit_events (date, actor, operation, site_id) "+
+			"VALUES (CURRENT_TIMESTAMP, $1, 'create', 1);", actor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func() [][2]string {
+		rows, err := conn.Conn.Query("SELECT actor, address FROM audit_events ORDER BY id;")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		var events [][2]string
+		for rows.Next() {
+			var e [2]string
+			if err := rows.Scan(&e[0], &e[1]); err != nil {
+				t.Fatal(err)
+			}
+			events = append(events, e)
+		}
+		return events
+	}
+
+	// 0012_audit_address splits the actor by its last @
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"alice", "10.0.0.5:51234"}, {"unknown", ""}, {"bob@example.com", "[::1]:1"}}
+	if got := read(); !reflect.DeepEqual(got, want) {
+		t.Errorf("events after Up = %v, want %v", got, want)
+	}
+	downTo(t, m, 12)
+	var actors []string
+	rows, err := conn.Conn.Query("SELECT actor FROM audit_events ORDER BY id;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var actor string
+		if err := rows.Scan(&actor); err != nil {
+			t.Fatal(err)
+		}
+		actors = append(actors, actor)
+	}
+	if want := []string{"alice@10.0.0.5:51234", "unknown", "bob@example.com@[::1]:1"}; !reflect.DeepEqual(actors, want) {
+		t.Errorf("actors after Down = %v, want %v", actors, want)
+	}
+}
+
+func TestCheckUnknownVersion(t *testing.T) {
+	conn := connectSqlite(t)
+	m, err := NewMigrator(conn, DialectSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Conn.Exec(sqlInsert, 9999, "newer", "2021-05-01 00:00:00"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Check(context.Background()); !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("Check = %v, want %v", err, ErrUnknownVersion)
+	}
+}
+
+// TestConcurrentUp runs against the database from HOST, PORT, USER,
+// PASSWORD, NAME and SSLMODE, only if TEST_POSTGRES is set.
+// All data of the database is removed.
+func TestConcurrentUp(t *testing.T) {
+	if os.Getenv("TEST_POSTGRES") == "" {
+		t.Skip("TEST_POSTGRES is not set")
+	}
+	var cfg config.EnvCache
+	if err := envconfig.Process("", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	conn := db.NewConnectionManager()
+	if err := conn.Connect(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	m, err := NewMigrator(conn, DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := m.Down(context.Background()); err == ErrNoMigrations {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the servers wait for the lock, so every migration is applied once
+	var wg sync.WaitGroup
+	counts := make([]int, 4)
+	for i := range counts {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			count, err := m.Up(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+			counts[i] = count
+		}(i)
+	}
+	wg.Wait()
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	if total != len(m.migrations) {
+		t.Errorf("%v migrations applied, want %d in total", counts, len(m.migrations))
+	}
+	if err := m.Check(context.Background()); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS sites (
+    id        BIGSERIAL PRIMARY KEY,
+    url       TEXT      NOT NULL,
+    frequency BIGINT    NOT NULL DEFAULT 0,
+    deleted   BOOLEAN   NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS status (
+    id          BIGSERIAL PRIMARY KEY,
+    date        TIMESTAMP NOT NULL,
+    status_code BIGINT    NOT NULL,
+    site_id     BIGINT    NOT NULL REFERENCES sites (id)
+);
+
+CREATE INDEX IF NOT EXISTS status_site_id_date_idx ON status (site_id, date);
@@ -0,0 +1,15 @@
+ALTER TABLE status
+    DROP COLUMN IF EXISTS latency,
+    DROP COLUMN IF EXISTS proxy,
+    DROP COLUMN IF EXISTS insecure;
+
+ALTER TABLE sites
+    DROP COLUMN IF EXISTS check_mode,
+    DROP COLUMN IF EXISTS proxy,
+    DROP COLUMN IF EXISTS client_cert,
+    DROP COLUMN IF EXISTS client_key,
+    DROP COLUMN IF EXISTS ca_bundle,
+    DROP COLUMN IF EXISTS skip_verify,
+    DROP COLUMN IF EXISTS auth_secret,
+    DROP COLUMN IF EXISTS basic_user,
+    DROP COLUMN IF EXISTS basic_secret;
@@ -0,0 +1,15 @@
+ALTER TABLE sites
+    ADD COLUMN IF NOT EXISTS check_mode   TEXT    NOT NULL DEFAULT 'warm',
+    ADD COLUMN IF NOT EXISTS proxy        TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS client_cert  TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS client_key   TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ca_bundle    TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS skip_verify  BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS auth_secret  TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS basic_user   TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS basic_secret TEXT    NOT NULL DEFAULT '';
+
+ALTER TABLE status
+    ADD COLUMN IF NOT EXISTS latency  BIGINT  NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS proxy    TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS insecure BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS status_rollups (
+    site_id     BIGINT    NOT NULL REFERENCES sites (id),
+    resolution  TEXT      NOT NULL,
+    bucket      TIMESTAMP NOT NULL,
+    checks      BIGINT    NOT NULL,
+    up          BIGINT    NOT NULL,
+    latency_p50 BIGINT    NOT NULL,
+    latency_p90 BIGINT    NOT NULL,
+    latency_p99 BIGINT    NOT NULL,
+    PRIMARY KEY (site_id, resolution, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS status_rollups_resolution_bucket_idx ON status_rollups (resolution, bucket);
+
+CREATE INDEX IF NOT EXISTS status_date_idx ON status (date);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS sites_url_active_idx;
+
+-- the table is missing if up was applied before the duplicates were recorded
+CREATE TABLE IF NOT EXISTS sites_retired_duplicates (
+    site_id BIGINT PRIMARY KEY
+);
+
+UPDATE sites SET deleted = false WHERE id IN (SELECT site_id FROM sites_retired_duplicates);
+
+DROP TABLE sites_retired_duplicates;
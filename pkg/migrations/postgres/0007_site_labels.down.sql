@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS site_labels_key_value_idx;
+
+DROP TABLE IF EXISTS site_labels;
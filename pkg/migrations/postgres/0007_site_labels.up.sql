@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS site_labels (
+    site_id BIGINT  NOT NULL REFERENCES sites (id),
+    key     TEXT    NOT NULL,
+    value   TEXT    NOT NULL DEFAULT '',
+    PRIMARY KEY (site_id, key)
+);
+
+CREATE INDEX IF NOT EXISTS site_labels_key_value_idx ON site_labels (key, value);
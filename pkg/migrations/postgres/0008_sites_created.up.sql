@@ -0,0 +1,6 @@
+-- the sites created before the migration get the time of the migration
+ALTER TABLE sites ADD COLUMN IF NOT EXISTS created_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc');
+
+CREATE INDEX IF NOT EXISTS sites_created_at_idx ON sites (created_at, id);
+
+CREATE INDEX IF NOT EXISTS sites_url_idx ON sites (url, id);
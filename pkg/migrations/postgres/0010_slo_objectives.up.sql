@@ -0,0 +1,13 @@
+-- site_id is 0 when the sites are selected by the labels, so it has no reference,
+-- threshold and time_window are durations in nanoseconds
+CREATE TABLE IF NOT EXISTS slo_objectives (
+    id          BIGSERIAL        PRIMARY KEY,
+    name        TEXT             NOT NULL UNIQUE,
+    site_id     BIGINT           NOT NULL DEFAULT 0,
+    selector    TEXT             NOT NULL DEFAULT '',
+    kind        TEXT             NOT NULL,
+    target      DOUBLE PRECISION NOT NULL,
+    threshold   BIGINT           NOT NULL DEFAULT 0,
+    time_window BIGINT           NOT NULL,
+    created_at  TIMESTAMP        NOT NULL
+);
@@ -0,0 +1,8 @@
+-- actor was saved as name@address, the address of the client is kept apart,
+-- so the events are selected by the name of the user
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '';
+
+UPDATE audit_events SET address=substring(actor from '@([^@]*)$'), actor=substring(actor from '^(.*)@[^@]*$')
+WHERE actor LIKE '%@%';
+
+CREATE INDEX IF NOT EXISTS audit_events_actor_date_idx ON audit_events (actor, date);
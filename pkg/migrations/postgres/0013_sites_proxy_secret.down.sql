@@ -0,0 +1,3 @@
+-- the moved passwords stay in the secret store,
+-- the proxies of their sites are kept without them
+ALTER TABLE sites DROP COLUMN IF EXISTS proxy_secret;
@@ -0,0 +1,3 @@
+-- proxy_secret is the name of the secret with the proxy password,
+-- the passwords saved in proxy are moved by "secrets move-proxies"
+ALTER TABLE sites ADD COLUMN IF NOT EXISTS proxy_secret TEXT NOT NULL DEFAULT '';
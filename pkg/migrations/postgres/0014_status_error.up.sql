@@ -0,0 +1,2 @@
+-- error is why the check failed before the request was sent
+ALTER TABLE status ADD COLUMN IF NOT EXISTS error TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS sites (
+    id        INTEGER PRIMARY KEY AUTOINCREMENT,
+    url       TEXT    NOT NULL,
+    frequency INTEGER NOT NULL DEFAULT 0,
+    deleted   BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS status (
+    id          INTEGER   PRIMARY KEY AUTOINCREMENT,
+    date        TIMESTAMP NOT NULL,
+    status_code INTEGER   NOT NULL,
+    site_id     INTEGER   NOT NULL REFERENCES sites (id)
+);
+
+CREATE INDEX IF NOT EXISTS status_site_id_date_idx ON status (site_id, date);
@@ -0,0 +1,13 @@
+ALTER TABLE status DROP COLUMN latency;
+ALTER TABLE status DROP COLUMN proxy;
+ALTER TABLE status DROP COLUMN insecure;
+
+ALTER TABLE sites DROP COLUMN check_mode;
+ALTER TABLE sites DROP COLUMN proxy;
+ALTER TABLE sites DROP COLUMN client_cert;
+ALTER TABLE sites DROP COLUMN client_key;
+ALTER TABLE sites DROP COLUMN ca_bundle;
+ALTER TABLE sites DROP COLUMN skip_verify;
+ALTER TABLE sites DROP COLUMN auth_secret;
+ALTER TABLE sites DROP COLUMN basic_user;
+ALTER TABLE sites DROP COLUMN basic_secret;
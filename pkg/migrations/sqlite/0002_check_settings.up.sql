@@ -0,0 +1,13 @@
+ALTER TABLE sites ADD COLUMN check_mode TEXT NOT NULL DEFAULT 'warm';
+ALTER TABLE sites ADD COLUMN proxy TEXT NOT NULL DEFAULT '';
+ALTER TABLE sites ADD COLUMN client_cert TEXT NOT NULL DEFAULT '';
+ALTER TABLE sites ADD COLUMN client_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE sites ADD COLUMN ca_bundle TEXT NOT NULL DEFAULT '';
+ALTER TABLE sites ADD COLUMN skip_verify BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE sites ADD COLUMN auth_secret TEXT NOT NULL DEFAULT '';
+ALTER TABLE sites ADD COLUMN basic_user TEXT NOT NULL DEFAULT '';
+ALTER TABLE sites ADD COLUMN basic_secret TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE status ADD COLUMN latency INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE status ADD COLUMN proxy TEXT NOT NULL DEFAULT '';
+ALTER TABLE status ADD COLUMN insecure BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS secrets (
+    name   TEXT PRIMARY KEY,
+    key_id TEXT NOT NULL,
+    nonce  BLOB NOT NULL,
+    value  BLOB NOT NULL
+);
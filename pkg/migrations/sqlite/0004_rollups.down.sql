@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS status_date_idx;
+
+DROP TABLE IF EXISTS status_rollups;
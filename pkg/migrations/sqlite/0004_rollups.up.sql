@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS status_rollups (
+    site_id     INTEGER   NOT NULL REFERENCES sites (id),
+    resolution  TEXT      NOT NULL,
+    bucket      TIMESTAMP NOT NULL,
+    checks      INTEGER   NOT NULL,
+    up          INTEGER   NOT NULL,
+    latency_p50 INTEGER   NOT NULL,
+    latency_p90 INTEGER   NOT NULL,
+    latency_p99 INTEGER   NOT NULL,
+    PRIMARY KEY (site_id, resolution, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS status_rollups_resolution_bucket_idx ON status_rollups (resolution, bucket);
+
+CREATE INDEX IF NOT EXISTS status_date_idx ON status (date);
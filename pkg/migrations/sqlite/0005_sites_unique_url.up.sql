@@ -0,0 +1,13 @@
+-- duplicates created by concurrent requests are deleted, the first site is kept,
+-- the ids of the deleted duplicates are recorded, so down restores exactly them
+CREATE TABLE IF NOT EXISTS sites_retired_duplicates (
+    site_id INTEGER PRIMARY KEY
+);
+
+INSERT INTO sites_retired_duplicates (site_id)
+SELECT id FROM sites
+WHERE NOT deleted AND id NOT IN (SELECT min(id) FROM sites WHERE NOT deleted GROUP BY url);
+
+UPDATE sites SET deleted = true WHERE id IN (SELECT site_id FROM sites_retired_duplicates);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sites_url_active_idx ON sites (url) WHERE NOT deleted;
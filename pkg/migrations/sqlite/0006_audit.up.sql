@@ -0,0 +1,14 @@
+-- site_id has no reference, the events are kept after the site is purged
+CREATE TABLE IF NOT EXISTS audit_events (
+    id          INTEGER   PRIMARY KEY AUTOINCREMENT,
+    date        TIMESTAMP NOT NULL,
+    actor       TEXT      NOT NULL,
+    operation   TEXT      NOT NULL,
+    site_id     INTEGER   NOT NULL,
+    site_before TEXT      NOT NULL DEFAULT '',
+    site_after  TEXT      NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_site_id_date_idx ON audit_events (site_id, date);
+
+CREATE INDEX IF NOT EXISTS audit_events_date_idx ON audit_events (date);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS sites_url_idx;
+
+DROP INDEX IF EXISTS sites_created_at_idx;
+
+ALTER TABLE sites DROP COLUMN created_at;
@@ -0,0 +1,10 @@
+-- the added column can't have a default of the current time,
+-- the sites created before the migration get the time of the migration
+-- in the format of the times written by the driver, so they are compared as text
+ALTER TABLE sites ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00+00:00';
+
+UPDATE sites SET created_at = datetime('now') || '+00:00';
+
+CREATE INDEX IF NOT EXISTS sites_created_at_idx ON sites (created_at, id);
+
+CREATE INDEX IF NOT EXISTS sites_url_idx ON sites (url, id);
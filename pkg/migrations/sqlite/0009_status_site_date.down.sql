@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS status_site_id_date_id_idx;
+CREATE INDEX IF NOT EXISTS status_site_id_date_idx ON status (site_id, date);
@@ -0,0 +1,4 @@
+-- the history of a site is read by its id and the range of dates,
+-- the index of 0001_init is replaced by the one ordered by id as well
+DROP INDEX IF EXISTS status_site_id_date_idx;
+CREATE INDEX IF NOT EXISTS status_site_id_date_id_idx ON status (site_id, date, id);
@@ -0,0 +1,3 @@
+-- version is increased by every change of the site,
+-- updates with a stale version are refused
+ALTER TABLE sites ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
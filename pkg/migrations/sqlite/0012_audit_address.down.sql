@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS audit_events_actor_date_idx;
+
+UPDATE audit_events SET actor=actor || '@' || address WHERE address <> '';
+
+ALTER TABLE audit_events DROP COLUMN address;
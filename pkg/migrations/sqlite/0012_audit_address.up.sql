@@ -0,0 +1,11 @@
+-- actor was saved as name@address, the address of the client is kept apart,
+-- so the events are selected by the name of the user
+ALTER TABLE audit_events ADD COLUMN address TEXT NOT NULL DEFAULT '';
+
+-- rtrim(actor, replace(actor, '@', '')) is actor up to its last @
+UPDATE audit_events SET
+    address=substr(actor, length(rtrim(actor, replace(actor, '@', ''))) + 1),
+    actor=substr(actor, 1, length(rtrim(actor, replace(actor, '@', ''))) - 1)
+WHERE instr(actor, '@') > 0;
+
+CREATE INDEX IF NOT EXISTS audit_events_actor_date_idx ON audit_events (actor, date);
@@ -0,0 +1 @@
+ALTER TABLE status DROP COLUMN error;
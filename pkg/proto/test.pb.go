@@ -9,6 +9,7 @@ package proto
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -26,9 +27,26 @@ type Site struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id        int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Url       string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	Frequency int64  `protobuf:"varint,3,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Id              int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url             string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Frequency       int64  `protobuf:"varint,3,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	CheckMode       string `protobuf:"bytes,4,opt,name=check_mode,json=checkMode,proto3" json:"check_mode,omitempty"`
+	Proxy           string `protobuf:"bytes,5,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	ClientCert      string `protobuf:"bytes,6,opt,name=client_cert,json=clientCert,proto3" json:"client_cert,omitempty"`
+	ClientKey       string `protobuf:"bytes,7,opt,name=client_key,json=clientKey,proto3" json:"client_key,omitempty"`
+	CaBundle        string `protobuf:"bytes,8,opt,name=ca_bundle,json=caBundle,proto3" json:"ca_bundle,omitempty"`
+	SkipVerify      bool   `protobuf:"varint,9,opt,name=skip_verify,json=skipVerify,proto3" json:"skip_verify,omitempty"`
+	AuthSecret      string `protobuf:"bytes,10,opt,name=auth_secret,json=authSecret,proto3" json:"auth_secret,omitempty"`
+	BasicAuthUser   string `protobuf:"bytes,11,opt,name=basic_auth_user,json=basicAuthUser,proto3" json:"basic_auth_user,omitempty"`
+	BasicAuthSecret string `protobuf:"bytes,12,opt,name=basic_auth_secret,json=basicAuthSecret,proto3" json:"basic_auth_secret,omitempty"`
+	// labels are key/value pairs, like team=payments
+	Labels map[string]string `protobuf:"bytes,13,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// created_at is set by the server
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// version is increased by the server on every change of the site
+	Version int64 `protobuf:"varint,15,opt,name=version,proto3" json:"version,omitempty"`
+	// proxy_secret is the name of the secret with the proxy password
+	ProxySecret string `protobuf:"bytes,16,opt,name=proxy_secret,json=proxySecret,proto3" json:"proxy_secret,omitempty"`
 }
 
 func (x *Site) Reset() {
@@ -84,15 +102,111 @@ func (x *Site) GetFrequency() int64 {
 	return 0
 }
 
+func (x *Site) GetCheckMode() string {
+	if x != nil {
+		return x.CheckMode
+	}
+	return ""
+}
+
+func (x *Site) GetProxy() string {
+	if x != nil {
+		return x.Proxy
+	}
+	return ""
+}
+
+func (x *Site) GetClientCert() string {
+	if x != nil {
+		return x.ClientCert
+	}
+	return ""
+}
+
+func (x *Site) GetClientKey() string {
+	if x != nil {
+		return x.ClientKey
+	}
+	return ""
+}
+
+func (x *Site) GetCaBundle() string {
+	if x != nil {
+		return x.CaBundle
+	}
+	return ""
+}
+
+func (x *Site) GetSkipVerify() bool {
+	if x != nil {
+		return x.SkipVerify
+	}
+	return false
+}
+
+func (x *Site) GetAuthSecret() string {
+	if x != nil {
+		return x.AuthSecret
+	}
+	return ""
+}
+
+func (x *Site) GetBasicAuthUser() string {
+	if x != nil {
+		return x.BasicAuthUser
+	}
+	return ""
+}
+
+func (x *Site) GetBasicAuthSecret() string {
+	if x != nil {
+		return x.BasicAuthSecret
+	}
+	return ""
+}
+
+func (x *Site) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Site) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Site) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Site) GetProxySecret() string {
+	if x != nil {
+		return x.ProxySecret
+	}
+	return ""
+}
+
 type State struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Date   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
-	Status int64                  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
-	SiteId int64                  `protobuf:"varint,4,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Date      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Status    int64                  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	SiteId    int64                  `protobuf:"varint,4,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	LatencyMs int64                  `protobuf:"varint,5,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Proxy     string                 `protobuf:"bytes,6,opt,name=proxy,proto3" json:"proxy,omitempty"`
+	Insecure  bool                   `protobuf:"varint,7,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	// error is why the check failed before the request was sent
+	Error string `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
 }
 
 func (x *State) Reset() {
@@ -155,18 +269,53 @@ func (x *State) GetSiteId() int64 {
 	return 0
 }
 
-type StatusResponse struct {
+func (x *State) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *State) GetProxy() string {
+	if x != nil {
+		return x.Proxy
+	}
+	return ""
+}
+
+func (x *State) GetInsecure() bool {
+	if x != nil {
+		return x.Insecure
+	}
+	return false
+}
+
+func (x *State) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Rollup aggregates the checks of an hour or a day
+// in the history which is older than raw states
+type Rollup struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Url       string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Frequency int64    `protobuf:"varint,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
-	States    []*State `protobuf:"bytes,3,rep,name=states,proto3" json:"states,omitempty"`
+	SiteId       int64                  `protobuf:"varint,1,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	Resolution   string                 `protobuf:"bytes,2,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Start        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	Checks       int64                  `protobuf:"varint,4,opt,name=checks,proto3" json:"checks,omitempty"`
+	UpRatio      float64                `protobuf:"fixed64,5,opt,name=up_ratio,json=upRatio,proto3" json:"up_ratio,omitempty"`
+	LatencyP50Ms int64                  `protobuf:"varint,6,opt,name=latency_p50_ms,json=latencyP50Ms,proto3" json:"latency_p50_ms,omitempty"`
+	LatencyP90Ms int64                  `protobuf:"varint,7,opt,name=latency_p90_ms,json=latencyP90Ms,proto3" json:"latency_p90_ms,omitempty"`
+	LatencyP99Ms int64                  `protobuf:"varint,8,opt,name=latency_p99_ms,json=latencyP99Ms,proto3" json:"latency_p99_ms,omitempty"`
 }
 
-func (x *StatusResponse) Reset() {
-	*x = StatusResponse{}
+func (x *Rollup) Reset() {
+	*x = Rollup{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -174,13 +323,13 @@ func (x *StatusResponse) Reset() {
 	}
 }
 
-func (x *StatusResponse) String() string {
+func (x *Rollup) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StatusResponse) ProtoMessage() {}
+func (*Rollup) ProtoMessage() {}
 
-func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+func (x *Rollup) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -192,43 +341,82 @@ func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
-func (*StatusResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Rollup.ProtoReflect.Descriptor instead.
+func (*Rollup) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *StatusResponse) GetUrl() string {
+func (x *Rollup) GetSiteId() int64 {
 	if x != nil {
-		return x.Url
+		return x.SiteId
+	}
+	return 0
+}
+
+func (x *Rollup) GetResolution() string {
+	if x != nil {
+		return x.Resolution
 	}
 	return ""
 }
 
-func (x *StatusResponse) GetFrequency() int64 {
+func (x *Rollup) GetStart() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Frequency
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Rollup) GetChecks() int64 {
+	if x != nil {
+		return x.Checks
 	}
 	return 0
 }
 
-func (x *StatusResponse) GetStates() []*State {
+func (x *Rollup) GetUpRatio() float64 {
 	if x != nil {
-		return x.States
+		return x.UpRatio
 	}
-	return nil
+	return 0
 }
 
-type ReadRequestState struct {
+func (x *Rollup) GetLatencyP50Ms() int64 {
+	if x != nil {
+		return x.LatencyP50Ms
+	}
+	return 0
+}
+
+func (x *Rollup) GetLatencyP90Ms() int64 {
+	if x != nil {
+		return x.LatencyP90Ms
+	}
+	return 0
+}
+
+func (x *Rollup) GetLatencyP99Ms() int64 {
+	if x != nil {
+		return x.LatencyP99Ms
+	}
+	return 0
+}
+
+type StatusResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Url   string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Count int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Url       string    `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Frequency int64     `protobuf:"varint,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	States    []*State  `protobuf:"bytes,3,rep,name=states,proto3" json:"states,omitempty"`
+	Rollups   []*Rollup `protobuf:"bytes,4,rep,name=rollups,proto3" json:"rollups,omitempty"`
+	// sites are the statuses of the sites selected by labels
+	Sites []*StatusResponse `protobuf:"bytes,5,rep,name=sites,proto3" json:"sites,omitempty"`
 }
 
-func (x *ReadRequestState) Reset() {
-	*x = ReadRequestState{}
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -236,13 +424,13 @@ func (x *ReadRequestState) Reset() {
 	}
 }
 
-func (x *ReadRequestState) String() string {
+func (x *StatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadRequestState) ProtoMessage() {}
+func (*StatusResponse) ProtoMessage() {}
 
-func (x *ReadRequestState) ProtoReflect() protoreflect.Message {
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -254,35 +442,60 @@ func (x *ReadRequestState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadRequestState.ProtoReflect.Descriptor instead.
-func (*ReadRequestState) Descriptor() ([]byte, []int) {
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ReadRequestState) GetUrl() string {
+func (x *StatusResponse) GetUrl() string {
 	if x != nil {
 		return x.Url
 	}
 	return ""
 }
 
-func (x *ReadRequestState) GetCount() int64 {
+func (x *StatusResponse) GetFrequency() int64 {
 	if x != nil {
-		return x.Count
+		return x.Frequency
 	}
 	return 0
 }
 
-type CreateRequestSite struct {
+func (x *StatusResponse) GetStates() []*State {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetRollups() []*Rollup {
+	if x != nil {
+		return x.Rollups
+	}
+	return nil
+}
+
+func (x *StatusResponse) GetSites() []*StatusResponse {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+// ReadRequestState reads the status of the site with the url
+// or of every site selected by the label selector
+type ReadRequestState struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sites *Site `protobuf:"bytes,1,opt,name=sites,proto3" json:"sites,omitempty"`
+	Url      string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Count    int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Selector string `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`
 }
 
-func (x *CreateRequestSite) Reset() {
-	*x = CreateRequestSite{}
+func (x *ReadRequestState) Reset() {
+	*x = ReadRequestState{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -290,13 +503,13 @@ func (x *CreateRequestSite) Reset() {
 	}
 }
 
-func (x *CreateRequestSite) String() string {
+func (x *ReadRequestState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateRequestSite) ProtoMessage() {}
+func (*ReadRequestState) ProtoMessage() {}
 
-func (x *CreateRequestSite) ProtoReflect() protoreflect.Message {
+func (x *ReadRequestState) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -308,28 +521,54 @@ func (x *CreateRequestSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateRequestSite.ProtoReflect.Descriptor instead.
-func (*CreateRequestSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReadRequestState.ProtoReflect.Descriptor instead.
+func (*ReadRequestState) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateRequestSite) GetSites() *Site {
+func (x *ReadRequestState) GetUrl() string {
 	if x != nil {
-		return x.Sites
+		return x.Url
 	}
-	return nil
+	return ""
 }
 
-type CreateResponseSite struct {
+func (x *ReadRequestState) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReadRequestState) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+// ReadRequestHistory reads the states of the site, deleted or not,
+// from the latest one
+type ReadRequestHistory struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-}
-
-func (x *CreateResponseSite) Reset() {
-	*x = CreateResponseSite{}
+	SiteId int64 `protobuf:"varint,1,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	// from is inclusive and to is exclusive, not set - without the bound
+	From *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// health is "up", "down" or empty for all states
+	Health string `protobuf:"bytes,4,opt,name=health,proto3" json:"health,omitempty"`
+	// page_size is 100 by default and 1000 at most
+	PageSize int64 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is next_page_token of the previous page,
+	// the other fields must be the same as in its request
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ReadRequestHistory) Reset() {
+	*x = ReadRequestHistory{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -337,13 +576,13 @@ func (x *CreateResponseSite) Reset() {
 	}
 }
 
-func (x *CreateResponseSite) String() string {
+func (x *ReadRequestHistory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateResponseSite) ProtoMessage() {}
+func (*ReadRequestHistory) ProtoMessage() {}
 
-func (x *CreateResponseSite) ProtoReflect() protoreflect.Message {
+func (x *ReadRequestHistory) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -355,28 +594,66 @@ func (x *CreateResponseSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateResponseSite.ProtoReflect.Descriptor instead.
-func (*CreateResponseSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReadRequestHistory.ProtoReflect.Descriptor instead.
+func (*ReadRequestHistory) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *CreateResponseSite) GetId() int64 {
+func (x *ReadRequestHistory) GetSiteId() int64 {
 	if x != nil {
-		return x.Id
+		return x.SiteId
 	}
 	return 0
 }
 
-type ReadRequestSite struct {
+func (x *ReadRequestHistory) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReadRequestHistory) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ReadRequestHistory) GetHealth() string {
+	if x != nil {
+		return x.Health
+	}
+	return ""
+}
+
+func (x *ReadRequestHistory) GetPageSize() int64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ReadRequestHistory) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ReadResponseHistory struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url    string   `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	States []*State `protobuf:"bytes,2,rep,name=states,proto3" json:"states,omitempty"`
+	// next_page_token is empty on the last page
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 }
 
-func (x *ReadRequestSite) Reset() {
-	*x = ReadRequestSite{}
+func (x *ReadResponseHistory) Reset() {
+	*x = ReadResponseHistory{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -384,13 +661,13 @@ func (x *ReadRequestSite) Reset() {
 	}
 }
 
-func (x *ReadRequestSite) String() string {
+func (x *ReadResponseHistory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadRequestSite) ProtoMessage() {}
+func (*ReadResponseHistory) ProtoMessage() {}
 
-func (x *ReadRequestSite) ProtoReflect() protoreflect.Message {
+func (x *ReadResponseHistory) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -402,28 +679,47 @@ func (x *ReadRequestSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadRequestSite.ProtoReflect.Descriptor instead.
-func (*ReadRequestSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReadResponseHistory.ProtoReflect.Descriptor instead.
+func (*ReadResponseHistory) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *ReadRequestSite) GetId() int64 {
+func (x *ReadResponseHistory) GetUrl() string {
 	if x != nil {
-		return x.Id
+		return x.Url
 	}
-	return 0
+	return ""
 }
 
-type ReadResponseSite struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+func (x *ReadResponseHistory) GetStates() []*State {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *ReadResponseHistory) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// ReportRequestUptime selects the sites by ids and by labels, every checked
+// site when both are empty, the window is [from, to), to is now by default
+type ReportRequestUptime struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sites *Site `protobuf:"bytes,1,opt,name=sites,proto3" json:"sites,omitempty"`
+	SiteIds  []int64                `protobuf:"varint,1,rep,packed,name=site_ids,json=siteIds,proto3" json:"site_ids,omitempty"`
+	Selector string                 `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	From     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
 }
 
-func (x *ReadResponseSite) Reset() {
-	*x = ReadResponseSite{}
+func (x *ReportRequestUptime) Reset() {
+	*x = ReportRequestUptime{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -431,13 +727,13 @@ func (x *ReadResponseSite) Reset() {
 	}
 }
 
-func (x *ReadResponseSite) String() string {
+func (x *ReportRequestUptime) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadResponseSite) ProtoMessage() {}
+func (*ReportRequestUptime) ProtoMessage() {}
 
-func (x *ReadResponseSite) ProtoReflect() protoreflect.Message {
+func (x *ReportRequestUptime) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -449,26 +745,64 @@ func (x *ReadResponseSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadResponseSite.ProtoReflect.Descriptor instead.
-func (*ReadResponseSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReportRequestUptime.ProtoReflect.Descriptor instead.
+func (*ReportRequestUptime) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ReadResponseSite) GetSites() *Site {
+func (x *ReportRequestUptime) GetSiteIds() []int64 {
 	if x != nil {
-		return x.Sites
+		return x.SiteIds
 	}
 	return nil
 }
 
-type ReadAllRequestSite struct {
+func (x *ReportRequestUptime) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *ReportRequestUptime) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReportRequestUptime) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+// SiteUptime is the availability of the site in the window, up_ms, down_ms
+// and gap_ms split the window, gaps are the time without checks
+type SiteUptime struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-}
 
-func (x *ReadAllRequestSite) Reset() {
-	*x = ReadAllRequestSite{}
+	SiteId int64  `protobuf:"varint,1,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	Url    string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Checks int64  `protobuf:"varint,3,opt,name=checks,proto3" json:"checks,omitempty"`
+	// uptime_percent is the share of up time in the time covered by checks
+	UptimePercent float64 `protobuf:"fixed64,4,opt,name=uptime_percent,json=uptimePercent,proto3" json:"uptime_percent,omitempty"`
+	UpMs          int64   `protobuf:"varint,5,opt,name=up_ms,json=upMs,proto3" json:"up_ms,omitempty"`
+	DownMs        int64   `protobuf:"varint,6,opt,name=down_ms,json=downMs,proto3" json:"down_ms,omitempty"`
+	GapMs         int64   `protobuf:"varint,7,opt,name=gap_ms,json=gapMs,proto3" json:"gap_ms,omitempty"`
+	Incidents     int64   `protobuf:"varint,8,opt,name=incidents,proto3" json:"incidents,omitempty"`
+	// mttr_ms is the mean time to recovery of the incidents ended in the window
+	MttrMs       int64 `protobuf:"varint,9,opt,name=mttr_ms,json=mttrMs,proto3" json:"mttr_ms,omitempty"`
+	LatencyP50Ms int64 `protobuf:"varint,10,opt,name=latency_p50_ms,json=latencyP50Ms,proto3" json:"latency_p50_ms,omitempty"`
+	LatencyP90Ms int64 `protobuf:"varint,11,opt,name=latency_p90_ms,json=latencyP90Ms,proto3" json:"latency_p90_ms,omitempty"`
+	LatencyP99Ms int64 `protobuf:"varint,12,opt,name=latency_p99_ms,json=latencyP99Ms,proto3" json:"latency_p99_ms,omitempty"`
+}
+
+func (x *SiteUptime) Reset() {
+	*x = SiteUptime{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -476,13 +810,13 @@ func (x *ReadAllRequestSite) Reset() {
 	}
 }
 
-func (x *ReadAllRequestSite) String() string {
+func (x *SiteUptime) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadAllRequestSite) ProtoMessage() {}
+func (*SiteUptime) ProtoMessage() {}
 
-func (x *ReadAllRequestSite) ProtoReflect() protoreflect.Message {
+func (x *SiteUptime) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -494,21 +828,107 @@ func (x *ReadAllRequestSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadAllRequestSite.ProtoReflect.Descriptor instead.
-func (*ReadAllRequestSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use SiteUptime.ProtoReflect.Descriptor instead.
+func (*SiteUptime) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{8}
 }
 
-type ReadAllResponseSite struct {
+func (x *SiteUptime) GetSiteId() int64 {
+	if x != nil {
+		return x.SiteId
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *SiteUptime) GetChecks() int64 {
+	if x != nil {
+		return x.Checks
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetUptimePercent() float64 {
+	if x != nil {
+		return x.UptimePercent
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetUpMs() int64 {
+	if x != nil {
+		return x.UpMs
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetDownMs() int64 {
+	if x != nil {
+		return x.DownMs
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetGapMs() int64 {
+	if x != nil {
+		return x.GapMs
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetIncidents() int64 {
+	if x != nil {
+		return x.Incidents
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetMttrMs() int64 {
+	if x != nil {
+		return x.MttrMs
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetLatencyP50Ms() int64 {
+	if x != nil {
+		return x.LatencyP50Ms
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetLatencyP90Ms() int64 {
+	if x != nil {
+		return x.LatencyP90Ms
+	}
+	return 0
+}
+
+func (x *SiteUptime) GetLatencyP99Ms() int64 {
+	if x != nil {
+		return x.LatencyP99Ms
+	}
+	return 0
+}
+
+type ReportResponseUptime struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Sites []*Site `protobuf:"bytes,1,rep,name=sites,proto3" json:"sites,omitempty"`
+	From  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Sites []*SiteUptime          `protobuf:"bytes,3,rep,name=sites,proto3" json:"sites,omitempty"`
 }
 
-func (x *ReadAllResponseSite) Reset() {
-	*x = ReadAllResponseSite{}
+func (x *ReportResponseUptime) Reset() {
+	*x = ReportResponseUptime{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -516,13 +936,13 @@ func (x *ReadAllResponseSite) Reset() {
 	}
 }
 
-func (x *ReadAllResponseSite) String() string {
+func (x *ReportResponseUptime) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadAllResponseSite) ProtoMessage() {}
+func (*ReportResponseUptime) ProtoMessage() {}
 
-func (x *ReadAllResponseSite) ProtoReflect() protoreflect.Message {
+func (x *ReportResponseUptime) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -534,28 +954,44 @@ func (x *ReadAllResponseSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadAllResponseSite.ProtoReflect.Descriptor instead.
-func (*ReadAllResponseSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReportResponseUptime.ProtoReflect.Descriptor instead.
+func (*ReportResponseUptime) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *ReadAllResponseSite) GetSites() []*Site {
+func (x *ReportResponseUptime) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReportResponseUptime) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ReportResponseUptime) GetSites() []*SiteUptime {
 	if x != nil {
 		return x.Sites
 	}
 	return nil
 }
 
-type UpdateRequestSite struct {
+type CreateRequestSite struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	Sites *Site `protobuf:"bytes,1,opt,name=sites,proto3" json:"sites,omitempty"`
+	// restore restores the last deleted site with the url instead of creating a new one
+	Restore bool `protobuf:"varint,2,opt,name=restore,proto3" json:"restore,omitempty"`
 }
 
-func (x *UpdateRequestSite) Reset() {
-	*x = UpdateRequestSite{}
+func (x *CreateRequestSite) Reset() {
+	*x = CreateRequestSite{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -563,13 +999,13 @@ func (x *UpdateRequestSite) Reset() {
 	}
 }
 
-func (x *UpdateRequestSite) String() string {
+func (x *CreateRequestSite) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateRequestSite) ProtoMessage() {}
+func (*CreateRequestSite) ProtoMessage() {}
 
-func (x *UpdateRequestSite) ProtoReflect() protoreflect.Message {
+func (x *CreateRequestSite) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -581,28 +1017,35 @@ func (x *UpdateRequestSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateRequestSite.ProtoReflect.Descriptor instead.
-func (*UpdateRequestSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateRequestSite.ProtoReflect.Descriptor instead.
+func (*CreateRequestSite) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *UpdateRequestSite) GetSites() *Site {
+func (x *CreateRequestSite) GetSites() *Site {
 	if x != nil {
 		return x.Sites
 	}
 	return nil
 }
 
-type UpdateResponseSite struct {
+func (x *CreateRequestSite) GetRestore() bool {
+	if x != nil {
+		return x.Restore
+	}
+	return false
+}
+
+type CreateResponseSite struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Updated int64 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *UpdateResponseSite) Reset() {
-	*x = UpdateResponseSite{}
+func (x *CreateResponseSite) Reset() {
+	*x = CreateResponseSite{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -610,13 +1053,13 @@ func (x *UpdateResponseSite) Reset() {
 	}
 }
 
-func (x *UpdateResponseSite) String() string {
+func (x *CreateResponseSite) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateResponseSite) ProtoMessage() {}
+func (*CreateResponseSite) ProtoMessage() {}
 
-func (x *UpdateResponseSite) ProtoReflect() protoreflect.Message {
+func (x *CreateResponseSite) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -628,19 +1071,19 @@ func (x *UpdateResponseSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateResponseSite.ProtoReflect.Descriptor instead.
-func (*UpdateResponseSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateResponseSite.ProtoReflect.Descriptor instead.
+func (*CreateResponseSite) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *UpdateResponseSite) GetUpdated() int64 {
+func (x *CreateResponseSite) GetId() int64 {
 	if x != nil {
-		return x.Updated
+		return x.Id
 	}
 	return 0
 }
 
-type DeleteRequestSite struct {
+type ReadRequestSite struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -648,8 +1091,8 @@ type DeleteRequestSite struct {
 	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func (x *DeleteRequestSite) Reset() {
-	*x = DeleteRequestSite{}
+func (x *ReadRequestSite) Reset() {
+	*x = ReadRequestSite{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -657,13 +1100,13 @@ func (x *DeleteRequestSite) Reset() {
 	}
 }
 
-func (x *DeleteRequestSite) String() string {
+func (x *ReadRequestSite) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRequestSite) ProtoMessage() {}
+func (*ReadRequestSite) ProtoMessage() {}
 
-func (x *DeleteRequestSite) ProtoReflect() protoreflect.Message {
+func (x *ReadRequestSite) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -675,28 +1118,28 @@ func (x *DeleteRequestSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRequestSite.ProtoReflect.Descriptor instead.
-func (*DeleteRequestSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReadRequestSite.ProtoReflect.Descriptor instead.
+func (*ReadRequestSite) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *DeleteRequestSite) GetId() int64 {
+func (x *ReadRequestSite) GetId() int64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-type DeleteResponseSite struct {
+type ReadResponseSite struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Deleted int64 `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	Sites *Site `protobuf:"bytes,1,opt,name=sites,proto3" json:"sites,omitempty"`
 }
 
-func (x *DeleteResponseSite) Reset() {
-	*x = DeleteResponseSite{}
+func (x *ReadResponseSite) Reset() {
+	*x = ReadResponseSite{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_pkg_proto_test_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -704,13 +1147,13 @@ func (x *DeleteResponseSite) Reset() {
 	}
 }
 
-func (x *DeleteResponseSite) String() string {
+func (x *ReadResponseSite) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponseSite) ProtoMessage() {}
+func (*ReadResponseSite) ProtoMessage() {}
 
-func (x *DeleteResponseSite) ProtoReflect() protoreflect.Message {
+func (x *ReadResponseSite) ProtoReflect() protoreflect.Message {
 	mi := &file_pkg_proto_test_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -722,165 +1165,2347 @@ func (x *DeleteResponseSite) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponseSite.ProtoReflect.Descriptor instead.
-func (*DeleteResponseSite) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReadResponseSite.ProtoReflect.Descriptor instead.
+func (*ReadResponseSite) Descriptor() ([]byte, []int) {
 	return file_pkg_proto_test_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *DeleteResponseSite) GetDeleted() int64 {
+func (x *ReadResponseSite) GetSites() *Site {
 	if x != nil {
-		return x.Deleted
+		return x.Sites
 	}
-	return 0
+	return nil
 }
 
-var File_pkg_proto_test_proto protoreflect.FileDescriptor
+type ReadAllRequestSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_pkg_proto_test_proto_rawDesc = []byte{
-	0x0a, 0x14, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x65, 0x73, 0x74,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x46,
-	0x0a, 0x04, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71,
-	0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x72, 0x65,
-	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x22, 0x78, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x2e, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
-	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
-	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12,
-	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x74, 0x65, 0x5f,
-	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x69, 0x74, 0x65, 0x49, 0x64,
-	0x22, 0x66, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x75, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
-	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e,
-	0x63, 0x79, 0x12, 0x24, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65,
-	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x22, 0x3a, 0x0a, 0x10, 0x52, 0x65, 0x61, 0x64,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03,
-	0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x14,
-	0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63,
-	0x6f, 0x75, 0x6e, 0x74, 0x22, 0x36, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74,
-	0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x53, 0x69, 0x74, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x22, 0x24, 0x0a, 0x12,
-	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69,
-	0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
-	0x69, 0x64, 0x22, 0x21, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x35, 0x0a, 0x10, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74,
-	0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x53, 0x69, 0x74, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x22, 0x14, 0x0a, 0x12,
-	0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69,
-	0x74, 0x65, 0x22, 0x38, 0x0a, 0x13, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74,
-	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x53, 0x69, 0x74, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x22, 0x36, 0x0a, 0x11,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74,
-	0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x52, 0x05, 0x73,
-	0x69, 0x74, 0x65, 0x73, 0x22, 0x2e, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x75, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x75, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x64, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2e, 0x0a, 0x12, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12,
-	0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x32, 0x84, 0x03, 0x0a, 0x0c, 0x53, 0x69,
-	0x74, 0x65, 0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65,
-	0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x19,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x04, 0x52, 0x65, 0x61,
-	0x64, 0x12, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69,
-	0x74, 0x65, 0x12, 0x40, 0x0a, 0x07, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x12, 0x19, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x53, 0x69, 0x74, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x18,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53,
-	0x69, 0x74, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x18, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69,
-	0x74, 0x65, 0x12, 0x3c, 0x0a, 0x0a, 0x52, 0x65, 0x61, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x42, 0x15, 0x5a, 0x13, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x73, 0x2f, 0x70, 0x6b,
-	0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	// selector selects the sites by labels, like "team=payments,env in (prod, staging)"
+	Selector string `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// page_size is 100 by default and 1000 at most
+	PageSize int64 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token is next_page_token of the previous page,
+	// the other fields must be the same as in its request
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// order_by is "id" (default), "url" or "created", "desc" is added for the reverse order
+	OrderBy string `protobuf:"bytes,4,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// url_contains selects the sites with the substring in the url
+	UrlContains string `protobuf:"bytes,5,opt,name=url_contains,json=urlContains,proto3" json:"url_contains,omitempty"`
+	// state is "active" (default), "deleted" or "all"
+	State string `protobuf:"bytes,6,opt,name=state,proto3" json:"state,omitempty"`
+	// min_frequency and max_frequency are inclusive, 0 - without the bound
+	MinFrequency int64 `protobuf:"varint,7,opt,name=min_frequency,json=minFrequency,proto3" json:"min_frequency,omitempty"`
+	MaxFrequency int64 `protobuf:"varint,8,opt,name=max_frequency,json=maxFrequency,proto3" json:"max_frequency,omitempty"`
 }
 
-var (
-	file_pkg_proto_test_proto_rawDescOnce sync.Once
-	file_pkg_proto_test_proto_rawDescData = file_pkg_proto_test_proto_rawDesc
-)
-
-func file_pkg_proto_test_proto_rawDescGZIP() []byte {
-	file_pkg_proto_test_proto_rawDescOnce.Do(func() {
-		file_pkg_proto_test_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_test_proto_rawDescData)
-	})
-	return file_pkg_proto_test_proto_rawDescData
+func (x *ReadAllRequestSite) Reset() {
+	*x = ReadAllRequestSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-var file_pkg_proto_test_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
-var file_pkg_proto_test_proto_goTypes = []interface{}{
-	(*Site)(nil),                  // 0: proto.Site
-	(*State)(nil),                 // 1: proto.State
-	(*StatusResponse)(nil),        // 2: proto.StatusResponse
-	(*ReadRequestState)(nil),      // 3: proto.ReadRequestState
-	(*CreateRequestSite)(nil),     // 4: proto.CreateRequestSite
-	(*CreateResponseSite)(nil),    // 5: proto.CreateResponseSite
-	(*ReadRequestSite)(nil),       // 6: proto.ReadRequestSite
-	(*ReadResponseSite)(nil),      // 7: proto.ReadResponseSite
-	(*ReadAllRequestSite)(nil),    // 8: proto.ReadAllRequestSite
-	(*ReadAllResponseSite)(nil),   // 9: proto.ReadAllResponseSite
-	(*UpdateRequestSite)(nil),     // 10: proto.UpdateRequestSite
-	(*UpdateResponseSite)(nil),    // 11: proto.UpdateResponseSite
-	(*DeleteRequestSite)(nil),     // 12: proto.DeleteRequestSite
-	(*DeleteResponseSite)(nil),    // 13: proto.DeleteResponseSite
-	(*timestamppb.Timestamp)(nil), // 14: google.protobuf.Timestamp
-}
-var file_pkg_proto_test_proto_depIdxs = []int32{
-	14, // 0: proto.State.date:type_name -> google.protobuf.Timestamp
-	1,  // 1: proto.StatusResponse.states:type_name -> proto.State
-	0,  // 2: proto.CreateRequestSite.sites:type_name -> proto.Site
-	0,  // 3: proto.ReadResponseSite.sites:type_name -> proto.Site
-	0,  // 4: proto.ReadAllResponseSite.sites:type_name -> proto.Site
-	0,  // 5: proto.UpdateRequestSite.sites:type_name -> proto.Site
-	4,  // 6: proto.SitesService.Create:input_type -> proto.CreateRequestSite
-	6,  // 7: proto.SitesService.Read:input_type -> proto.ReadRequestSite
-	8,  // 8: proto.SitesService.ReadAll:input_type -> proto.ReadAllRequestSite
-	10, // 9: proto.SitesService.Update:input_type -> proto.UpdateRequestSite
-	12, // 10: proto.SitesService.Delete:input_type -> proto.DeleteRequestSite
-	3,  // 11: proto.SitesService.ReadStatus:input_type -> proto.ReadRequestState
-	5,  // 12: proto.SitesService.Create:output_type -> proto.CreateResponseSite
-	7,  // 13: proto.SitesService.Read:output_type -> proto.ReadResponseSite
-	9,  // 14: proto.SitesService.ReadAll:output_type -> proto.ReadAllResponseSite
-	11, // 15: proto.SitesService.Update:output_type -> proto.UpdateResponseSite
-	13, // 16: proto.SitesService.Delete:output_type -> proto.DeleteResponseSite
-	2,  // 17: proto.SitesService.ReadStatus:output_type -> proto.StatusResponse
-	12, // [12:18] is the sub-list for method output_type
-	6,  // [6:12] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+func (x *ReadAllRequestSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_pkg_proto_test_proto_init() }
-func file_pkg_proto_test_proto_init() {
-	if File_pkg_proto_test_proto != nil {
-		return
-	}
+func (*ReadAllRequestSite) ProtoMessage() {}
+
+func (x *ReadAllRequestSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadAllRequestSite.ProtoReflect.Descriptor instead.
+func (*ReadAllRequestSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReadAllRequestSite) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *ReadAllRequestSite) GetPageSize() int64 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ReadAllRequestSite) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ReadAllRequestSite) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *ReadAllRequestSite) GetUrlContains() string {
+	if x != nil {
+		return x.UrlContains
+	}
+	return ""
+}
+
+func (x *ReadAllRequestSite) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ReadAllRequestSite) GetMinFrequency() int64 {
+	if x != nil {
+		return x.MinFrequency
+	}
+	return 0
+}
+
+func (x *ReadAllRequestSite) GetMaxFrequency() int64 {
+	if x != nil {
+		return x.MaxFrequency
+	}
+	return 0
+}
+
+type ReadAllResponseSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sites []*Site `protobuf:"bytes,1,rep,name=sites,proto3" json:"sites,omitempty"`
+	// next_page_token is empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *ReadAllResponseSite) Reset() {
+	*x = ReadAllResponseSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReadAllResponseSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadAllResponseSite) ProtoMessage() {}
+
+func (x *ReadAllResponseSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadAllResponseSite.ProtoReflect.Descriptor instead.
+func (*ReadAllResponseSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReadAllResponseSite) GetSites() []*Site {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+func (x *ReadAllResponseSite) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UpdateRequestSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sites *Site `protobuf:"bytes,1,opt,name=sites,proto3" json:"sites,omitempty"`
+	// update_mask lists the changed fields of sites, like url or labels.team,
+	// the site is replaced when it is empty. The site is not changed
+	// when sites.version is set and the site has another version
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+}
+
+func (x *UpdateRequestSite) Reset() {
+	*x = UpdateRequestSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateRequestSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequestSite) ProtoMessage() {}
+
+func (x *UpdateRequestSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequestSite.ProtoReflect.Descriptor instead.
+func (*UpdateRequestSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateRequestSite) GetSites() *Site {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+func (x *UpdateRequestSite) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type UpdateResponseSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Updated int64 `protobuf:"varint,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	Sites   *Site `protobuf:"bytes,2,opt,name=sites,proto3" json:"sites,omitempty"`
+}
+
+func (x *UpdateResponseSite) Reset() {
+	*x = UpdateResponseSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateResponseSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResponseSite) ProtoMessage() {}
+
+func (x *UpdateResponseSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResponseSite.ProtoReflect.Descriptor instead.
+func (*UpdateResponseSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateResponseSite) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *UpdateResponseSite) GetSites() *Site {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+type DeleteRequestSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteRequestSite) Reset() {
+	*x = DeleteRequestSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequestSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequestSite) ProtoMessage() {}
+
+func (x *DeleteRequestSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequestSite.ProtoReflect.Descriptor instead.
+func (*DeleteRequestSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteRequestSite) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteResponseSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deleted int64 `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
+func (x *DeleteResponseSite) Reset() {
+	*x = DeleteResponseSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteResponseSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponseSite) ProtoMessage() {}
+
+func (x *DeleteResponseSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponseSite.ProtoReflect.Descriptor instead.
+func (*DeleteResponseSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteResponseSite) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+type ListDeletedRequestSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListDeletedRequestSite) Reset() {
+	*x = ListDeletedRequestSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeletedRequestSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedRequestSite) ProtoMessage() {}
+
+func (x *ListDeletedRequestSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedRequestSite.ProtoReflect.Descriptor instead.
+func (*ListDeletedRequestSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{20}
+}
+
+type ListDeletedResponseSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sites []*Site `protobuf:"bytes,1,rep,name=sites,proto3" json:"sites,omitempty"`
+}
+
+func (x *ListDeletedResponseSite) Reset() {
+	*x = ListDeletedResponseSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListDeletedResponseSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeletedResponseSite) ProtoMessage() {}
+
+func (x *ListDeletedResponseSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeletedResponseSite.ProtoReflect.Descriptor instead.
+func (*ListDeletedResponseSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ListDeletedResponseSite) GetSites() []*Site {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+type RestoreRequestSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *RestoreRequestSite) Reset() {
+	*x = RestoreRequestSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreRequestSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreRequestSite) ProtoMessage() {}
+
+func (x *RestoreRequestSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreRequestSite.ProtoReflect.Descriptor instead.
+func (*RestoreRequestSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RestoreRequestSite) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RestoreResponseSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Restored int64 `protobuf:"varint,1,opt,name=restored,proto3" json:"restored,omitempty"`
+}
+
+func (x *RestoreResponseSite) Reset() {
+	*x = RestoreResponseSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RestoreResponseSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreResponseSite) ProtoMessage() {}
+
+func (x *RestoreResponseSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreResponseSite.ProtoReflect.Descriptor instead.
+func (*RestoreResponseSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RestoreResponseSite) GetRestored() int64 {
+	if x != nil {
+		return x.Restored
+	}
+	return 0
+}
+
+// PurgeRequestSite removes the deleted site with its history for good
+type PurgeRequestSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *PurgeRequestSite) Reset() {
+	*x = PurgeRequestSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeRequestSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeRequestSite) ProtoMessage() {}
+
+func (x *PurgeRequestSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeRequestSite.ProtoReflect.Descriptor instead.
+func (*PurgeRequestSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PurgeRequestSite) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PurgeResponseSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Purged int64 `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+}
+
+func (x *PurgeResponseSite) Reset() {
+	*x = PurgeResponseSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PurgeResponseSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeResponseSite) ProtoMessage() {}
+
+func (x *PurgeResponseSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeResponseSite.ProtoReflect.Descriptor instead.
+func (*PurgeResponseSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PurgeResponseSite) GetPurged() int64 {
+	if x != nil {
+		return x.Purged
+	}
+	return 0
+}
+
+// AuditEvent is a change of the site, before and after
+// are the site in JSON, empty when the site is purged.
+// The actor is the name of the user, the address is of the client
+type AuditEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Date      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Actor     string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operation string                 `protobuf:"bytes,4,opt,name=operation,proto3" json:"operation,omitempty"`
+	SiteId    int64                  `protobuf:"varint,5,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	Before    string                 `protobuf:"bytes,6,opt,name=before,proto3" json:"before,omitempty"`
+	After     string                 `protobuf:"bytes,7,opt,name=after,proto3" json:"after,omitempty"`
+	Address   string                 `protobuf:"bytes,8,opt,name=address,proto3" json:"address,omitempty"`
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AuditEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *AuditEvent) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetSiteId() int64 {
+	if x != nil {
+		return x.SiteId
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+// ListRequestAudit selects the events, the empty fields match any event
+type ListRequestAudit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SiteId    int64                  `protobuf:"varint,1,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	Actor     string                 `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	Operation string                 `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	From      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	To        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	Limit     int64                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *ListRequestAudit) Reset() {
+	*x = ListRequestAudit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequestAudit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequestAudit) ProtoMessage() {}
+
+func (x *ListRequestAudit) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequestAudit.ProtoReflect.Descriptor instead.
+func (*ListRequestAudit) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListRequestAudit) GetSiteId() int64 {
+	if x != nil {
+		return x.SiteId
+	}
+	return 0
+}
+
+func (x *ListRequestAudit) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *ListRequestAudit) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *ListRequestAudit) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListRequestAudit) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListRequestAudit) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListResponseAudit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Events []*AuditEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (x *ListResponseAudit) Reset() {
+	*x = ListResponseAudit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListResponseAudit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponseAudit) ProtoMessage() {}
+
+func (x *ListResponseAudit) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponseAudit.ProtoReflect.Descriptor instead.
+func (*ListResponseAudit) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ListResponseAudit) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// WatchRequestState selects the sites by ids and by labels,
+// every site is watched when both are empty
+type WatchRequestState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SiteIds  []int64 `protobuf:"varint,1,rep,packed,name=site_ids,json=siteIds,proto3" json:"site_ids,omitempty"`
+	Selector string  `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	// transitions_only sends only the checks when the site went up or down
+	TransitionsOnly bool `protobuf:"varint,3,opt,name=transitions_only,json=transitionsOnly,proto3" json:"transitions_only,omitempty"`
+}
+
+func (x *WatchRequestState) Reset() {
+	*x = WatchRequestState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequestState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequestState) ProtoMessage() {}
+
+func (x *WatchRequestState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequestState.ProtoReflect.Descriptor instead.
+func (*WatchRequestState) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WatchRequestState) GetSiteIds() []int64 {
+	if x != nil {
+		return x.SiteIds
+	}
+	return nil
+}
+
+func (x *WatchRequestState) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *WatchRequestState) GetTransitionsOnly() bool {
+	if x != nil {
+		return x.TransitionsOnly
+	}
+	return false
+}
+
+type WatchResponseState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State *State `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	Url   string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// up is set when the site answered with 2xx or 3xx
+	Up bool `protobuf:"varint,3,opt,name=up,proto3" json:"up,omitempty"`
+	// transition is set when the site went up or down since the previous check
+	Transition bool `protobuf:"varint,4,opt,name=transition,proto3" json:"transition,omitempty"`
+}
+
+func (x *WatchResponseState) Reset() {
+	*x = WatchResponseState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchResponseState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponseState) ProtoMessage() {}
+
+func (x *WatchResponseState) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponseState.ProtoReflect.Descriptor instead.
+func (*WatchResponseState) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *WatchResponseState) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *WatchResponseState) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *WatchResponseState) GetUp() bool {
+	if x != nil {
+		return x.Up
+	}
+	return false
+}
+
+func (x *WatchResponseState) GetTransition() bool {
+	if x != nil {
+		return x.Transition
+	}
+	return false
+}
+
+// ImportRequestSite is one site of the import
+type ImportRequestSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sites *Site `protobuf:"bytes,1,opt,name=sites,proto3" json:"sites,omitempty"`
+}
+
+func (x *ImportRequestSite) Reset() {
+	*x = ImportRequestSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportRequestSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRequestSite) ProtoMessage() {}
+
+func (x *ImportRequestSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRequestSite.ProtoReflect.Descriptor instead.
+func (*ImportRequestSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ImportRequestSite) GetSites() *Site {
+	if x != nil {
+		return x.Sites
+	}
+	return nil
+}
+
+// ImportResult is the result of the site with the index of the import,
+// result is "created", "updated" or "failed" with the reason
+type ImportResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index  int64  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Url    string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Id     int64  `protobuf:"varint,3,opt,name=id,proto3" json:"id,omitempty"`
+	Result string `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`
+	Reason string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *ImportResult) Reset() {
+	*x = ImportResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportResult) ProtoMessage() {}
+
+func (x *ImportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportResult.ProtoReflect.Descriptor instead.
+func (*ImportResult) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ImportResult) GetIndex() int64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportResult) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ImportResult) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ImportResult) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *ImportResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ImportResponseSite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*ImportResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Created int64           `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	Updated int64           `protobuf:"varint,3,opt,name=updated,proto3" json:"updated,omitempty"`
+	Failed  int64           `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+}
+
+func (x *ImportResponseSite) Reset() {
+	*x = ImportResponseSite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImportResponseSite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportResponseSite) ProtoMessage() {}
+
+func (x *ImportResponseSite) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportResponseSite.ProtoReflect.Descriptor instead.
+func (*ImportResponseSite) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ImportResponseSite) GetResults() []*ImportResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ImportResponseSite) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ImportResponseSite) GetUpdated() int64 {
+	if x != nil {
+		return x.Updated
+	}
+	return 0
+}
+
+func (x *ImportResponseSite) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// Objective is the percent of good checks of the sites over the rolling window,
+// the sites are selected by site_id or by the selector, every site is selected
+// when both are empty. kind is "availability" or "latency", a good check of the
+// latency objective is answered within threshold_ms.
+type Objective struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id       int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	SiteId   int64  `protobuf:"varint,3,opt,name=site_id,json=siteId,proto3" json:"site_id,omitempty"`
+	Selector string `protobuf:"bytes,4,opt,name=selector,proto3" json:"selector,omitempty"`
+	Kind     string `protobuf:"bytes,5,opt,name=kind,proto3" json:"kind,omitempty"`
+	// target is the percent of good checks, like 99.9
+	Target        float64                `protobuf:"fixed64,6,opt,name=target,proto3" json:"target,omitempty"`
+	ThresholdMs   int64                  `protobuf:"varint,7,opt,name=threshold_ms,json=thresholdMs,proto3" json:"threshold_ms,omitempty"`
+	WindowSeconds int64                  `protobuf:"varint,8,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+}
+
+func (x *Objective) Reset() {
+	*x = Objective{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Objective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Objective) ProtoMessage() {}
+
+func (x *Objective) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Objective.ProtoReflect.Descriptor instead.
+func (*Objective) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *Objective) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Objective) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Objective) GetSiteId() int64 {
+	if x != nil {
+		return x.SiteId
+	}
+	return 0
+}
+
+func (x *Objective) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *Objective) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Objective) GetTarget() float64 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *Objective) GetThresholdMs() int64 {
+	if x != nil {
+		return x.ThresholdMs
+	}
+	return 0
+}
+
+func (x *Objective) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *Objective) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// ObjectiveStatus is the error budget of the objective, the burn rate is
+// the share of bad checks divided by the share allowed by the objective.
+// The status is empty when the objective is not tracked yet.
+type ObjectiveStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Objective   *Objective `protobuf:"bytes,1,opt,name=objective,proto3" json:"objective,omitempty"`
+	Tracked     bool       `protobuf:"varint,2,opt,name=tracked,proto3" json:"tracked,omitempty"`
+	Checks      int64      `protobuf:"varint,3,opt,name=checks,proto3" json:"checks,omitempty"`
+	Bad         int64      `protobuf:"varint,4,opt,name=bad,proto3" json:"bad,omitempty"`
+	GoodPercent float64    `protobuf:"fixed64,5,opt,name=good_percent,json=goodPercent,proto3" json:"good_percent,omitempty"`
+	// budget_remaining_percent is below 0 when the budget is exhausted
+	BudgetRemainingPercent float64 `protobuf:"fixed64,6,opt,name=budget_remaining_percent,json=budgetRemainingPercent,proto3" json:"budget_remaining_percent,omitempty"`
+	BurnRate_5M            float64 `protobuf:"fixed64,7,opt,name=burn_rate_5m,json=burnRate5m,proto3" json:"burn_rate_5m,omitempty"`
+	BurnRate_30M           float64 `protobuf:"fixed64,8,opt,name=burn_rate_30m,json=burnRate30m,proto3" json:"burn_rate_30m,omitempty"`
+	BurnRate_1H            float64 `protobuf:"fixed64,9,opt,name=burn_rate_1h,json=burnRate1h,proto3" json:"burn_rate_1h,omitempty"`
+	BurnRate_6H            float64 `protobuf:"fixed64,10,opt,name=burn_rate_6h,json=burnRate6h,proto3" json:"burn_rate_6h,omitempty"`
+	// alert is "fast_burn", "slow_burn" or empty
+	Alert string `protobuf:"bytes,11,opt,name=alert,proto3" json:"alert,omitempty"`
+}
+
+func (x *ObjectiveStatus) Reset() {
+	*x = ObjectiveStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ObjectiveStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectiveStatus) ProtoMessage() {}
+
+func (x *ObjectiveStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectiveStatus.ProtoReflect.Descriptor instead.
+func (*ObjectiveStatus) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ObjectiveStatus) GetObjective() *Objective {
+	if x != nil {
+		return x.Objective
+	}
+	return nil
+}
+
+func (x *ObjectiveStatus) GetTracked() bool {
+	if x != nil {
+		return x.Tracked
+	}
+	return false
+}
+
+func (x *ObjectiveStatus) GetChecks() int64 {
+	if x != nil {
+		return x.Checks
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetBad() int64 {
+	if x != nil {
+		return x.Bad
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetGoodPercent() float64 {
+	if x != nil {
+		return x.GoodPercent
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetBudgetRemainingPercent() float64 {
+	if x != nil {
+		return x.BudgetRemainingPercent
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetBurnRate_5M() float64 {
+	if x != nil {
+		return x.BurnRate_5M
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetBurnRate_30M() float64 {
+	if x != nil {
+		return x.BurnRate_30M
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetBurnRate_1H() float64 {
+	if x != nil {
+		return x.BurnRate_1H
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetBurnRate_6H() float64 {
+	if x != nil {
+		return x.BurnRate_6H
+	}
+	return 0
+}
+
+func (x *ObjectiveStatus) GetAlert() string {
+	if x != nil {
+		return x.Alert
+	}
+	return ""
+}
+
+type CreateRequestObjective struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Objective *Objective `protobuf:"bytes,1,opt,name=objective,proto3" json:"objective,omitempty"`
+}
+
+func (x *CreateRequestObjective) Reset() {
+	*x = CreateRequestObjective{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateRequestObjective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequestObjective) ProtoMessage() {}
+
+func (x *CreateRequestObjective) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateRequestObjective.ProtoReflect.Descriptor instead.
+func (*CreateRequestObjective) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CreateRequestObjective) GetObjective() *Objective {
+	if x != nil {
+		return x.Objective
+	}
+	return nil
+}
+
+type CreateResponseObjective struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Objective *Objective `protobuf:"bytes,1,opt,name=objective,proto3" json:"objective,omitempty"`
+}
+
+func (x *CreateResponseObjective) Reset() {
+	*x = CreateResponseObjective{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateResponseObjective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResponseObjective) ProtoMessage() {}
+
+func (x *CreateResponseObjective) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResponseObjective.ProtoReflect.Descriptor instead.
+func (*CreateResponseObjective) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CreateResponseObjective) GetObjective() *Objective {
+	if x != nil {
+		return x.Objective
+	}
+	return nil
+}
+
+type ListRequestObjective struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListRequestObjective) Reset() {
+	*x = ListRequestObjective{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListRequestObjective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequestObjective) ProtoMessage() {}
+
+func (x *ListRequestObjective) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequestObjective.ProtoReflect.Descriptor instead.
+func (*ListRequestObjective) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{38}
+}
+
+type ListResponseObjective struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Objectives []*ObjectiveStatus `protobuf:"bytes,1,rep,name=objectives,proto3" json:"objectives,omitempty"`
+}
+
+func (x *ListResponseObjective) Reset() {
+	*x = ListResponseObjective{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListResponseObjective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponseObjective) ProtoMessage() {}
+
+func (x *ListResponseObjective) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponseObjective.ProtoReflect.Descriptor instead.
+func (*ListResponseObjective) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ListResponseObjective) GetObjectives() []*ObjectiveStatus {
+	if x != nil {
+		return x.Objectives
+	}
+	return nil
+}
+
+type DeleteRequestObjective struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteRequestObjective) Reset() {
+	*x = DeleteRequestObjective{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequestObjective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequestObjective) ProtoMessage() {}
+
+func (x *DeleteRequestObjective) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequestObjective.ProtoReflect.Descriptor instead.
+func (*DeleteRequestObjective) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DeleteRequestObjective) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteResponseObjective struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Deleted int64 `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+}
+
+func (x *DeleteResponseObjective) Reset() {
+	*x = DeleteResponseObjective{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_test_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteResponseObjective) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponseObjective) ProtoMessage() {}
+
+func (x *DeleteResponseObjective) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_test_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponseObjective.ProtoReflect.Descriptor instead.
+func (*DeleteResponseObjective) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_test_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DeleteResponseObjective) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+var File_pkg_proto_test_proto protoreflect.FileDescriptor
+
+var file_pkg_proto_test_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x65, 0x73, 0x74,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66,
+	0x69, 0x65, 0x6c, 0x64, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xd2, 0x04, 0x0a, 0x04, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x66,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x78,
+	0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x1f,
+	0x0a, 0x0b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x65, 0x72, 0x74, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x12,
+	0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x1b,
+	0x0a, 0x09, 0x63, 0x61, 0x5f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x63, 0x61, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x6b, 0x69, 0x70, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0a, 0x73, 0x6b, 0x69, 0x70, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x1f, 0x0a, 0x0b,
+	0x61, 0x75, 0x74, 0x68, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0a, 0x61, 0x75, 0x74, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x26, 0x0a,
+	0x0f, 0x62, 0x61, 0x73, 0x69, 0x63, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x75, 0x73, 0x65, 0x72,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x62, 0x61, 0x73, 0x69, 0x63, 0x41, 0x75, 0x74,
+	0x68, 0x55, 0x73, 0x65, 0x72, 0x12, 0x2a, 0x0a, 0x11, 0x62, 0x61, 0x73, 0x69, 0x63, 0x5f, 0x61,
+	0x75, 0x74, 0x68, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x62, 0x61, 0x73, 0x69, 0x63, 0x41, 0x75, 0x74, 0x68, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x12, 0x2f, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x2e, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65,
+	0x6c, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x72, 0x6f, 0x78, 0x79,
+	0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x70,
+	0x72, 0x6f, 0x78, 0x79, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xdf, 0x01, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x2e, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x74, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x69, 0x74, 0x65, 0x49, 0x64,
+	0x12, 0x1d, 0x0a, 0x0a, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72,
+	0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72,
+	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x98, 0x02, 0x0a, 0x06, 0x52, 0x6f, 0x6c, 0x6c,
+	0x75, 0x70, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x72,
+	0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0a, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x16, 0x0a,
+	0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x70, 0x5f, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x07, 0x75, 0x70, 0x52, 0x61, 0x74, 0x69, 0x6f,
+	0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x35, 0x30, 0x5f,
+	0x6d, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x50, 0x35, 0x30, 0x4d, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x5f, 0x70, 0x39, 0x30, 0x5f, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x30, 0x4d, 0x73, 0x12, 0x24, 0x0a, 0x0e,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x39, 0x39, 0x5f, 0x6d, 0x73, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x39,
+	0x4d, 0x73, 0x22, 0xbc, 0x01, 0x0a, 0x0e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x24, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74,
+	0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x07, 0x72,
+	0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x6f, 0x6c, 0x6c, 0x75, 0x70, 0x52, 0x07, 0x72, 0x6f, 0x6c,
+	0x6c, 0x75, 0x70, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65,
+	0x73, 0x22, 0x56, 0x0a, 0x10, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x22, 0xdd, 0x01, 0x0a, 0x12, 0x52, 0x65,
+	0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x73, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f,
+	0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x1b, 0x0a,
+	0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61,
+	0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x75, 0x0a, 0x13, 0x52, 0x65, 0x61,
+	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
+	0x72, 0x6c, 0x12, 0x24, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74,
+	0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e,
+	0x22, 0xa8, 0x01, 0x0a, 0x13, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x73, 0x69, 0x74, 0x65,
+	0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x07, 0x73, 0x69, 0x74, 0x65,
+	0x49, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12,
+	0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12,
+	0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x22, 0xe4, 0x02, 0x0a, 0x0a,
+	0x53, 0x69, 0x74, 0x65, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69,
+	0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x69, 0x74,
+	0x65, 0x49, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x25, 0x0a,
+	0x0e, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x75, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x50, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x12, 0x13, 0x0a, 0x05, 0x75, 0x70, 0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x75, 0x70, 0x4d, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x6f, 0x77,
+	0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x64, 0x6f, 0x77, 0x6e,
+	0x4d, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x67, 0x61, 0x70, 0x5f, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x67, 0x61, 0x70, 0x4d, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6e, 0x63,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x69, 0x6e,
+	0x63, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x74, 0x74, 0x72, 0x5f,
+	0x6d, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6d, 0x74, 0x74, 0x72, 0x4d, 0x73,
+	0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x35, 0x30, 0x5f,
+	0x6d, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x50, 0x35, 0x30, 0x4d, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x5f, 0x70, 0x39, 0x30, 0x5f, 0x6d, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x30, 0x4d, 0x73, 0x12, 0x24, 0x0a, 0x0e,
+	0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x39, 0x39, 0x5f, 0x6d, 0x73, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x39,
+	0x4d, 0x73, 0x22, 0x9b, 0x01, 0x0a, 0x14, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x66,
+	0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74,
+	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x27, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53,
+	0x69, 0x74, 0x65, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73,
+	0x22, 0x50, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x74,
+	0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x74,
+	0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x72, 0x65, 0x73, 0x74, 0x6f,
+	0x72, 0x65, 0x22, 0x24, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x21, 0x0a, 0x0f, 0x52, 0x65, 0x61, 0x64,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x35, 0x0a, 0x10, 0x52,
+	0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12,
+	0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74,
+	0x65, 0x73, 0x22, 0x8a, 0x02, 0x0a, 0x12, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x5f, 0x62, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x42, 0x79, 0x12, 0x21, 0x0a, 0x0c,
+	0x75, 0x72, 0x6c, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x75, 0x72, 0x6c, 0x43, 0x6f, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x66, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x6d, 0x69,
+	0x6e, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61,
+	0x78, 0x5f, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x46, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x22,
+	0x60, 0x0a, 0x13, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69,
+	0x74, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78,
+	0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x22, 0x73, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69,
+	0x74, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4d, 0x61, 0x73, 0x6b, 0x22, 0x51, 0x0a, 0x12, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69,
+	0x74, 0x65, 0x52, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2e,
+	0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x53, 0x69, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x22, 0x18,
+	0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x22, 0x3c, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53,
+	0x69, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x52,
+	0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x22, 0x24, 0x0a, 0x12, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x31, 0x0a, 0x13,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53,
+	0x69, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x22,
+	0x22, 0x0a, 0x10, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53,
+	0x69, 0x74, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x02, 0x69, 0x64, 0x22, 0x2b, 0x0a, 0x11, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x75, 0x72, 0x67,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x70, 0x75, 0x72, 0x67, 0x65, 0x64,
+	0x22, 0xe1, 0x01, 0x0a, 0x0a, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x2e, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06,
+	0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x65,
+	0x66, 0x6f, 0x72, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64,
+	0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x22, 0xd1, 0x01, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x73, 0x69, 0x74,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x69, 0x74, 0x65,
+	0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x2a, 0x0a, 0x02, 0x74, 0x6f, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x02,
+	0x74, 0x6f, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x3e, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x41, 0x75, 0x64, 0x69, 0x74, 0x12, 0x29, 0x0a,
+	0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x52, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x75, 0x0a, 0x11, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a,
+	0x08, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52,
+	0x07, 0x73, 0x69, 0x74, 0x65, 0x49, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x12, 0x29, 0x0a, 0x10, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f,
+	0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x22,
+	0x7a, 0x0a, 0x12, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x75,
+	0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x75, 0x70, 0x12, 0x1e, 0x0a, 0x0a, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x36, 0x0a, 0x11, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65,
+	0x12, 0x21, 0x0a, 0x05, 0x73, 0x69, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x69, 0x74, 0x65, 0x52, 0x05, 0x73, 0x69,
+	0x74, 0x65, 0x73, 0x22, 0x76, 0x0a, 0x0c, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x8f, 0x01, 0x0a, 0x12,
+	0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69,
+	0x74, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x6f,
+	0x72, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x22, 0x95, 0x02,
+	0x0a, 0x09, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x73, 0x69, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x73, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x6d, 0x73,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x4d, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x39, 0x0a, 0x0a, 0x63, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x82, 0x03, 0x0a, 0x0f, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2e, 0x0a, 0x09, 0x6f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x52, 0x09,
+	0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x62,
+	0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x62, 0x61, 0x64, 0x12, 0x21, 0x0a,
+	0x0c, 0x67, 0x6f, 0x6f, 0x64, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x67, 0x6f, 0x6f, 0x64, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74,
+	0x12, 0x38, 0x0a, 0x18, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x5f, 0x72, 0x65, 0x6d, 0x61, 0x69,
+	0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x16, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x52, 0x65, 0x6d, 0x61, 0x69, 0x6e,
+	0x69, 0x6e, 0x67, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x20, 0x0a, 0x0c, 0x62, 0x75,
+	0x72, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x35, 0x6d, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x62, 0x75, 0x72, 0x6e, 0x52, 0x61, 0x74, 0x65, 0x35, 0x6d, 0x12, 0x22, 0x0a, 0x0d,
+	0x62, 0x75, 0x72, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x33, 0x30, 0x6d, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x0b, 0x62, 0x75, 0x72, 0x6e, 0x52, 0x61, 0x74, 0x65, 0x33, 0x30, 0x6d,
+	0x12, 0x20, 0x0a, 0x0c, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x31, 0x68,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x62, 0x75, 0x72, 0x6e, 0x52, 0x61, 0x74, 0x65,
+	0x31, 0x68, 0x12, 0x20, 0x0a, 0x0c, 0x62, 0x75, 0x72, 0x6e, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f,
+	0x36, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x62, 0x75, 0x72, 0x6e, 0x52, 0x61,
+	0x74, 0x65, 0x36, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x6c, 0x65, 0x72, 0x74, 0x22, 0x48, 0x0a, 0x16, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x12, 0x2e, 0x0a, 0x09, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x52, 0x09, 0x6f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x22, 0x49, 0x0a, 0x17, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12,
+	0x2e, 0x0a, 0x09, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x62, 0x6a, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x52, 0x09, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x22,
+	0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x22, 0x4f, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x12, 0x36, 0x0a, 0x0a, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x62, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0a, 0x6f, 0x62,
+	0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x73, 0x22, 0x28, 0x0a, 0x16, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x33, 0x0a, 0x17, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x32, 0xa5, 0x09, 0x0a, 0x0c, 0x53, 0x69, 0x74, 0x65,
+	0x73, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x19, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x04, 0x52, 0x65, 0x61, 0x64, 0x12,
+	0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65,
+	0x12, 0x40, 0x0a, 0x07, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x12, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x65, 0x61, 0x64, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69,
+	0x74, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74,
+	0x65, 0x12, 0x3d, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x18, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65,
+	0x12, 0x4c, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12,
+	0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x1e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x40,
+	0x0a, 0x07, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x53, 0x69, 0x74, 0x65, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x73,
+	0x74, 0x6f, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65,
+	0x12, 0x3a, 0x0a, 0x05, 0x50, 0x75, 0x72, 0x67, 0x65, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69,
+	0x74, 0x65, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x12, 0x44, 0x0a, 0x0f,
+	0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x41, 0x75, 0x64, 0x69, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x41, 0x75, 0x64,
+	0x69, 0x74, 0x12, 0x44, 0x0a, 0x0b, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x53, 0x69, 0x74, 0x65,
+	0x73, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x69, 0x74, 0x65, 0x1a, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x53, 0x69, 0x74, 0x65, 0x28, 0x01, 0x12, 0x3c, 0x0a, 0x0a, 0x52, 0x65, 0x61, 0x64,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52,
+	0x65, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x1a,
+	0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x52, 0x65, 0x61, 0x64, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65,
+	0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x61, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x4a, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x1a, 0x1b, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x55, 0x70, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x44, 0x0a, 0x0b, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x30, 0x01, 0x12, 0x50,
+	0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x1a, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x12, 0x4b, 0x0a, 0x0e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x1a,
+	0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x12, 0x50, 0x0a,
+	0x0f, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x12, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x1a,
+	0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42,
+	0x15, 0x5a, 0x13, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x55, 0x72, 0x6c, 0x73, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_pkg_proto_test_proto_rawDescOnce sync.Once
+	file_pkg_proto_test_proto_rawDescData = file_pkg_proto_test_proto_rawDesc
+)
+
+func file_pkg_proto_test_proto_rawDescGZIP() []byte {
+	file_pkg_proto_test_proto_rawDescOnce.Do(func() {
+		file_pkg_proto_test_proto_rawDescData = protoimpl.X.CompressGZIP(file_pkg_proto_test_proto_rawDescData)
+	})
+	return file_pkg_proto_test_proto_rawDescData
+}
+
+var file_pkg_proto_test_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
+var file_pkg_proto_test_proto_goTypes = []interface{}{
+	(*Site)(nil),                    // 0: proto.Site
+	(*State)(nil),                   // 1: proto.State
+	(*Rollup)(nil),                  // 2: proto.Rollup
+	(*StatusResponse)(nil),          // 3: proto.StatusResponse
+	(*ReadRequestState)(nil),        // 4: proto.ReadRequestState
+	(*ReadRequestHistory)(nil),      // 5: proto.ReadRequestHistory
+	(*ReadResponseHistory)(nil),     // 6: proto.ReadResponseHistory
+	(*ReportRequestUptime)(nil),     // 7: proto.ReportRequestUptime
+	(*SiteUptime)(nil),              // 8: proto.SiteUptime
+	(*ReportResponseUptime)(nil),    // 9: proto.ReportResponseUptime
+	(*CreateRequestSite)(nil),       // 10: proto.CreateRequestSite
+	(*CreateResponseSite)(nil),      // 11: proto.CreateResponseSite
+	(*ReadRequestSite)(nil),         // 12: proto.ReadRequestSite
+	(*ReadResponseSite)(nil),        // 13: proto.ReadResponseSite
+	(*ReadAllRequestSite)(nil),      // 14: proto.ReadAllRequestSite
+	(*ReadAllResponseSite)(nil),     // 15: proto.ReadAllResponseSite
+	(*UpdateRequestSite)(nil),       // 16: proto.UpdateRequestSite
+	(*UpdateResponseSite)(nil),      // 17: proto.UpdateResponseSite
+	(*DeleteRequestSite)(nil),       // 18: proto.DeleteRequestSite
+	(*DeleteResponseSite)(nil),      // 19: proto.DeleteResponseSite
+	(*ListDeletedRequestSite)(nil),  // 20: proto.ListDeletedRequestSite
+	(*ListDeletedResponseSite)(nil), // 21: proto.ListDeletedResponseSite
+	(*RestoreRequestSite)(nil),      // 22: proto.RestoreRequestSite
+	(*RestoreResponseSite)(nil),     // 23: proto.RestoreResponseSite
+	(*PurgeRequestSite)(nil),        // 24: proto.PurgeRequestSite
+	(*PurgeResponseSite)(nil),       // 25: proto.PurgeResponseSite
+	(*AuditEvent)(nil),              // 26: proto.AuditEvent
+	(*ListRequestAudit)(nil),        // 27: proto.ListRequestAudit
+	(*ListResponseAudit)(nil),       // 28: proto.ListResponseAudit
+	(*WatchRequestState)(nil),       // 29: proto.WatchRequestState
+	(*WatchResponseState)(nil),      // 30: proto.WatchResponseState
+	(*ImportRequestSite)(nil),       // 31: proto.ImportRequestSite
+	(*ImportResult)(nil),            // 32: proto.ImportResult
+	(*ImportResponseSite)(nil),      // 33: proto.ImportResponseSite
+	(*Objective)(nil),               // 34: proto.Objective
+	(*ObjectiveStatus)(nil),         // 35: proto.ObjectiveStatus
+	(*CreateRequestObjective)(nil),  // 36: proto.CreateRequestObjective
+	(*CreateResponseObjective)(nil), // 37: proto.CreateResponseObjective
+	(*ListRequestObjective)(nil),    // 38: proto.ListRequestObjective
+	(*ListResponseObjective)(nil),   // 39: proto.ListResponseObjective
+	(*DeleteRequestObjective)(nil),  // 40: proto.DeleteRequestObjective
+	(*DeleteResponseObjective)(nil), // 41: proto.DeleteResponseObjective
+	nil,                             // 42: proto.Site.LabelsEntry
+	(*timestamppb.Timestamp)(nil),   // 43: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),   // 44: google.protobuf.FieldMask
+}
+var file_pkg_proto_test_proto_depIdxs = []int32{
+	42, // 0: proto.Site.labels:type_name -> proto.Site.LabelsEntry
+	43, // 1: proto.Site.created_at:type_name -> google.protobuf.Timestamp
+	43, // 2: proto.State.date:type_name -> google.protobuf.Timestamp
+	43, // 3: proto.Rollup.start:type_name -> google.protobuf.Timestamp
+	1,  // 4: proto.StatusResponse.states:type_name -> proto.State
+	2,  // 5: proto.StatusResponse.rollups:type_name -> proto.Rollup
+	3,  // 6: proto.StatusResponse.sites:type_name -> proto.StatusResponse
+	43, // 7: proto.ReadRequestHistory.from:type_name -> google.protobuf.Timestamp
+	43, // 8: proto.ReadRequestHistory.to:type_name -> google.protobuf.Timestamp
+	1,  // 9: proto.ReadResponseHistory.states:type_name -> proto.State
+	43, // 10: proto.ReportRequestUptime.from:type_name -> google.protobuf.Timestamp
+	43, // 11: proto.ReportRequestUptime.to:type_name -> google.protobuf.Timestamp
+	43, // 12: proto.ReportResponseUptime.from:type_name -> google.protobuf.Timestamp
+	43, // 13: proto.ReportResponseUptime.to:type_name -> google.protobuf.Timestamp
+	8,  // 14: proto.ReportResponseUptime.sites:type_name -> proto.SiteUptime
+	0,  // 15: proto.CreateRequestSite.sites:type_name -> proto.Site
+	0,  // 16: proto.ReadResponseSite.sites:type_name -> proto.Site
+	0,  // 17: proto.ReadAllResponseSite.sites:type_name -> proto.Site
+	0,  // 18: proto.UpdateRequestSite.sites:type_name -> proto.Site
+	44, // 19: proto.UpdateRequestSite.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 20: proto.UpdateResponseSite.sites:type_name -> proto.Site
+	0,  // 21: proto.ListDeletedResponseSite.sites:type_name -> proto.Site
+	43, // 22: proto.AuditEvent.date:type_name -> google.protobuf.Timestamp
+	43, // 23: proto.ListRequestAudit.from:type_name -> google.protobuf.Timestamp
+	43, // 24: proto.ListRequestAudit.to:type_name -> google.protobuf.Timestamp
+	26, // 25: proto.ListResponseAudit.events:type_name -> proto.AuditEvent
+	1,  // 26: proto.WatchResponseState.state:type_name -> proto.State
+	0,  // 27: proto.ImportRequestSite.sites:type_name -> proto.Site
+	32, // 28: proto.ImportResponseSite.results:type_name -> proto.ImportResult
+	43, // 29: proto.Objective.created_at:type_name -> google.protobuf.Timestamp
+	34, // 30: proto.ObjectiveStatus.objective:type_name -> proto.Objective
+	34, // 31: proto.CreateRequestObjective.objective:type_name -> proto.Objective
+	34, // 32: proto.CreateResponseObjective.objective:type_name -> proto.Objective
+	35, // 33: proto.ListResponseObjective.objectives:type_name -> proto.ObjectiveStatus
+	10, // 34: proto.SitesService.Create:input_type -> proto.CreateRequestSite
+	12, // 35: proto.SitesService.Read:input_type -> proto.ReadRequestSite
+	14, // 36: proto.SitesService.ReadAll:input_type -> proto.ReadAllRequestSite
+	16, // 37: proto.SitesService.Update:input_type -> proto.UpdateRequestSite
+	18, // 38: proto.SitesService.Delete:input_type -> proto.DeleteRequestSite
+	20, // 39: proto.SitesService.ListDeleted:input_type -> proto.ListDeletedRequestSite
+	22, // 40: proto.SitesService.Restore:input_type -> proto.RestoreRequestSite
+	24, // 41: proto.SitesService.Purge:input_type -> proto.PurgeRequestSite
+	27, // 42: proto.SitesService.ListAuditEvents:input_type -> proto.ListRequestAudit
+	31, // 43: proto.SitesService.ImportSites:input_type -> proto.ImportRequestSite
+	4,  // 44: proto.SitesService.ReadStatus:input_type -> proto.ReadRequestState
+	5,  // 45: proto.SitesService.ReadHistory:input_type -> proto.ReadRequestHistory
+	7,  // 46: proto.SitesService.GetUptimeReport:input_type -> proto.ReportRequestUptime
+	29, // 47: proto.SitesService.WatchStatus:input_type -> proto.WatchRequestState
+	36, // 48: proto.SitesService.CreateObjective:input_type -> proto.CreateRequestObjective
+	38, // 49: proto.SitesService.ListObjectives:input_type -> proto.ListRequestObjective
+	40, // 50: proto.SitesService.DeleteObjective:input_type -> proto.DeleteRequestObjective
+	11, // 51: proto.SitesService.Create:output_type -> proto.CreateResponseSite
+	13, // 52: proto.SitesService.Read:output_type -> proto.ReadResponseSite
+	15, // 53: proto.SitesService.ReadAll:output_type -> proto.ReadAllResponseSite
+	17, // 54: proto.SitesService.Update:output_type -> proto.UpdateResponseSite
+	19, // 55: proto.SitesService.Delete:output_type -> proto.DeleteResponseSite
+	21, // 56: proto.SitesService.ListDeleted:output_type -> proto.ListDeletedResponseSite
+	23, // 57: proto.SitesService.Restore:output_type -> proto.RestoreResponseSite
+	25, // 58: proto.SitesService.Purge:output_type -> proto.PurgeResponseSite
+	28, // 59: proto.SitesService.ListAuditEvents:output_type -> proto.ListResponseAudit
+	33, // 60: proto.SitesService.ImportSites:output_type -> proto.ImportResponseSite
+	3,  // 61: proto.SitesService.ReadStatus:output_type -> proto.StatusResponse
+	6,  // 62: proto.SitesService.ReadHistory:output_type -> proto.ReadResponseHistory
+	9,  // 63: proto.SitesService.GetUptimeReport:output_type -> proto.ReportResponseUptime
+	30, // 64: proto.SitesService.WatchStatus:output_type -> proto.WatchResponseState
+	37, // 65: proto.SitesService.CreateObjective:output_type -> proto.CreateResponseObjective
+	39, // 66: proto.SitesService.ListObjectives:output_type -> proto.ListResponseObjective
+	41, // 67: proto.SitesService.DeleteObjective:output_type -> proto.DeleteResponseObjective
+	51, // [51:68] is the sub-list for method output_type
+	34, // [34:51] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
+}
+
+func init() { file_pkg_proto_test_proto_init() }
+func file_pkg_proto_test_proto_init() {
+	if File_pkg_proto_test_proto != nil {
+		return
+	}
 	if !protoimpl.UnsafeEnabled {
 		file_pkg_proto_test_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Site); i {
@@ -894,8 +3519,68 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*State); i {
+		file_pkg_proto_test_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*State); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Rollup); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StatusResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadRequestState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadRequestHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReadResponseHistory); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -906,8 +3591,8 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*StatusResponse); i {
+		file_pkg_proto_test_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportRequestUptime); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -918,8 +3603,8 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReadRequestState); i {
+		file_pkg_proto_test_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SiteUptime); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -930,7 +3615,19 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportResponseUptime); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CreateRequestSite); i {
 			case 0:
 				return &v.state
@@ -942,7 +3639,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*CreateResponseSite); i {
 			case 0:
 				return &v.state
@@ -954,7 +3651,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadRequestSite); i {
 			case 0:
 				return &v.state
@@ -966,7 +3663,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadResponseSite); i {
 			case 0:
 				return &v.state
@@ -978,7 +3675,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadAllRequestSite); i {
 			case 0:
 				return &v.state
@@ -990,7 +3687,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ReadAllResponseSite); i {
 			case 0:
 				return &v.state
@@ -1002,7 +3699,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UpdateRequestSite); i {
 			case 0:
 				return &v.state
@@ -1014,7 +3711,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*UpdateResponseSite); i {
 			case 0:
 				return &v.state
@@ -1026,7 +3723,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteRequestSite); i {
 			case 0:
 				return &v.state
@@ -1038,7 +3735,7 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
-		file_pkg_proto_test_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+		file_pkg_proto_test_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteResponseSite); i {
 			case 0:
 				return &v.state
@@ -1050,6 +3747,270 @@ func file_pkg_proto_test_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_proto_test_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeletedRequestSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListDeletedResponseSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreRequestSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RestoreResponseSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeRequestSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PurgeResponseSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuditEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequestAudit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListResponseAudit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequestState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchResponseState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportRequestSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ImportResponseSite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Objective); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ObjectiveStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequestObjective); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateResponseObjective); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListRequestObjective); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListResponseObjective); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRequestObjective); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_test_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteResponseObjective); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1057,7 +4018,7 @@ func file_pkg_proto_test_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_test_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   14,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
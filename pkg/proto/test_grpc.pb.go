@@ -23,7 +23,18 @@ type SitesServiceClient interface {
 	ReadAll(ctx context.Context, in *ReadAllRequestSite, opts ...grpc.CallOption) (*ReadAllResponseSite, error)
 	Update(ctx context.Context, in *UpdateRequestSite, opts ...grpc.CallOption) (*UpdateResponseSite, error)
 	Delete(ctx context.Context, in *DeleteRequestSite, opts ...grpc.CallOption) (*DeleteResponseSite, error)
+	ListDeleted(ctx context.Context, in *ListDeletedRequestSite, opts ...grpc.CallOption) (*ListDeletedResponseSite, error)
+	Restore(ctx context.Context, in *RestoreRequestSite, opts ...grpc.CallOption) (*RestoreResponseSite, error)
+	Purge(ctx context.Context, in *PurgeRequestSite, opts ...grpc.CallOption) (*PurgeResponseSite, error)
+	ListAuditEvents(ctx context.Context, in *ListRequestAudit, opts ...grpc.CallOption) (*ListResponseAudit, error)
+	ImportSites(ctx context.Context, opts ...grpc.CallOption) (SitesService_ImportSitesClient, error)
 	ReadStatus(ctx context.Context, in *ReadRequestState, opts ...grpc.CallOption) (*StatusResponse, error)
+	ReadHistory(ctx context.Context, in *ReadRequestHistory, opts ...grpc.CallOption) (*ReadResponseHistory, error)
+	GetUptimeReport(ctx context.Context, in *ReportRequestUptime, opts ...grpc.CallOption) (*ReportResponseUptime, error)
+	WatchStatus(ctx context.Context, in *WatchRequestState, opts ...grpc.CallOption) (SitesService_WatchStatusClient, error)
+	CreateObjective(ctx context.Context, in *CreateRequestObjective, opts ...grpc.CallOption) (*CreateResponseObjective, error)
+	ListObjectives(ctx context.Context, in *ListRequestObjective, opts ...grpc.CallOption) (*ListResponseObjective, error)
+	DeleteObjective(ctx context.Context, in *DeleteRequestObjective, opts ...grpc.CallOption) (*DeleteResponseObjective, error)
 }
 
 type sitesServiceClient struct {
@@ -79,6 +90,76 @@ func (c *sitesServiceClient) Delete(ctx context.Context, in *DeleteRequestSite,
 	return out, nil
 }
 
+func (c *sitesServiceClient) ListDeleted(ctx context.Context, in *ListDeletedRequestSite, opts ...grpc.CallOption) (*ListDeletedResponseSite, error) {
+	out := new(ListDeletedResponseSite)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/ListDeleted", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) Restore(ctx context.Context, in *RestoreRequestSite, opts ...grpc.CallOption) (*RestoreResponseSite, error) {
+	out := new(RestoreResponseSite)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/Restore", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) Purge(ctx context.Context, in *PurgeRequestSite, opts ...grpc.CallOption) (*PurgeResponseSite, error) {
+	out := new(PurgeResponseSite)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/Purge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) ListAuditEvents(ctx context.Context, in *ListRequestAudit, opts ...grpc.CallOption) (*ListResponseAudit, error) {
+	out := new(ListResponseAudit)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/ListAuditEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) ImportSites(ctx context.Context, opts ...grpc.CallOption) (SitesService_ImportSitesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SitesService_ServiceDesc.Streams[0], "/proto.SitesService/ImportSites", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sitesServiceImportSitesClient{stream}
+	return x, nil
+}
+
+type SitesService_ImportSitesClient interface {
+	Send(*ImportRequestSite) error
+	CloseAndRecv() (*ImportResponseSite, error)
+	grpc.ClientStream
+}
+
+type sitesServiceImportSitesClient struct {
+	grpc.ClientStream
+}
+
+func (x *sitesServiceImportSitesClient) Send(m *ImportRequestSite) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *sitesServiceImportSitesClient) CloseAndRecv() (*ImportResponseSite, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponseSite)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *sitesServiceClient) ReadStatus(ctx context.Context, in *ReadRequestState, opts ...grpc.CallOption) (*StatusResponse, error) {
 	out := new(StatusResponse)
 	err := c.cc.Invoke(ctx, "/proto.SitesService/ReadStatus", in, out, opts...)
@@ -88,6 +169,83 @@ func (c *sitesServiceClient) ReadStatus(ctx context.Context, in *ReadRequestStat
 	return out, nil
 }
 
+func (c *sitesServiceClient) ReadHistory(ctx context.Context, in *ReadRequestHistory, opts ...grpc.CallOption) (*ReadResponseHistory, error) {
+	out := new(ReadResponseHistory)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/ReadHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) GetUptimeReport(ctx context.Context, in *ReportRequestUptime, opts ...grpc.CallOption) (*ReportResponseUptime, error) {
+	out := new(ReportResponseUptime)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/GetUptimeReport", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) WatchStatus(ctx context.Context, in *WatchRequestState, opts ...grpc.CallOption) (SitesService_WatchStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SitesService_ServiceDesc.Streams[1], "/proto.SitesService/WatchStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sitesServiceWatchStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SitesService_WatchStatusClient interface {
+	Recv() (*WatchResponseState, error)
+	grpc.ClientStream
+}
+
+type sitesServiceWatchStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *sitesServiceWatchStatusClient) Recv() (*WatchResponseState, error) {
+	m := new(WatchResponseState)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *sitesServiceClient) CreateObjective(ctx context.Context, in *CreateRequestObjective, opts ...grpc.CallOption) (*CreateResponseObjective, error) {
+	out := new(CreateResponseObjective)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/CreateObjective", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) ListObjectives(ctx context.Context, in *ListRequestObjective, opts ...grpc.CallOption) (*ListResponseObjective, error) {
+	out := new(ListResponseObjective)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/ListObjectives", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sitesServiceClient) DeleteObjective(ctx context.Context, in *DeleteRequestObjective, opts ...grpc.CallOption) (*DeleteResponseObjective, error) {
+	out := new(DeleteResponseObjective)
+	err := c.cc.Invoke(ctx, "/proto.SitesService/DeleteObjective", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SitesServiceServer is the server API for SitesService service.
 // All implementations must embed UnimplementedSitesServiceServer
 // for forward compatibility
@@ -97,7 +255,18 @@ type SitesServiceServer interface {
 	ReadAll(context.Context, *ReadAllRequestSite) (*ReadAllResponseSite, error)
 	Update(context.Context, *UpdateRequestSite) (*UpdateResponseSite, error)
 	Delete(context.Context, *DeleteRequestSite) (*DeleteResponseSite, error)
+	ListDeleted(context.Context, *ListDeletedRequestSite) (*ListDeletedResponseSite, error)
+	Restore(context.Context, *RestoreRequestSite) (*RestoreResponseSite, error)
+	Purge(context.Context, *PurgeRequestSite) (*PurgeResponseSite, error)
+	ListAuditEvents(context.Context, *ListRequestAudit) (*ListResponseAudit, error)
+	ImportSites(SitesService_ImportSitesServer) error
 	ReadStatus(context.Context, *ReadRequestState) (*StatusResponse, error)
+	ReadHistory(context.Context, *ReadRequestHistory) (*ReadResponseHistory, error)
+	GetUptimeReport(context.Context, *ReportRequestUptime) (*ReportResponseUptime, error)
+	WatchStatus(*WatchRequestState, SitesService_WatchStatusServer) error
+	CreateObjective(context.Context, *CreateRequestObjective) (*CreateResponseObjective, error)
+	ListObjectives(context.Context, *ListRequestObjective) (*ListResponseObjective, error)
+	DeleteObjective(context.Context, *DeleteRequestObjective) (*DeleteResponseObjective, error)
 	mustEmbedUnimplementedSitesServiceServer()
 }
 
@@ -120,9 +289,42 @@ func (UnimplementedSitesServiceServer) Update(context.Context, *UpdateRequestSit
 func (UnimplementedSitesServiceServer) Delete(context.Context, *DeleteRequestSite) (*DeleteResponseSite, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedSitesServiceServer) ListDeleted(context.Context, *ListDeletedRequestSite) (*ListDeletedResponseSite, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeleted not implemented")
+}
+func (UnimplementedSitesServiceServer) Restore(context.Context, *RestoreRequestSite) (*RestoreResponseSite, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedSitesServiceServer) Purge(context.Context, *PurgeRequestSite) (*PurgeResponseSite, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (UnimplementedSitesServiceServer) ListAuditEvents(context.Context, *ListRequestAudit) (*ListResponseAudit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
+func (UnimplementedSitesServiceServer) ImportSites(SitesService_ImportSitesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ImportSites not implemented")
+}
 func (UnimplementedSitesServiceServer) ReadStatus(context.Context, *ReadRequestState) (*StatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReadStatus not implemented")
 }
+func (UnimplementedSitesServiceServer) ReadHistory(context.Context, *ReadRequestHistory) (*ReadResponseHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadHistory not implemented")
+}
+func (UnimplementedSitesServiceServer) GetUptimeReport(context.Context, *ReportRequestUptime) (*ReportResponseUptime, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUptimeReport not implemented")
+}
+func (UnimplementedSitesServiceServer) WatchStatus(*WatchRequestState, SitesService_WatchStatusServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+func (UnimplementedSitesServiceServer) CreateObjective(context.Context, *CreateRequestObjective) (*CreateResponseObjective, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateObjective not implemented")
+}
+func (UnimplementedSitesServiceServer) ListObjectives(context.Context, *ListRequestObjective) (*ListResponseObjective, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListObjectives not implemented")
+}
+func (UnimplementedSitesServiceServer) DeleteObjective(context.Context, *DeleteRequestObjective) (*DeleteResponseObjective, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteObjective not implemented")
+}
 func (UnimplementedSitesServiceServer) mustEmbedUnimplementedSitesServiceServer() {}
 
 // UnsafeSitesServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -226,6 +428,104 @@ func _SitesService_Delete_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SitesService_ListDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeletedRequestSite)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).ListDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/ListDeleted",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).ListDeleted(ctx, req.(*ListDeletedRequestSite))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequestSite)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).Restore(ctx, req.(*RestoreRequestSite))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeRequestSite)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/Purge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).Purge(ctx, req.(*PurgeRequestSite))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequestAudit)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/ListAuditEvents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).ListAuditEvents(ctx, req.(*ListRequestAudit))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_ImportSites_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SitesServiceServer).ImportSites(&sitesServiceImportSitesServer{stream})
+}
+
+type SitesService_ImportSitesServer interface {
+	SendAndClose(*ImportResponseSite) error
+	Recv() (*ImportRequestSite, error)
+	grpc.ServerStream
+}
+
+type sitesServiceImportSitesServer struct {
+	grpc.ServerStream
+}
+
+func (x *sitesServiceImportSitesServer) SendAndClose(m *ImportResponseSite) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *sitesServiceImportSitesServer) Recv() (*ImportRequestSite, error) {
+	m := new(ImportRequestSite)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _SitesService_ReadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReadRequestState)
 	if err := dec(in); err != nil {
@@ -244,6 +544,117 @@ func _SitesService_ReadStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SitesService_ReadHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadRequestHistory)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).ReadHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/ReadHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).ReadHistory(ctx, req.(*ReadRequestHistory))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_GetUptimeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportRequestUptime)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).GetUptimeReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/GetUptimeReport",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).GetUptimeReport(ctx, req.(*ReportRequestUptime))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequestState)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SitesServiceServer).WatchStatus(m, &sitesServiceWatchStatusServer{stream})
+}
+
+type SitesService_WatchStatusServer interface {
+	Send(*WatchResponseState) error
+	grpc.ServerStream
+}
+
+type sitesServiceWatchStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *sitesServiceWatchStatusServer) Send(m *WatchResponseState) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _SitesService_CreateObjective_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequestObjective)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).CreateObjective(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/CreateObjective",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).CreateObjective(ctx, req.(*CreateRequestObjective))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_ListObjectives_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequestObjective)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).ListObjectives(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/ListObjectives",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).ListObjectives(ctx, req.(*ListRequestObjective))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SitesService_DeleteObjective_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequestObjective)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SitesServiceServer).DeleteObjective(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.SitesService/DeleteObjective",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SitesServiceServer).DeleteObjective(ctx, req.(*DeleteRequestObjective))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SitesService_ServiceDesc is the grpc.ServiceDesc for SitesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -271,11 +682,58 @@ var SitesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _SitesService_Delete_Handler,
 		},
+		{
+			MethodName: "ListDeleted",
+			Handler:    _SitesService_ListDeleted_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _SitesService_Restore_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _SitesService_Purge_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _SitesService_ListAuditEvents_Handler,
+		},
 		{
 			MethodName: "ReadStatus",
 			Handler:    _SitesService_ReadStatus_Handler,
 		},
+		{
+			MethodName: "ReadHistory",
+			Handler:    _SitesService_ReadHistory_Handler,
+		},
+		{
+			MethodName: "GetUptimeReport",
+			Handler:    _SitesService_GetUptimeReport_Handler,
+		},
+		{
+			MethodName: "CreateObjective",
+			Handler:    _SitesService_CreateObjective_Handler,
+		},
+		{
+			MethodName: "ListObjectives",
+			Handler:    _SitesService_ListObjectives_Handler,
+		},
+		{
+			MethodName: "DeleteObjective",
+			Handler:    _SitesService_DeleteObjective_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportSites",
+			Handler:       _SitesService_ImportSites_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _SitesService_WatchStatus_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/proto/test.proto",
 }
@@ -0,0 +1,153 @@
+// Package report computes the uptime of sites over a window by their raw checks.
+package report
+
+import (
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"sort"
+	"time"
+)
+
+// coveredIntervals is the number of intervals of the site covered by a check,
+// the check is late by less than an interval when the schedule is jittered
+const coveredIntervals = 2
+
+// Uptime is the availability of the site in the window [From, To).
+// Every check covers the time until the next check, but at most two
+// intervals of the site. Up, Down and Gap split the window, Gap is the
+// time which is not covered by any check, it is not counted as up.
+type Uptime struct {
+	SiteId int64
+	Url    string
+	From   time.Time
+	To     time.Time
+	// Checks is the number of checks in the window
+	Checks int64
+	Up     time.Duration
+	Down   time.Duration
+	Gap    time.Duration
+	// Incidents is the number of times the site went down,
+	// the incident going on at the start of the window is counted
+	Incidents int64
+	// MTTR is the mean time from the first down check to the first up check
+	// of the incidents which ended in the window, 0 if there are none
+	MTTR       time.Duration
+	LatencyP50 time.Duration
+	LatencyP90 time.Duration
+	LatencyP99 time.Duration
+}
+
+// Percent returns the share of up time in the time covered
+// by checks in percents, 0 if there were no checks
+func (u *Uptime) Percent() float64 {
+	if u.Up+u.Down == 0 {
+		return 0
+	}
+	return float64(u.Up) * 100 / float64(u.Up+u.Down)
+}
+
+// Build computes the uptime of the site in [from, to) by its checks in
+// the window ordered from the oldest one, previous is the last check
+// before the window or nil
+func Build(site *sites.Site, previous *statuses.State, states []*statuses.State, from, to time.Time) *Uptime {
+	b := NewBuilder(site, previous, from, to)
+	for _, state := range states {
+		b.Add(state)
+	}
+	return b.Uptime()
+}
+
+// Builder computes the uptime by the checks added from the oldest one,
+// so the checks of a long window are not kept, only their latencies
+type Builder struct {
+	u       *Uptime
+	covered time.Duration
+	// last is the last added check, its time is covered by the next check
+	last          *statuses.State
+	down          bool
+	incidentStart time.Time
+	recovery      time.Duration
+	recovered     int64
+	latencies     []time.Duration
+}
+
+// NewBuilder starts the uptime of the site in [from, to),
+// previous is the last check before the window or nil
+func NewBuilder(site *sites.Site, previous *statuses.State, from, to time.Time) *Builder {
+	b := &Builder{
+		u:       &Uptime{SiteId: site.Id, Url: site.Url, From: from, To: to},
+		covered: coveredIntervals * site.Interval(),
+	}
+	if previous != nil {
+		b.check(previous)
+	}
+	return b
+}
+
+// Add adds the check of the window, which is not older than the added ones
+func (b *Builder) Add(state *statuses.State) {
+	b.u.Checks++
+	b.latencies = append(b.latencies, state.Latency)
+	b.check(state)
+}
+
+// Uptime returns the uptime of the window by the added checks,
+// no checks are added after it
+func (b *Builder) Uptime() *Uptime {
+	u := b.u
+	b.cover(u.To)
+	u.Gap = u.To.Sub(u.From) - u.Up - u.Down
+	if b.recovered != 0 {
+		u.MTTR = b.recovery / time.Duration(b.recovered)
+	}
+
+	sort.Slice(b.latencies, func(i, j int) bool { return b.latencies[i] < b.latencies[j] })
+	u.LatencyP50 = statuses.Percentile(b.latencies, 50)
+	u.LatencyP90 = statuses.Percentile(b.latencies, 90)
+	u.LatencyP99 = statuses.Percentile(b.latencies, 99)
+	return u
+}
+
+// check covers the time of the last check until the check and counts the incidents
+func (b *Builder) check(check *statuses.State) {
+	b.cover(check.Date)
+	b.last = check
+
+	up := check.Up()
+	switch {
+	case !up && !b.down:
+		b.down, b.incidentStart = true, check.Date
+		b.u.Incidents++
+	case up && b.down:
+		b.down = false
+		b.recovery += check.Date.Sub(b.incidentStart)
+		b.recovered++
+	}
+}
+
+// cover counts the time of the last check until next, but in the window
+// and at most the covered intervals of the site, as up or down
+func (b *Builder) cover(next time.Time) {
+	if b.last == nil {
+		return
+	}
+	end := b.u.To
+	if next.Before(end) {
+		end = next
+	}
+	if limit := b.last.Date.Add(b.covered); limit.Before(end) {
+		end = limit
+	}
+	start := b.last.Date
+	if start.Before(b.u.From) {
+		start = b.u.From
+	}
+	if !end.After(start) {
+		return
+	}
+	if b.last.Up() {
+		b.u.Up += end.Sub(start)
+	} else {
+		b.u.Down += end.Sub(start)
+	}
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"testing"
+	"time"
+)
+
+func TestBuild(t *testing.T) {
+	site := &sites.Site{Id: 1, Url: "https://example.com", Frequency: 60}
+	from := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	minute := func(n int) time.Time { return from.Add(time.Duration(n) * time.Minute) }
+	check := func(n int, status int64, latency time.Duration) *statuses.State {
+		return &statuses.State{Date: minute(n), Status: status, SiteId: site.Id, Latency: latency}
+	}
+	// the site is down since before the window, then up, then down for 2 minutes,
+	// then nothing is checked from minute 10, which is covered till minute 12
+	previous := check(-1, 500, 0)
+	states := []*statuses.State{
+		check(0, 200, 10*time.Millisecond),
+		check(1, 200, 20*time.Millisecond),
+		check(2, 503, 30*time.Millisecond),
+		check(3, 0, 40*time.Millisecond),
+		check(4, 200, 50*time.Millisecond),
+		check(10, 200, 60*time.Millisecond),
+	}
+
+	u := Build(site, previous, states, from, minute(20))
+	want := Uptime{
+		SiteId: site.Id, Url: site.Url, From: from, To: minute(20), Checks: 6,
+		// minutes 0-2, 4-6 (the next check is late) and 10-12
+		Up:   6 * time.Minute,
+		Down: 2 * time.Minute,
+		Gap:  12 * time.Minute,
+		// the ongoing incident lasted a minute, the next one 2 minutes
+		Incidents:  2,
+		MTTR:       90 * time.Second,
+		LatencyP50: 30 * time.Millisecond,
+		LatencyP90: 60 * time.Millisecond,
+		LatencyP99: 60 * time.Millisecond,
+	}
+	if *u != want {
+		t.Errorf("Build = %+v, want %+v", *u, want)
+	}
+	if got := u.Percent(); got != 75 {
+		t.Errorf("Percent = %v, want 75", got)
+	}
+}
+
+func TestBuildWithoutChecks(t *testing.T) {
+	site := &sites.Site{Id: 1, Url: "https://example.com"}
+	from := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+
+	u := Build(site, nil, nil, from, to)
+	if u.Gap != to.Sub(from) || u.Up != 0 || u.Down != 0 || u.Incidents != 0 || u.Percent() != 0 {
+		t.Errorf("Build without checks = %+v", *u)
+	}
+
+	// the site without frequency is checked once a day, so a check
+	// before the window covers its first day
+	previous := &statuses.State{Date: from.Add(-24 * time.Hour), Status: 200}
+	u = Build(site, previous, nil, from, to)
+	if u.Up != 24*time.Hour || u.Gap != 48*time.Hour || u.Percent() != 100 {
+		t.Errorf("Build with the previous check = %+v", *u)
+	}
+}
@@ -0,0 +1,121 @@
+// Package audit describes the records of site changes made through the gRPC API.
+package audit
+
+import (
+	"CheckUrls/pkg/repository/sites"
+	"encoding/json"
+	"time"
+)
+
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	// OperationRestore restores the deleted site, by its url or id
+	OperationRestore = "restore"
+	OperationPurge   = "purge"
+)
+
+// DefaultLimit is the number of events returned when the filter has no limit
+const DefaultLimit = 100
+
+// Event is a change of the site made by the actor. Before and After
+// are the site in JSON before and after the change, empty if the site
+// did not exist or its data must not be kept.
+type Event struct {
+	Id   int64
+	Date time.Time
+	// Actor is the name of the user, Address is the address of the client
+	Actor     string
+	Address   string
+	Operation string
+	SiteId    int64
+	Before    string
+	After     string
+}
+
+// Record completes the event by the change of the site, before is nil
+// when the site is created. Creating the site for the checked url
+// updates it, so the event becomes OperationUpdate.
+func (e *Event) Record(before, after *sites.Site) {
+	if before != nil && e.Operation == OperationCreate {
+		e.Operation = OperationUpdate
+	}
+	e.SiteId = after.Id
+	e.Before = snapshot(before)
+	e.After = snapshot(after)
+}
+
+// Filter selects the events, the zero fields match any event.
+// The events are returned from the latest one.
+type Filter struct {
+	SiteId int64
+	// Actor is the name of the user
+	Actor     string
+	Operation string
+	// From is inclusive and To is exclusive
+	From  time.Time
+	To    time.Time
+	Limit int64
+}
+
+// Match reports whether the event is selected by the filter
+func (f Filter) Match(e *Event) bool {
+	switch {
+	case f.SiteId != 0 && e.SiteId != f.SiteId:
+		return false
+	case f.Actor != "" && e.Actor != f.Actor:
+		return false
+	case f.Operation != "" && e.Operation != f.Operation:
+		return false
+	case !f.From.IsZero() && e.Date.Before(f.From):
+		return false
+	case !f.To.IsZero() && !e.Date.Before(f.To):
+		return false
+	}
+	return true
+}
+
+// Size returns the limit of the filter or DefaultLimit
+func (f Filter) Size() int64 {
+	if f.Limit <= 0 {
+		return DefaultLimit
+	}
+	return f.Limit
+}
+
+// site is the site in JSON, the secrets are referenced by names and
+// paths, so the only value to hide is the password of the proxy
+type site struct {
+	Id              int64             `json:"id"`
+	Url             string            `json:"url"`
+	Frequency       int64             `json:"frequency"`
+	Deleted         bool              `json:"deleted"`
+	CheckMode       string            `json:"check_mode"`
+	Proxy           string            `json:"proxy,omitempty"`
+	ProxySecret     string            `json:"proxy_secret,omitempty"`
+	ClientCert      string            `json:"client_cert,omitempty"`
+	ClientKey       string            `json:"client_key,omitempty"`
+	CaBundle        string            `json:"ca_bundle,omitempty"`
+	SkipVerify      bool              `json:"skip_verify,omitempty"`
+	AuthSecret      string            `json:"auth_secret,omitempty"`
+	BasicAuthUser   string            `json:"basic_auth_user,omitempty"`
+	BasicAuthSecret string            `json:"basic_auth_secret,omitempty"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	Version         int64             `json:"version"`
+}
+
+func snapshot(s *sites.Site) string {
+	if s == nil {
+		return ""
+	}
+	redacted := site(*s)
+	redacted.Proxy = s.RedactedProxy()
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		// the fields are strings, numbers, booleans, a map of strings and a time
+		panic(err)
+	}
+	return string(data)
+}
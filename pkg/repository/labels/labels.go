@@ -0,0 +1,238 @@
+// Package labels validates the key/value labels of sites and parses
+// label selectors in the syntax of Kubernetes, like
+// "team=payments,env in (prod, staging),!deprecated".
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrIncorrectLabel    = fmt.Errorf("incorrect label")
+	ErrIncorrectSelector = fmt.Errorf("incorrect label selector")
+)
+
+const (
+	// maxName is the length limit of values and names of keys
+	maxName = 63
+	// maxPrefix is the length limit of the DNS prefix of keys
+	maxPrefix = 253
+)
+
+var (
+	namePattern   = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+	prefixPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+	setPattern    = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+)
+
+type Operator string
+
+const (
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+)
+
+// Requirement is a condition on one label, NotEquals and NotIn
+// are met by the sites without the label as well
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+// Selector selects the sites which meet all requirements,
+// the empty selector selects every site
+type Selector []Requirement
+
+// Validate checks the keys and the values of the labels
+func Validate(labels map[string]string) error {
+	for key, value := range labels {
+		if err := validateKey(key); err != nil {
+			return err
+		}
+		if err := validateValue(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Parse parses the selector, the requirements are separated by commas
+func Parse(selector string) (Selector, error) {
+	var result Selector
+	for _, part := range split(selector) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			if strings.TrimSpace(selector) == "" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("%w: empty requirement", ErrIncorrectSelector)
+		}
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// Matches reports whether the labels meet all requirements
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches reports whether the labels meet the requirement
+func (r Requirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case Exists:
+		return ok
+	case DoesNotExist:
+		return !ok
+	case Equals, In:
+		return ok && contains(r.Values, value)
+	case NotEquals, NotIn:
+		return !ok || !contains(r.Values, value)
+	}
+	return false
+}
+
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		switch r.Operator {
+		case Exists:
+			parts = append(parts, r.Key)
+		case DoesNotExist:
+			parts = append(parts, "!"+r.Key)
+		case In, NotIn:
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", r.Key, r.Operator, strings.Join(r.Values, ", ")))
+		default:
+			parts = append(parts, r.Key+string(r.Operator)+r.Values[0])
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// String formats the labels as "key=value" pairs sorted by keys
+func String(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+labels[key])
+	}
+	return strings.Join(parts, ",")
+}
+
+// split splits the selector by the commas which are not in the value lists
+func split(selector string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, selector[start:])
+}
+
+func parseRequirement(part string) (Requirement, error) {
+	var r Requirement
+	switch {
+	case strings.HasPrefix(part, "!") && !strings.Contains(part, "="):
+		r = Requirement{Key: strings.TrimSpace(part[1:]), Operator: DoesNotExist}
+	case strings.Contains(part, "!="):
+		key, value, _ := strings.Cut(part, "!=")
+		r = Requirement{Key: strings.TrimSpace(key), Operator: NotEquals, Values: []string{strings.TrimSpace(value)}}
+	case strings.Contains(part, "="):
+		key, value, _ := strings.Cut(part, "=")
+		value = strings.TrimPrefix(value, "=")
+		r = Requirement{Key: strings.TrimSpace(key), Operator: Equals, Values: []string{strings.TrimSpace(value)}}
+	case setPattern.MatchString(part):
+		match := setPattern.FindStringSubmatch(part)
+		r = Requirement{Key: match[1], Operator: Operator(match[2])}
+		for _, value := range strings.Split(match[3], ",") {
+			r.Values = append(r.Values, strings.TrimSpace(value))
+		}
+	case !strings.ContainsAny(part, " ()"):
+		r = Requirement{Key: part, Operator: Exists}
+	default:
+		return r, fmt.Errorf("%w: %q", ErrIncorrectSelector, part)
+	}
+
+	if err := validateKey(r.Key); err != nil {
+		return r, fmt.Errorf("%w: %q: %v", ErrIncorrectSelector, part, err)
+	}
+	for _, value := range r.Values {
+		if err := validateValue(value); err != nil {
+			return r, fmt.Errorf("%w: %q: %v", ErrIncorrectSelector, part, err)
+		}
+	}
+	if (r.Operator == In || r.Operator == NotIn) && len(r.Values) == 1 && r.Values[0] == "" {
+		return r, fmt.Errorf("%w: %q: empty list of values", ErrIncorrectSelector, part)
+	}
+	return r, nil
+}
+
+// validateKey checks the key, which is a name with an optional
+// DNS prefix, like "team" or "example.com/team"
+func validateKey(key string) error {
+	name := key
+	if prefix, rest, ok := strings.Cut(key, "/"); ok {
+		if len(prefix) > maxPrefix || !prefixPattern.MatchString(prefix) {
+			return fmt.Errorf("%w: incorrect prefix of key %q", ErrIncorrectLabel, key)
+		}
+		name = rest
+	}
+	if len(name) > maxName || !namePattern.MatchString(name) {
+		return fmt.Errorf("%w: incorrect key %q, it must be up to %d letters, digits, "+
+			"'-', '_' or '.' starting and ending with a letter or digit", ErrIncorrectLabel, key, maxName)
+	}
+	return nil
+}
+
+// validateValue checks the value, which can be empty
+func validateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxName || !namePattern.MatchString(value) {
+		return fmt.Errorf("%w: incorrect value %q, it must be up to %d letters, digits, "+
+			"'-', '_' or '.' starting and ending with a letter or digit", ErrIncorrectLabel, value, maxName)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package labels
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{"", ""},
+		{"team=payments", "team=payments"},
+		{"team == payments", "team=payments"},
+		{"env!=prod", "env!=prod"},
+		{"env in (prod, staging)", "env in (prod, staging)"},
+		{"env notin (dev)", "env notin (dev)"},
+		{"canary", "canary"},
+		{"!deprecated", "!deprecated"},
+		{"example.com/team=payments, env in (prod,staging), !deprecated",
+			"example.com/team=payments,env in (prod, staging),!deprecated"},
+		{"tier=", "tier="},
+	}
+	for _, tt := range tests {
+		selector, err := Parse(tt.selector)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.selector, err)
+			continue
+		}
+		if got := selector.String(); got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestParseIncorrect(t *testing.T) {
+	for _, selector := range []string{
+		"team=payments,",
+		",team",
+		"team=pay ments",
+		"env in prod",
+		"env in ()",
+		"env between (a, b)",
+		"-team=payments",
+		"Example.com/team=payments",
+		"team=" + strings.Repeat("a", 64),
+	} {
+		if _, err := Parse(selector); !errors.Is(err, ErrIncorrectSelector) {
+			t.Errorf("Parse(%q) = %v, want %v", selector, err, ErrIncorrectSelector)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	labels := map[string]string{"team": "payments", "env": "prod", "tier": ""}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"team=payments", true},
+		{"team=search", false},
+		{"team=payments,env=prod", true},
+		{"team=payments,env=dev", false},
+		{"env!=dev", true},
+		{"owner!=alice", true},
+		{"env in (prod, staging)", true},
+		{"env in (dev, staging)", false},
+		{"env notin (dev)", true},
+		{"owner notin (alice)", true},
+		{"tier", true},
+		{"tier=", true},
+		{"owner", false},
+		{"!owner", true},
+		{"!team", false},
+	}
+	for _, tt := range tests {
+		selector, err := Parse(tt.selector)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.selector, err)
+		}
+		if got := selector.Matches(labels); got != tt.want {
+			t.Errorf("%q matches %v = %v, want %v", tt.selector, labels, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(map[string]string{"team": "payments", "example.com/env": "prod", "tier": ""}); err != nil {
+		t.Errorf("Validate of correct labels: %v", err)
+	}
+	for _, labels := range []map[string]string{
+		{"": "payments"},
+		{"team": "pay ments"},
+		{"team/": "payments"},
+		{"/team": "payments"},
+		{"team": "-payments"},
+		{strings.Repeat("a", 64): "payments"},
+	} {
+		if err := Validate(labels); !errors.Is(err, ErrIncorrectLabel) {
+			t.Errorf("Validate(%v) = %v, want %v", labels, err, ErrIncorrectLabel)
+		}
+	}
+}
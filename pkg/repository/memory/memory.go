@@ -0,0 +1,410 @@
+// Package memory keeps sites and states in memory,
+// it is used by tests and by the ephemeral server.
+// Everything is lost when the process stops.
+package memory
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/audit"
+	"CheckUrls/pkg/repository/sites"
+	"CheckUrls/pkg/repository/slo"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ repository.SiteRepository   = (*SiteRepository)(nil)
+	_ repository.StatusRepository = (*StatusRepository)(nil)
+)
+
+// store is shared by the repositories the same way
+// the tables are shared in the database
+type store struct {
+	mu       sync.RWMutex
+	siteSeq  int64
+	stateSeq int64
+	sites    map[int64]*sites.Site
+	states   []*statuses.State
+	rollups  map[rollupKey]*statuses.Rollup
+	eventSeq int64
+	events   []*audit.Event
+	// objectives are ordered by id
+	objectiveSeq int64
+	objectives   []*slo.Objective
+}
+
+// SiteRepository stores sites in memory
+type SiteRepository struct {
+	store *store
+}
+
+// StatusRepository stores states in memory
+type StatusRepository struct {
+	store *store
+}
+
+// NewRepositories returns the empty site and status repositories
+// which work on the same data
+func NewRepositories() (*SiteRepository, *StatusRepository) {
+	s := &store{sites: make(map[int64]*sites.Site), rollups: make(map[rollupKey]*statuses.Rollup)}
+	return &SiteRepository{store: s}, &StatusRepository{store: s}
+}
+
+func (r *SiteRepository) Create(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	r.store.create(s, event)
+	return nil
+}
+
+func (r *SiteRepository) CreateBatch(ctx context.Context, list []*sites.Site,
+	events []*audit.Event) ([]bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	created := make([]bool, len(list))
+	for i, s := range list {
+		var event *audit.Event
+		if events != nil {
+			event = events[i]
+		}
+		created[i] = r.store.create(s, event)
+	}
+	return created, nil
+}
+
+func (r *SiteRepository) Restore(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	list := r.store.sortedSites()
+	for i := len(list) - 1; i >= 0; i-- {
+		if list[i].Deleted && list[i].Url == s.Url {
+			if r.store.checked(s.Url) != nil {
+				return sites.ErrSiteExists
+			}
+			before := *list[i]
+			s.Id, s.CreatedAt, s.Version = list[i].Id, list[i].CreatedAt, list[i].Version+1
+			s.Deleted = false
+			r.store.save(s)
+			r.store.record(event, &before, s)
+			return nil
+		}
+	}
+	return sites.ErrSitesNotFound
+}
+
+func (r *SiteRepository) Read(ctx context.Context, s *sites.Site) error {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	found, ok := r.store.sites[s.Id]
+	if !ok || found.Deleted {
+		return sites.ErrSitesNotFound
+	}
+	*s = *copySite(found)
+	return nil
+}
+
+func (r *SiteRepository) ReadAll(ctx context.Context, filter sites.Filter) ([]*sites.Site, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	list := make([]*sites.Site, 0)
+	for _, found := range r.store.sites {
+		if !filter.Match(found) || (filter.After != nil && !filter.Less(filter.After, found)) {
+			continue
+		}
+		list = append(list, copySite(found))
+	}
+	sort.Slice(list, func(i, j int) bool { return filter.Less(list[i], list[j]) })
+	if filter.Limit != 0 && int64(len(list)) > filter.Limit {
+		list = list[:filter.Limit]
+	}
+	return list, nil
+}
+
+func (r *SiteRepository) Update(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	found, ok := r.store.sites[s.Id]
+	if !ok || found.Deleted {
+		return sites.ErrSitesNotFound
+	}
+	if s.Version != 0 && s.Version != found.Version {
+		return sites.ErrVersionConflict
+	}
+	if checked := r.store.checked(s.Url); checked != nil && checked.Id != s.Id {
+		return sites.ErrSiteExists
+	}
+	before := *found
+	s.Deleted, s.CreatedAt, s.Version = false, found.CreatedAt, found.Version+1
+	r.store.save(s)
+	r.store.record(event, &before, s)
+	return nil
+}
+
+func (r *SiteRepository) Delete(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	found, ok := r.store.sites[s.Id]
+	if !ok {
+		return sites.ErrSitesNotFound
+	}
+	if found.Deleted {
+		*s = *copySite(found)
+		return nil
+	}
+	before := *found
+	found.Deleted = true
+	found.Version++
+	*s = *copySite(found)
+	r.store.record(event, &before, s)
+	return nil
+}
+
+func (r *SiteRepository) ListDeleted(ctx context.Context) ([]*sites.Site, error) {
+	return r.ReadAll(ctx, sites.Filter{State: sites.StateDeleted})
+}
+
+func (r *SiteRepository) Undelete(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	found, ok := r.store.sites[s.Id]
+	if !ok || !found.Deleted {
+		return sites.ErrSitesNotFound
+	}
+	if r.store.checked(found.Url) != nil {
+		return sites.ErrSiteExists
+	}
+	before := *found
+	found.Deleted = false
+	found.Version++
+	*s = *copySite(found)
+	r.store.record(event, &before, s)
+	return nil
+}
+
+func (r *SiteRepository) Purge(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.sites[s.Id]; !ok {
+		return sites.ErrSitesNotFound
+	}
+	delete(r.store.sites, s.Id)
+	states := r.store.states[:0]
+	for _, state := range r.store.states {
+		if state.SiteId != s.Id {
+			states = append(states, state)
+		}
+	}
+	r.store.states = states
+	for key := range r.store.rollups {
+		if key.siteId == s.Id {
+			delete(r.store.rollups, key)
+		}
+	}
+	objectives := r.store.objectives[:0]
+	for _, o := range r.store.objectives {
+		if o.SiteId != s.Id {
+			objectives = append(objectives, o)
+		}
+	}
+	r.store.objectives = objectives
+	for _, e := range r.store.events {
+		if e.SiteId == s.Id {
+			e.Before, e.After = "", ""
+		}
+	}
+	r.store.eventSeq++
+	event.Id = r.store.eventSeq
+	saved := *event
+	r.store.events = append(r.store.events, &saved)
+	return nil
+}
+
+func (r *SiteRepository) ListEvents(ctx context.Context, filter audit.Filter) ([]*audit.Event, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	events := make([]*audit.Event, 0)
+	for i := len(r.store.events) - 1; i >= 0; i-- {
+		if filter.Match(r.store.events[i]) {
+			e := *r.store.events[i]
+			events = append(events, &e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Date.After(events[j].Date)
+	})
+	if int64(len(events)) > filter.Size() {
+		events = events[:filter.Size()]
+	}
+	return events, nil
+}
+
+// errRejected is returned for the states of unknown sites
+var errRejected = fmt.Errorf("%w: %w", statuses.ErrStatesRejected, sites.ErrSitesNotFound)
+
+func (r *StatusRepository) Create(ctx context.Context, status *statuses.State) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	if _, ok := r.store.sites[status.SiteId]; !ok {
+		return errRejected
+	}
+	r.store.stateSeq++
+	status.Id = r.store.stateSeq
+	s := *status
+	r.store.states = append(r.store.states, &s)
+	return nil
+}
+
+func (r *StatusRepository) CreateBatch(ctx context.Context, states []*statuses.State) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, status := range states {
+		if _, ok := r.store.sites[status.SiteId]; !ok {
+			return errRejected
+		}
+	}
+	for _, status := range states {
+		r.store.stateSeq++
+		status.Id = r.store.stateSeq
+		s := *status
+		r.store.states = append(r.store.states, &s)
+	}
+	return nil
+}
+
+func (r *StatusRepository) ReadByUrl(ctx context.Context, url string, count int64) (*statuses.History, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	history := &statuses.History{Url: url, States: make([]*statuses.State, 0)}
+	for _, state := range r.store.states {
+		site := r.store.sites[state.SiteId]
+		if site.Url != url {
+			continue
+		}
+		history.Frequency = site.Frequency
+		s := *state
+		history.States = append(history.States, &s)
+	}
+	sort.SliceStable(history.States, func(i, j int) bool {
+		return history.States[i].Date.After(history.States[j].Date)
+	})
+	if count >= 0 && int64(len(history.States)) > count {
+		history.States = history.States[:count]
+	}
+	return history, nil
+}
+
+func (r *StatusRepository) ReadBySite(ctx context.Context, filter statuses.Filter) ([]*statuses.State, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	list := make([]*statuses.State, 0)
+	for _, state := range r.store.states {
+		if filter.Match(state) {
+			s := *state
+			list = append(list, &s)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return statuses.Later(list[i], list[j]) })
+	if filter.Limit != 0 && int64(len(list)) > filter.Limit {
+		list = list[:filter.Limit]
+	}
+	return list, nil
+}
+
+// LastChecks returns the time of the last check of every
+// site which is not deleted, the time is zero if there were no checks
+func (r *StatusRepository) LastChecks(ctx context.Context) (map[int64]time.Time, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	checks := make(map[int64]time.Time)
+	for id, site := range r.store.sites {
+		if !site.Deleted {
+			checks[id] = time.Time{}
+		}
+	}
+	for _, state := range r.store.states {
+		if last, ok := checks[state.SiteId]; ok && state.Date.After(last) {
+			checks[state.SiteId] = state.Date
+		}
+	}
+	return checks, nil
+}
+
+// create saves the site or updates the checked site
+// with the same url, it reports whether the site is new
+func (s *store) create(site *sites.Site, event *audit.Event) bool {
+	logger := logging.NewLoggers("memory", "createSite")
+	var before *sites.Site
+	if found := s.checked(site.Url); found != nil {
+		logger.DebugLog().Str("when", "site found").Msg("update site")
+		before = copySite(found)
+		site.Id, site.CreatedAt, site.Version = found.Id, found.CreatedAt, found.Version+1
+	} else {
+		logger.DebugLog().Str("when", "site not found").Msg("create new site")
+		s.siteSeq++
+		site.Id, site.CreatedAt, site.Version = s.siteSeq, time.Now().UTC().Truncate(time.Microsecond), 1
+	}
+	site.Deleted = false
+	s.save(site)
+	s.record(event, before, site)
+	return before == nil
+}
+
+// save stores a copy, so callers can not change the site without the lock
+func (s *store) save(site *sites.Site) {
+	s.sites[site.Id] = copySite(site)
+}
+
+// copySite returns a copy of the site with its own labels
+func copySite(site *sites.Site) *sites.Site {
+	copied := *site
+	if site.Labels != nil {
+		copied.Labels = make(map[string]string, len(site.Labels))
+		for key, value := range site.Labels {
+			copied.Labels[key] = value
+		}
+	}
+	return &copied
+}
+
+// record saves the event of the change of the site, if it is not nil
+func (s *store) record(event *audit.Event, before, after *sites.Site) {
+	if event == nil {
+		return
+	}
+	event.Record(before, after)
+	s.eventSeq++
+	event.Id = s.eventSeq
+	saved := *event
+	s.events = append(s.events, &saved)
+}
+
+// checked returns the site with the url, which is not deleted
+func (s *store) checked(url string) *sites.Site {
+	for _, site := range s.sites {
+		if !site.Deleted && site.Url == url {
+			return site
+		}
+	}
+	return nil
+}
+
+// sortedSites returns sites in the order of creation
+func (s *store) sortedSites() []*sites.Site {
+	list := make([]*sites.Site, 0, len(s.sites))
+	for _, site := range s.sites {
+		list = append(list, site)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Id < list[j].Id })
+	return list
+}
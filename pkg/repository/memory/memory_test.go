@@ -0,0 +1,13 @@
+package memory
+
+import (
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/repotest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) (repository.SiteRepository, repository.StatusRepository) {
+		return NewRepositories()
+	})
+}
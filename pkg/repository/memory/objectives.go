@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"CheckUrls/pkg/repository/slo"
+	"context"
+	"time"
+)
+
+func (r *SiteRepository) CreateObjective(ctx context.Context, o *slo.Objective) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, saved := range r.store.objectives {
+		if saved.Name == o.Name {
+			return slo.ErrObjectiveExists
+		}
+	}
+	r.store.objectiveSeq++
+	o.Id, o.CreatedAt = r.store.objectiveSeq, time.Now().UTC().Truncate(time.Microsecond)
+	saved := *o
+	r.store.objectives = append(r.store.objectives, &saved)
+	return nil
+}
+
+func (r *SiteRepository) ListObjectives(ctx context.Context) ([]*slo.Objective, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	list := make([]*slo.Objective, 0, len(r.store.objectives))
+	for _, saved := range r.store.objectives {
+		o := *saved
+		list = append(list, &o)
+	}
+	return list, nil
+}
+
+func (r *SiteRepository) DeleteObjective(ctx context.Context, o *slo.Objective) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for i, saved := range r.store.objectives {
+		if saved.Id == o.Id {
+			*o = *saved
+			r.store.objectives = append(r.store.objectives[:i], r.store.objectives[i+1:]...)
+			return nil
+		}
+	}
+	return slo.ErrObjectiveNotFound
+}
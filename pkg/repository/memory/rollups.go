@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"sort"
+	"time"
+)
+
+// rollupKey is the primary key of the rollup
+type rollupKey struct {
+	siteId     int64
+	resolution string
+	start      int64
+}
+
+func (r *StatusRepository) OldestState(ctx context.Context) (time.Time, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var oldest time.Time
+	for _, state := range r.store.states {
+		if oldest.IsZero() || state.Date.Before(oldest) {
+			oldest = state.Date
+		}
+	}
+	return oldest, nil
+}
+
+func (r *StatusRepository) ReadStates(ctx context.Context, from, to time.Time) ([]*statuses.State, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	list := make([]*statuses.State, 0)
+	for _, state := range r.store.states {
+		if !state.Date.Before(from) && state.Date.Before(to) {
+			s := *state
+			list = append(list, &s)
+		}
+	}
+	return list, nil
+}
+
+func (r *StatusRepository) DeleteStates(ctx context.Context, before time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	kept := make([]*statuses.State, 0, len(r.store.states))
+	for _, state := range r.store.states {
+		if !state.Date.Before(before) {
+			kept = append(kept, state)
+		}
+	}
+	r.store.states = kept
+	return nil
+}
+
+func (r *StatusRepository) OldestRollup(ctx context.Context, resolution string) (time.Time, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	var oldest time.Time
+	for key, rollup := range r.store.rollups {
+		if key.resolution == resolution && (oldest.IsZero() || rollup.Start.Before(oldest)) {
+			oldest = rollup.Start
+		}
+	}
+	return oldest, nil
+}
+
+func (r *StatusRepository) ReadRollups(ctx context.Context, resolution string, from, to time.Time) ([]*statuses.Rollup, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	list := make([]*statuses.Rollup, 0)
+	for key, rollup := range r.store.rollups {
+		if key.resolution == resolution && !rollup.Start.Before(from) && rollup.Start.Before(to) {
+			saved := *rollup
+			list = append(list, &saved)
+		}
+	}
+	return list, nil
+}
+
+func (r *StatusRepository) ReadSiteRollups(ctx context.Context, siteId int64, resolution string,
+	from, to time.Time) ([]*statuses.Rollup, error) {
+	list, err := r.ReadRollups(ctx, resolution, from, to)
+	if err != nil {
+		return nil, err
+	}
+	site := list[:0]
+	for _, rollup := range list {
+		if rollup.SiteId == siteId {
+			site = append(site, rollup)
+		}
+	}
+	sort.Slice(site, func(i, j int) bool { return site[i].Start.Before(site[j].Start) })
+	return site, nil
+}
+
+func (r *StatusRepository) SaveRollups(ctx context.Context, rollups []*statuses.Rollup) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for _, rollup := range rollups {
+		if _, ok := r.store.sites[rollup.SiteId]; !ok {
+			return sites.ErrSitesNotFound
+		}
+	}
+	for _, rollup := range rollups {
+		saved := *rollup
+		saved.Start = saved.Start.UTC()
+		r.store.rollups[rollupKey{rollup.SiteId, rollup.Resolution, rollup.Start.Unix()}] = &saved
+	}
+	return nil
+}
+
+func (r *StatusRepository) DeleteRollups(ctx context.Context, resolution string, before time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	for key, rollup := range r.store.rollups {
+		if key.resolution == resolution && rollup.Start.Before(before) {
+			delete(r.store.rollups, key)
+		}
+	}
+	return nil
+}
+
+func (r *StatusRepository) CompactStates(ctx context.Context, from, to time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	kept := make([]*statuses.State, 0, len(r.store.states))
+	var compacted []*statuses.State
+	for _, state := range r.store.states {
+		if !state.Date.Before(from) && state.Date.Before(to) {
+			compacted = append(compacted, state)
+			continue
+		}
+		kept = append(kept, state)
+	}
+	r.store.states = kept
+	r.store.addRollups(statuses.Aggregate(compacted))
+	return len(compacted), nil
+}
+
+func (r *StatusRepository) CompactRollups(ctx context.Context, from, to time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+	var hourly []*statuses.Rollup
+	for key, rollup := range r.store.rollups {
+		if key.resolution == statuses.ResolutionHour && !rollup.Start.Before(from) && rollup.Start.Before(to) {
+			hourly = append(hourly, rollup)
+			delete(r.store.rollups, key)
+		}
+	}
+	r.store.addRollups(statuses.Merge(hourly))
+	return len(hourly), nil
+}
+
+// addRollups adds the rollups to the saved rollups of the same buckets
+func (s *store) addRollups(rollups []*statuses.Rollup) {
+	for _, rollup := range rollups {
+		key := rollupKey{rollup.SiteId, rollup.Resolution, rollup.Start.Unix()}
+		if saved, ok := s.rollups[key]; ok {
+			saved.Add(rollup)
+			continue
+		}
+		added := *rollup
+		added.Start = added.Start.UTC()
+		s.rollups[key] = &added
+	}
+}
+
+func (r *StatusRepository) ReadRollupsByUrl(ctx context.Context, url, resolution string, count int64) ([]*statuses.Rollup, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+	list := make([]*statuses.Rollup, 0)
+	for key, rollup := range r.store.rollups {
+		if key.resolution == resolution && r.store.sites[key.siteId].Url == url {
+			saved := *rollup
+			list = append(list, &saved)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Start.After(list[j].Start) })
+	if count >= 0 && int64(len(list)) > count {
+		list = list[:count]
+	}
+	return list, nil
+}
@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/repository/sqlrepo"
+	"errors"
+	"github.com/jackc/pgconn"
+	"strings"
+)
+
+const (
+	// uniqueViolationCode is the SQLSTATE of unique_violation
+	uniqueViolationCode = "23505"
+	// constraintClass is the SQLSTATE class of integrity constraint violations
+	constraintClass = "23"
+)
+
+// dialect of PostgreSQL
+var dialect = sqlrepo.Dialect{
+	Name:     "postgres",
+	Lock:     " FOR UPDATE",
+	Contains: "strpos(url, $%d) > 0",
+	// it keeps the parameters within the limit of the driver
+	BatchRows:           1000,
+	UniqueViolation:     uniqueViolation,
+	ConstraintViolation: constraintViolation,
+}
+
+// NewSiteRepository returns the repository of sites stored in PostgreSQL
+func NewSiteRepository(conn *db.ConnectionManager) *sqlrepo.SiteRepository {
+	return sqlrepo.NewSiteRepository(conn, dialect)
+}
+
+// NewStatusRepository returns the repository of states stored in PostgreSQL
+func NewStatusRepository(conn *db.ConnectionManager) *sqlrepo.StatusRepository {
+	return sqlrepo.NewStatusRepository(conn, dialect)
+}
+
+// uniqueViolation reports whether err is caused by a unique index
+func uniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// constraintViolation reports whether err is caused by any constraint
+func constraintViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, constraintClass)
+}
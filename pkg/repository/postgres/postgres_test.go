@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"CheckUrls/pkg/config"
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/migrations"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/repotest"
+	"context"
+	"github.com/kelseyhightower/envconfig"
+	"os"
+	"testing"
+)
+
+// TestConformance runs against the database from HOST, PORT, USER,
+// PASSWORD, NAME and SSLMODE, only if TEST_POSTGRES is set.
+// All data of the database is removed.
+func TestConformance(t *testing.T) {
+	if os.Getenv("TEST_POSTGRES") == "" {
+		t.Skip("TEST_POSTGRES is not set")
+	}
+	var cfg config.EnvCache
+	if err := envconfig.Process("", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	conn := db.NewConnectionManager()
+	if err := conn.Connect(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	migrator, err := migrations.NewMigrator(conn, migrations.DialectPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	repotest.Run(t, func(t *testing.T) (repository.SiteRepository, repository.StatusRepository) {
+		// the events and the objectives have no reference to the sites,
+		// so they are not truncated by the cascade
+		if _, err := conn.Conn.Exec("TRUNCATE status, sites, audit_events, slo_objectives " +
+			"RESTART IDENTITY CASCADE;"); err != nil {
+			t.Fatal(err)
+		}
+		return NewSiteRepository(conn), NewStatusRepository(conn)
+	})
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"CheckUrls/pkg/repository/audit"
+	"CheckUrls/pkg/repository/sites"
+	"CheckUrls/pkg/repository/slo"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"time"
+)
+
+// SiteRepository stores the monitored sites,
+// deleted sites are kept but not returned.
+// Every method stops when ctx is done.
+// The methods changing the site record the change in the event,
+// if it is not nil, and save the event in the same transaction.
+type SiteRepository interface {
+	// Create saves a new site or updates the checked site with the same url,
+	// deleted sites are never changed
+	Create(ctx context.Context, s *sites.Site, event *audit.Event) error
+	// CreateBatch creates or updates the sites like Create in one transaction and
+	// reports which sites are new, nothing is saved when it fails. The events
+	// are nil or have an event, which can be nil, for every site.
+	CreateBatch(ctx context.Context, list []*sites.Site, events []*audit.Event) ([]bool, error)
+	// Restore restores the last deleted site with the url of s and saves the
+	// settings of s in it. It returns ErrSitesNotFound if there is no deleted
+	// site and ErrSiteExists if the url is checked by another site.
+	Restore(ctx context.Context, s *sites.Site, event *audit.Event) error
+	Read(ctx context.Context, s *sites.Site) error
+	// ReadAll returns the page of the sites selected and ordered by the filter
+	ReadAll(ctx context.Context, filter sites.Filter) ([]*sites.Site, error)
+	// Update changes the site, it returns ErrSiteExists if the new url is
+	// checked by another site and ErrVersionConflict if s.Version is set
+	// and the site has another version. Every change increases the version.
+	Update(ctx context.Context, s *sites.Site, event *audit.Event) error
+	Delete(ctx context.Context, s *sites.Site, event *audit.Event) error
+	// ListDeleted returns the deleted sites
+	ListDeleted(ctx context.Context) ([]*sites.Site, error)
+	// Undelete restores the deleted site s.Id as it was deleted. It returns ErrSitesNotFound
+	// if the site is not deleted and ErrSiteExists if the url is checked by another site.
+	Undelete(ctx context.Context, s *sites.Site, event *audit.Event) error
+	// Purge removes the site, deleted or not, with all its states, rollups and the objectives
+	// of the site.
+	// The sites in the earlier events of the site are erased, the event is required.
+	Purge(ctx context.Context, s *sites.Site, event *audit.Event) error
+	// ListEvents returns the events selected by the filter from the latest one
+	ListEvents(ctx context.Context, filter audit.Filter) ([]*audit.Event, error)
+
+	// CreateObjective saves the objective, it returns
+	// ErrObjectiveExists if the name is taken
+	CreateObjective(ctx context.Context, o *slo.Objective) error
+	// ListObjectives returns the objectives ordered by id
+	ListObjectives(ctx context.Context) ([]*slo.Objective, error)
+	// DeleteObjective removes the objective o.Id and returns it in o,
+	// it returns ErrObjectiveNotFound if there is no objective
+	DeleteObjective(ctx context.Context, o *slo.Objective) error
+}
+
+// StatusRepository stores the results of checks.
+// Every method stops when ctx is done.
+type StatusRepository interface {
+	// Create saves the state, it returns ErrStatesRejected
+	// if the state breaks a constraint such as its site
+	Create(ctx context.Context, status *statuses.State) error
+	// CreateBatch saves all states or none of them, it returns
+	// ErrStatesRejected if any state breaks a constraint
+	CreateBatch(ctx context.Context, states []*statuses.State) error
+	// ReadByUrl returns count latest states of the site
+	ReadByUrl(ctx context.Context, url string, count int64) (*statuses.History, error)
+	// ReadBySite returns the page of the states of the site, deleted or not,
+	// selected by the filter from the latest one
+	ReadBySite(ctx context.Context, filter statuses.Filter) ([]*statuses.State, error)
+	// LastChecks returns the time of the last check of every site
+	LastChecks(ctx context.Context) (map[int64]time.Time, error)
+
+	// OldestState returns the date of the oldest state, zero if there are no states
+	OldestState(ctx context.Context) (time.Time, error)
+	// ReadStates returns the states of all sites checked in [from, to)
+	ReadStates(ctx context.Context, from, to time.Time) ([]*statuses.State, error)
+	// DeleteStates removes the states checked before the time
+	DeleteStates(ctx context.Context, before time.Time) error
+	// OldestRollup returns the start of the oldest rollup, zero if there are no rollups
+	OldestRollup(ctx context.Context, resolution string) (time.Time, error)
+	// ReadRollups returns the rollups of all sites started in [from, to)
+	ReadRollups(ctx context.Context, resolution string, from, to time.Time) ([]*statuses.Rollup, error)
+	// ReadSiteRollups returns the rollups of the site started in [from, to) from the oldest one
+	ReadSiteRollups(ctx context.Context, siteId int64, resolution string, from, to time.Time) ([]*statuses.Rollup, error)
+	// SaveRollups creates the rollups or replaces them
+	SaveRollups(ctx context.Context, rollups []*statuses.Rollup) error
+	// DeleteRollups removes the rollups started before the time
+	DeleteRollups(ctx context.Context, resolution string, before time.Time) error
+	// CompactStates replaces the states of all sites checked in [from, to) by
+	// hourly rollups in one transaction and returns the number of the states.
+	// The states are added to the rollups saved for their hours before,
+	// so late states of compacted hours are kept.
+	CompactStates(ctx context.Context, from, to time.Time) (int, error)
+	// CompactRollups replaces the hourly rollups started in [from, to) by daily
+	// rollups in one transaction and returns the number of the hourly rollups.
+	// They are added to the daily rollups saved for their days before.
+	CompactRollups(ctx context.Context, from, to time.Time) (int, error)
+	// ReadRollupsByUrl returns count latest rollups of the site
+	ReadRollupsByUrl(ctx context.Context, url, resolution string, count int64) ([]*statuses.Rollup, error)
+}
@@ -0,0 +1,1589 @@
+// Package repotest is the conformance suite of the repositories,
+// every storage backend must pass it to be used by the server.
+package repotest
+
+import (
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/audit"
+	"CheckUrls/pkg/repository/labels"
+	"CheckUrls/pkg/repository/sites"
+	"CheckUrls/pkg/repository/slo"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ctx is the context of all calls in the suite
+var ctx = context.Background()
+
+// Factory returns empty repositories which work on the same data
+type Factory func(t *testing.T) (repository.SiteRepository, repository.StatusRepository)
+
+// Run runs the conformance suite, each test gets new repositories
+func Run(t *testing.T, newRepos Factory) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository)
+	}{
+		{"CreateRead", testCreateRead},
+		{"ReadMissing", testReadMissing},
+		{"ReadAll", testReadAll},
+		{"Update", testUpdate},
+		{"UpdateMissing", testUpdateMissing},
+		{"SoftDelete", testSoftDelete},
+		{"DeleteMissing", testDeleteMissing},
+		{"CreateExisting", testCreateExisting},
+		{"CreateConcurrent", testCreateConcurrent},
+		{"CreateIgnoresDeleted", testCreateIgnoresDeleted},
+		{"CreateBatch", testCreateBatch},
+		{"Restore", testRestore},
+		{"RestoreMissing", testRestoreMissing},
+		{"UpdateConflict", testUpdateConflict},
+		{"UpdateVersion", testUpdateVersion},
+		{"ListDeleted", testListDeleted},
+		{"Undelete", testUndelete},
+		{"Purge", testPurge},
+		{"PurgeMissing", testPurgeMissing},
+		{"Events", testEvents},
+		{"EventsFilter", testEventsFilter},
+		{"PurgeErasesEvents", testPurgeErasesEvents},
+		{"Labels", testLabels},
+		{"LabelSelector", testLabelSelector},
+		{"ReadAllFilter", testReadAllFilter},
+		{"ReadAllPages", testReadAllPages},
+		{"StatusHistory", testStatusHistory},
+		{"StatusUnknownSite", testStatusUnknownSite},
+		{"StatusBatch", testStatusBatch},
+		{"StatesBySite", testStatesBySite},
+		{"StatesBySitePages", testStatesBySitePages},
+		{"LastChecks", testLastChecks},
+		{"StatesRange", testStatesRange},
+		{"Rollups", testRollups},
+		{"CompactStates", testCompactStates},
+		{"CompactRollups", testCompactRollups},
+		{"Objectives", testObjectives},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			siteRepo, statusRepo := newRepos(t)
+			tt.test(t, siteRepo, statusRepo)
+		})
+	}
+}
+
+func newSite(url string) *sites.Site {
+	return &sites.Site{
+		Url:             url,
+		Frequency:       60,
+		CheckMode:       sites.CheckModeCold,
+		Proxy:           "socks5://user@proxy:1080",
+		ProxySecret:     "proxy",
+		ClientCert:      "secret:cert",
+		ClientKey:       "secret:key",
+		CaBundle:        "/etc/ca.pem",
+		SkipVerify:      true,
+		AuthSecret:      "token",
+		BasicAuthUser:   "user",
+		BasicAuthSecret: "password",
+	}
+}
+
+// sameSite reports whether the sites are equal,
+// nil and empty labels are the same
+func sameSite(a, b sites.Site) bool {
+	if len(a.Labels) == 0 && len(b.Labels) == 0 {
+		a.Labels, b.Labels = nil, nil
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func create(t *testing.T, siteRepo repository.SiteRepository, url string) *sites.Site {
+	t.Helper()
+	s := newSite(url)
+	if err := siteRepo.Create(ctx, s, nil); err != nil {
+		t.Fatalf("Create(%s): %v", url, err)
+	}
+	if s.Id == 0 {
+		t.Fatalf("Create(%s) did not set id", url)
+	}
+	return s
+}
+
+func testCreateRead(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	want := create(t, siteRepo, "https://example.com")
+	got := &sites.Site{Id: want.Id}
+	if err := siteRepo.Read(ctx, got); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !sameSite(*got, *want) {
+		t.Errorf("Read = %+v, want %+v", *got, *want)
+	}
+}
+
+func testReadMissing(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	if err := siteRepo.Read(ctx, &sites.Site{Id: 100}); err != sites.ErrSitesNotFound {
+		t.Errorf("Read of missing site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+}
+
+func testReadAll(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	list, err := siteRepo.ReadAll(ctx, sites.Filter{})
+	if err != nil {
+		t.Fatalf("ReadAll of empty repository: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("ReadAll of empty repository returned %d sites", len(list))
+	}
+
+	first := create(t, siteRepo, "https://one.example.com")
+	second := create(t, siteRepo, "https://two.example.com")
+	list, err = siteRepo.ReadAll(ctx, sites.Filter{})
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	got := make(map[int64]sites.Site)
+	for _, s := range list {
+		got[s.Id] = *s
+	}
+	if len(got) != 2 || !sameSite(got[first.Id], *first) || !sameSite(got[second.Id], *second) {
+		t.Errorf("ReadAll = %+v, want sites %d and %d", list, first.Id, second.Id)
+	}
+}
+
+func testUpdate(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	want := sites.Site{Id: s.Id, Url: "https://example.org", Frequency: 30, CheckMode: sites.CheckModeWarm}
+	update := want
+	// the time of creation is kept and the version is increased
+	want.CreatedAt, want.Version = s.CreatedAt, s.Version+1
+	if err := siteRepo.Update(ctx, &update, nil); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got := &sites.Site{Id: s.Id}
+	if err := siteRepo.Read(ctx, got); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !sameSite(*got, want) {
+		t.Errorf("Read after update = %+v, want %+v", *got, want)
+	}
+}
+
+func testUpdateMissing(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	s := newSite("https://example.com")
+	s.Id = 100
+	if err := siteRepo.Update(ctx, s, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("Update of missing site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+}
+
+func testSoftDelete(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	date := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := statusRepo.Create(ctx, &statuses.State{Date: date, Status: 200, SiteId: s.Id}); err != nil {
+		t.Fatalf("Create status: %v", err)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if err := siteRepo.Read(ctx, &sites.Site{Id: s.Id}); err != sites.ErrSitesNotFound {
+		t.Errorf("Read of deleted site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+	list, err := siteRepo.ReadAll(ctx, sites.Filter{})
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("ReadAll returned deleted sites: %+v", list)
+	}
+	checks, err := statusRepo.LastChecks(ctx)
+	if err != nil {
+		t.Fatalf("LastChecks: %v", err)
+	}
+	if _, ok := checks[s.Id]; ok {
+		t.Errorf("LastChecks returned deleted site %d", s.Id)
+	}
+	// the history of the deleted site is kept
+	history, err := statusRepo.ReadByUrl(ctx, s.Url, 10)
+	if err != nil {
+		t.Fatalf("ReadByUrl: %v", err)
+	}
+	if len(history.States) != 1 {
+		t.Errorf("ReadByUrl of deleted site returned %d states, want 1", len(history.States))
+	}
+	// deleting twice is not an error
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+		t.Errorf("second Delete: %v", err)
+	}
+}
+
+func testDeleteMissing(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: 100}, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("Delete of missing site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+}
+
+func testCreateExisting(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	first := create(t, siteRepo, "https://example.com")
+	second := &sites.Site{Url: first.Url, Frequency: 5, CheckMode: sites.CheckModeWarm}
+	if err := siteRepo.Create(ctx, second, nil); err != nil {
+		t.Fatalf("second Create: %v", err)
+	}
+	if second.Id != first.Id {
+		t.Errorf("second Create of the url got id %d, want %d", second.Id, first.Id)
+	}
+	got := &sites.Site{Id: first.Id}
+	if err := siteRepo.Read(ctx, got); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !sameSite(*got, *second) {
+		t.Errorf("Read after second Create = %+v, want %+v", *got, *second)
+	}
+}
+
+func testCreateConcurrent(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	const creators = 8
+	ids := make([]int64, creators)
+	errs := make([]error, creators)
+	var wg sync.WaitGroup
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			s := newSite("https://example.com")
+			errs[i] = siteRepo.Create(ctx, s, &audit.Event{Date: time.Now(), Actor: "tester",
+				Operation: audit.OperationCreate})
+			ids[i] = s.Id
+		}(i)
+	}
+	wg.Wait()
+	for i := range ids {
+		if errs[i] != nil {
+			t.Fatalf("Create #%d: %v", i, errs[i])
+		}
+		if ids[i] != ids[0] {
+			t.Errorf("Create #%d got id %d, want %d", i, ids[i], ids[0])
+		}
+	}
+	list, err := siteRepo.ReadAll(ctx, sites.Filter{})
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(list) != 1 {
+		t.Errorf("ReadAll returned %d sites, want 1", len(list))
+	}
+
+	// one creation made the site, the others changed it
+	events, err := siteRepo.ListEvents(ctx, audit.Filter{SiteId: ids[0]})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != creators {
+		t.Fatalf("ListEvents returned %d events, want %d", len(events), creators)
+	}
+	created := 0
+	for _, e := range events {
+		if e.Before == "" {
+			created++
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d events without the site before, want 1", created)
+	}
+}
+
+func testCreateIgnoresDeleted(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	old := create(t, siteRepo, "https://example.com")
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: old.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	created := create(t, siteRepo, old.Url)
+	if created.Id == old.Id {
+		t.Errorf("Create of deleted url restored site %d", old.Id)
+	}
+	if err := siteRepo.Read(ctx, &sites.Site{Id: old.Id}); err != sites.ErrSitesNotFound {
+		t.Errorf("Read of deleted site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+}
+
+func testCreateBatch(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	existing := create(t, siteRepo, "https://example.com")
+	deleted := create(t, siteRepo, "https://example.net")
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: deleted.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	list := []*sites.Site{newSite("https://example.org"), newSite("https://example.com"),
+		newSite("https://example.net"), newSite("https://example.org")}
+	list[1].Frequency = 120
+	list[2].Labels = map[string]string{"team": "payments"}
+	list[3].Frequency = 30
+	events := []*audit.Event{eventAt(0, "alice", audit.OperationCreate), nil,
+		eventAt(0, "alice", audit.OperationCreate), eventAt(0, "alice", audit.OperationCreate)}
+	created, err := siteRepo.CreateBatch(ctx, list, events)
+	if err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	// the url of the batch is updated by its second site, the deleted site is not changed
+	if want := []bool{true, false, true, false}; !reflect.DeepEqual(created, want) {
+		t.Errorf("CreateBatch created %v, want %v", created, want)
+	}
+	if list[1].Id != existing.Id || list[3].Id != list[0].Id || list[2].Id == deleted.Id {
+		t.Errorf("CreateBatch ids = %v, want %d for the existing site", siteIds(list), existing.Id)
+	}
+	if events[0].Id == 0 || events[0].SiteId != list[0].Id || events[0].Before != "" ||
+		!strings.Contains(events[3].Before, `"frequency":60`) || !strings.Contains(events[3].After, `"frequency":30`) {
+		t.Errorf("events of CreateBatch = %+v, %+v", *events[0], *events[3])
+	}
+
+	all, err := siteRepo.ReadAll(ctx, sites.Filter{})
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("ReadAll = sites %v, want 3 sites", siteIds(all))
+	}
+	for _, want := range []*sites.Site{list[1], list[2], list[3]} {
+		got := &sites.Site{Id: want.Id}
+		if err := siteRepo.Read(ctx, got); err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		if !sameSite(*got, *want) {
+			t.Errorf("Read = %+v, want %+v", *got, *want)
+		}
+	}
+	listed, err := siteRepo.ListEvents(ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(listed) != 3 {
+		t.Errorf("ListEvents returned %d events, want 3", len(listed))
+	}
+
+	if created, err := siteRepo.CreateBatch(ctx, nil, nil); err != nil || len(created) != 0 {
+		t.Errorf("CreateBatch of no sites = %v, %v", created, err)
+	}
+}
+
+func testRestore(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	old := create(t, siteRepo, "https://example.com")
+	date := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := statusRepo.Create(ctx, &statuses.State{Date: date, Status: 200, SiteId: old.Id}); err != nil {
+		t.Fatalf("Create status: %v", err)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: old.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	restored := &sites.Site{Url: old.Url, Frequency: 5, CheckMode: sites.CheckModeWarm}
+	if err := siteRepo.Restore(ctx, restored, nil); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if restored.Id != old.Id {
+		t.Errorf("Restore got id %d, want %d", restored.Id, old.Id)
+	}
+	got := &sites.Site{Id: old.Id}
+	if err := siteRepo.Read(ctx, got); err != nil {
+		t.Fatalf("Read of restored site: %v", err)
+	}
+	if !sameSite(*got, *restored) {
+		t.Errorf("Read of restored site = %+v, want %+v", *got, *restored)
+	}
+	checks, err := statusRepo.LastChecks(ctx)
+	if err != nil {
+		t.Fatalf("LastChecks: %v", err)
+	}
+	if !checks[old.Id].Equal(date) {
+		t.Errorf("last check of restored site = %v, want %v", checks[old.Id], date)
+	}
+
+	if err := siteRepo.Restore(ctx, &sites.Site{Url: old.Url}, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("second Restore = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: old.Id}, nil); err != nil {
+		t.Fatalf("second Delete: %v", err)
+	}
+	create(t, siteRepo, old.Url)
+	if err := siteRepo.Restore(ctx, &sites.Site{Url: old.Url}, nil); err != sites.ErrSiteExists {
+		t.Errorf("Restore of checked url = %v, want %v", err, sites.ErrSiteExists)
+	}
+}
+
+func testRestoreMissing(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	create(t, siteRepo, "https://example.com")
+	if err := siteRepo.Restore(ctx, &sites.Site{Url: "https://example.org"}, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("Restore of unknown url = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+}
+
+func testUpdateConflict(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	first := create(t, siteRepo, "https://example.com")
+	second := create(t, siteRepo, "https://example.org")
+	update := *second
+	update.Url = first.Url
+	if err := siteRepo.Update(ctx, &update, nil); err != sites.ErrSiteExists {
+		t.Errorf("Update to checked url = %v, want %v", err, sites.ErrSiteExists)
+	}
+
+	// deleted sites are restored only by Restore
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: second.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := siteRepo.Update(ctx, second, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("Update of deleted site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+}
+
+func testUpdateVersion(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	if s.Version != 1 {
+		t.Fatalf("version of created site = %d, want 1", s.Version)
+	}
+	update := *s
+	update.Frequency = 30
+	if err := siteRepo.Update(ctx, &update, nil); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if update.Version != 2 {
+		t.Errorf("version after update = %d, want 2", update.Version)
+	}
+
+	// the update of the old version is rejected and changes nothing
+	stale := *s
+	stale.Frequency = 90
+	if err := siteRepo.Update(ctx, &stale, nil); err != sites.ErrVersionConflict {
+		t.Errorf("Update of old version = %v, want %v", err, sites.ErrVersionConflict)
+	}
+	got := &sites.Site{Id: s.Id}
+	if err := siteRepo.Read(ctx, got); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got.Frequency != 30 || got.Version != 2 {
+		t.Errorf("Read after conflict = %+v, want frequency 30 of version 2", *got)
+	}
+
+	// the update without the version replaces any version
+	stale.Version = 0
+	if err := siteRepo.Update(ctx, &stale, nil); err != nil {
+		t.Fatalf("Update without version: %v", err)
+	}
+	if stale.Version != 3 {
+		t.Errorf("version after update without version = %d, want 3", stale.Version)
+	}
+
+	// the repeated create of the url is a change too
+	again := newSite(s.Url)
+	if err := siteRepo.Create(ctx, again, nil); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if again.Id != s.Id || again.Version != 4 {
+		t.Errorf("Create of existing url = %+v, want site %d of version 4", *again, s.Id)
+	}
+}
+
+func testListDeleted(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	first := create(t, siteRepo, "https://example.com")
+	create(t, siteRepo, "https://example.org")
+	third := create(t, siteRepo, "https://example.net")
+	for _, s := range []*sites.Site{third, first} {
+		if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+	}
+	list, err := siteRepo.ListDeleted(ctx)
+	if err != nil {
+		t.Fatalf("ListDeleted: %v", err)
+	}
+	if len(list) != 2 || list[0].Id != first.Id || list[1].Id != third.Id {
+		t.Fatalf("ListDeleted = %+v, want sites %d and %d", list, first.Id, third.Id)
+	}
+	if !list[0].Deleted || list[0].Url != first.Url {
+		t.Errorf("ListDeleted returned %+v", *list[0])
+	}
+}
+
+func testUndelete(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	date := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := statusRepo.Create(ctx, &statuses.State{Date: date, Status: 200, SiteId: s.Id}); err != nil {
+		t.Fatalf("Create status: %v", err)
+	}
+	if err := siteRepo.Undelete(ctx, &sites.Site{Id: s.Id}, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("Undelete of checked site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	got := &sites.Site{Id: s.Id}
+	if err := siteRepo.Undelete(ctx, got, nil); err != nil {
+		t.Fatalf("Undelete: %v", err)
+	}
+	// the delete and the undelete increase the version
+	s.Version += 2
+	if !sameSite(*got, *s) {
+		t.Errorf("Undelete = %+v, want %+v", *got, *s)
+	}
+	history, err := statusRepo.ReadByUrl(ctx, s.Url, 10)
+	if err != nil {
+		t.Fatalf("ReadByUrl: %v", err)
+	}
+	if len(history.States) != 1 || history.States[0].SiteId != s.Id {
+		t.Errorf("history of undeleted site = %+v", history.States)
+	}
+
+	// the url is checked by a new site
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+		t.Fatalf("second Delete: %v", err)
+	}
+	create(t, siteRepo, s.Url)
+	if err := siteRepo.Undelete(ctx, &sites.Site{Id: s.Id}, nil); err != sites.ErrSiteExists {
+		t.Errorf("Undelete of checked url = %v, want %v", err, sites.ErrSiteExists)
+	}
+	if err := siteRepo.Undelete(ctx, &sites.Site{Id: 100}, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("Undelete of missing site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+}
+
+func testPurge(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	other := create(t, siteRepo, "https://example.org")
+	date := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, id := range []int64{s.Id, other.Id} {
+		if err := statusRepo.Create(ctx, &statuses.State{Date: date, Status: 200, SiteId: id}); err != nil {
+			t.Fatalf("Create status: %v", err)
+		}
+		if err := statusRepo.SaveRollups(ctx, []*statuses.Rollup{
+			{SiteId: id, Resolution: statuses.ResolutionHour, Start: date.Add(-time.Hour), Checks: 1, Up: 1},
+		}); err != nil {
+			t.Fatalf("SaveRollups: %v", err)
+		}
+		if err := siteRepo.CreateObjective(ctx, &slo.Objective{Name: fmt.Sprintf("site-%d", id), SiteId: id,
+			Kind: slo.KindAvailability, Target: 99, Window: time.Hour}); err != nil {
+			t.Fatalf("CreateObjective: %v", err)
+		}
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	event := &audit.Event{Date: date, Actor: "admin", Operation: audit.OperationPurge, SiteId: s.Id}
+	if err := siteRepo.Purge(ctx, &sites.Site{Id: s.Id}, event); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if event.Id == 0 {
+		t.Error("Purge did not save the event")
+	}
+	if list, _ := siteRepo.ListDeleted(ctx); len(list) != 0 {
+		t.Errorf("ListDeleted after purge = %+v", list)
+	}
+	if err := siteRepo.Undelete(ctx, &sites.Site{Id: s.Id}, nil); err != sites.ErrSitesNotFound {
+		t.Errorf("Undelete of purged site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+	states, err := statusRepo.ReadStates(ctx, date.Add(-time.Hour), date.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ReadStates: %v", err)
+	}
+	if len(states) != 1 || states[0].SiteId != other.Id {
+		t.Errorf("states after purge = %+v, want the state of site %d", states, other.Id)
+	}
+	rollups, err := statusRepo.ReadRollups(ctx, statuses.ResolutionHour, date.Add(-time.Hour), date)
+	if err != nil {
+		t.Fatalf("ReadRollups: %v", err)
+	}
+	if len(rollups) != 1 || rollups[0].SiteId != other.Id {
+		t.Errorf("rollups after purge = %+v, want the rollup of site %d", rollups, other.Id)
+	}
+	objectives, err := siteRepo.ListObjectives(ctx)
+	if err != nil {
+		t.Fatalf("ListObjectives: %v", err)
+	}
+	if len(objectives) != 1 || objectives[0].SiteId != other.Id {
+		t.Errorf("objectives after purge = %+v, want the objective of site %d", objectives, other.Id)
+	}
+
+	// the url is free for a new site
+	if created := create(t, siteRepo, s.Url); created.Id == s.Id {
+		t.Errorf("Create after purge reused id %d", s.Id)
+	}
+}
+
+func testPurgeMissing(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	event := &audit.Event{Date: time.Now(), Actor: "admin", Operation: audit.OperationPurge, SiteId: 100}
+	if err := siteRepo.Purge(ctx, &sites.Site{Id: 100}, event); err != sites.ErrSitesNotFound {
+		t.Errorf("Purge of missing site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+	if event.Id != 0 {
+		t.Errorf("Purge of missing site saved event %d", event.Id)
+	}
+}
+
+// eventAt returns the event of the operation made by the actor at minute of the day
+func eventAt(minute int, actor, operation string) *audit.Event {
+	date := time.Date(2021, 5, 1, 12, minute, 0, 0, time.UTC)
+	return &audit.Event{Date: date, Actor: actor, Address: "10.0.0.5:51234", Operation: operation}
+}
+
+func testEvents(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	s := newSite("https://example.com")
+	created := eventAt(0, "alice", audit.OperationCreate)
+	if err := siteRepo.Create(ctx, s, created); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if created.Id == 0 || created.SiteId != s.Id || created.Before != "" ||
+		!strings.Contains(created.After, `"url":"https://example.com"`) {
+		t.Errorf("event of Create = %+v", *created)
+	}
+
+	s.Frequency = 120
+	updated := eventAt(1, "bob", audit.OperationUpdate)
+	if err := siteRepo.Update(ctx, s, updated); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if !strings.Contains(updated.Before, `"frequency":60`) || !strings.Contains(updated.After, `"frequency":120`) {
+		t.Errorf("event of Update = %+v", *updated)
+	}
+
+	deleted := eventAt(2, "alice", audit.OperationDelete)
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, deleted); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if !strings.Contains(deleted.Before, `"deleted":false`) || !strings.Contains(deleted.After, `"deleted":true`) {
+		t.Errorf("event of Delete = %+v", *deleted)
+	}
+	// the second deletion changes nothing
+	again := eventAt(3, "alice", audit.OperationDelete)
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, again); err != nil {
+		t.Fatalf("second Delete: %v", err)
+	}
+	if again.Id != 0 {
+		t.Errorf("second Delete saved event %d", again.Id)
+	}
+
+	restored := eventAt(4, "bob", audit.OperationRestore)
+	if err := siteRepo.Undelete(ctx, &sites.Site{Id: s.Id}, restored); err != nil {
+		t.Fatalf("Undelete: %v", err)
+	}
+	// the failed changes are not recorded
+	if err := siteRepo.Update(ctx, &sites.Site{Id: 100, Url: "https://example.org"},
+		eventAt(5, "bob", audit.OperationUpdate)); err != sites.ErrSitesNotFound {
+		t.Fatalf("Update of missing site = %v, want %v", err, sites.ErrSitesNotFound)
+	}
+
+	events, err := siteRepo.ListEvents(ctx, audit.Filter{})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	want := []*audit.Event{restored, deleted, updated, created}
+	if len(events) != len(want) {
+		t.Fatalf("ListEvents returned %d events, want %d", len(events), len(want))
+	}
+	for i, e := range events {
+		if !e.Date.Equal(want[i].Date) {
+			t.Errorf("event #%d at %v, want %v", i, e.Date, want[i].Date)
+		}
+		e.Date = want[i].Date
+		if *e != *want[i] {
+			t.Errorf("event #%d = %+v, want %+v", i, *e, *want[i])
+		}
+	}
+}
+
+func testEventsFilter(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	first, second := newSite("https://example.com"), newSite("https://example.org")
+	if err := siteRepo.Create(ctx, first, eventAt(0, "alice", audit.OperationCreate)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := siteRepo.Create(ctx, second, eventAt(1, "bob", audit.OperationCreate)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := siteRepo.Update(ctx, first, eventAt(2, "bob", audit.OperationUpdate)); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: second.Id}, eventAt(3, "alice", audit.OperationDelete)); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		filter  audit.Filter
+		minutes []int
+	}{
+		{"all", audit.Filter{}, []int{3, 2, 1, 0}},
+		{"site", audit.Filter{SiteId: first.Id}, []int{2, 0}},
+		{"actor", audit.Filter{Actor: "bob"}, []int{2, 1}},
+		{"operation", audit.Filter{Operation: audit.OperationCreate}, []int{1, 0}},
+		{"range", audit.Filter{From: eventAt(1, "", "").Date, To: eventAt(3, "", "").Date}, []int{2, 1}},
+		{"limit", audit.Filter{Limit: 3}, []int{3, 2, 1}},
+		{"combined", audit.Filter{SiteId: second.Id, Actor: "alice"}, []int{3}},
+		{"nothing", audit.Filter{Actor: "carol"}, nil},
+	}
+	for _, tt := range tests {
+		events, err := siteRepo.ListEvents(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("ListEvents(%s): %v", tt.name, err)
+		}
+		got := make([]int, 0, len(events))
+		for _, e := range events {
+			got = append(got, e.Date.Minute())
+		}
+		if len(got) != len(tt.minutes) {
+			t.Errorf("ListEvents(%s) returned events at minutes %v, want %v", tt.name, got, tt.minutes)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.minutes[i] {
+				t.Errorf("ListEvents(%s) returned events at minutes %v, want %v", tt.name, got, tt.minutes)
+				break
+			}
+		}
+	}
+}
+
+func testPurgeErasesEvents(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	s := newSite("https://example.com")
+	if err := siteRepo.Create(ctx, s, eventAt(0, "alice", audit.OperationCreate)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	other := newSite("https://example.org")
+	if err := siteRepo.Create(ctx, other, eventAt(1, "alice", audit.OperationCreate)); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, eventAt(2, "alice", audit.OperationDelete)); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	purged := eventAt(3, "bob", audit.OperationPurge)
+	purged.SiteId = s.Id
+	if err := siteRepo.Purge(ctx, &sites.Site{Id: s.Id}, purged); err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+
+	events, err := siteRepo.ListEvents(ctx, audit.Filter{SiteId: s.Id})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 3 || events[0].Operation != audit.OperationPurge || events[0].Actor != "bob" {
+		t.Fatalf("events of purged site = %+v", events)
+	}
+	for _, e := range events {
+		if e.Before != "" || e.After != "" {
+			t.Errorf("event %d of purged site keeps the site: %+v", e.Id, *e)
+		}
+	}
+	events, err = siteRepo.ListEvents(ctx, audit.Filter{SiteId: other.Id})
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].After == "" {
+		t.Errorf("events of other site = %+v", events)
+	}
+}
+
+func testLabels(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	s := newSite("https://example.com")
+	s.Labels = map[string]string{"team": "payments", "env": "prod", "tier": ""}
+	created := eventAt(0, "alice", audit.OperationCreate)
+	if err := siteRepo.Create(ctx, s, created); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.Contains(created.After, `"labels":{"env":"prod","team":"payments","tier":""}`) {
+		t.Errorf("event of Create = %+v", *created)
+	}
+	got := &sites.Site{Id: s.Id}
+	if err := siteRepo.Read(ctx, got); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !sameSite(*got, *s) {
+		t.Errorf("Read = %+v, want %+v", *got, *s)
+	}
+
+	// the labels are replaced
+	s.Labels = map[string]string{"team": "search"}
+	if err := siteRepo.Update(ctx, s, nil); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	list, err := siteRepo.ReadAll(ctx, sites.Filter{})
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(list) != 1 || !sameSite(*list[0], *s) {
+		t.Errorf("ReadAll = %+v, want %+v", list, *s)
+	}
+
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	list, err = siteRepo.ListDeleted(ctx)
+	if err != nil {
+		t.Fatalf("ListDeleted: %v", err)
+	}
+	if len(list) != 1 || list[0].Labels["team"] != "search" || len(list[0].Labels) != 1 {
+		t.Errorf("ListDeleted = %+v", list)
+	}
+
+	// the restored site gets the new labels
+	restored := newSite(s.Url)
+	if err := siteRepo.Restore(ctx, restored, nil); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	got = &sites.Site{Id: s.Id}
+	if err := siteRepo.Read(ctx, got); err != nil {
+		t.Fatalf("Read of restored site: %v", err)
+	}
+	if len(got.Labels) != 0 {
+		t.Errorf("labels of restored site = %v, want none", got.Labels)
+	}
+}
+
+func testLabelSelector(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	siteLabels := []map[string]string{
+		{"team": "payments", "env": "prod"},
+		{"team": "payments", "env": "staging", "canary": ""},
+		{"team": "search", "env": "prod"},
+		nil,
+	}
+	ids := make([]int64, len(siteLabels))
+	for i, l := range siteLabels {
+		s := newSite(fmt.Sprintf("https://%d.example.com", i))
+		s.Labels = l
+		if err := siteRepo.Create(ctx, s, nil); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		ids[i] = s.Id
+	}
+	deleted := newSite("https://deleted.example.com")
+	deleted.Labels = map[string]string{"team": "payments"}
+	if err := siteRepo.Create(ctx, deleted, nil); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: deleted.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	tests := []struct {
+		selector string
+		want     []int
+	}{
+		{"", []int{0, 1, 2, 3}},
+		{"team=payments", []int{0, 1}},
+		{"team=payments,env=prod", []int{0}},
+		{"env!=prod", []int{1, 3}},
+		{"env in (staging, dev)", []int{1}},
+		{"team notin (payments)", []int{2, 3}},
+		{"canary", []int{1}},
+		{"canary=", []int{1}},
+		{"!team", []int{3}},
+		{"owner", nil},
+	}
+	for _, tt := range tests {
+		selector, err := labels.Parse(tt.selector)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.selector, err)
+		}
+		list, err := siteRepo.ReadAll(ctx, sites.Filter{Selector: selector})
+		if err != nil {
+			t.Fatalf("ReadAll(%q): %v", tt.selector, err)
+		}
+		got := make([]int64, 0, len(list))
+		for _, s := range list {
+			got = append(got, s.Id)
+		}
+		want := make([]int64, 0, len(tt.want))
+		for _, i := range tt.want {
+			want = append(want, ids[i])
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadAll(%q) = sites %v, want %v", tt.selector, got, want)
+		}
+	}
+}
+
+// siteIds returns the ids of the sites
+func siteIds(list []*sites.Site) []int64 {
+	ids := make([]int64, 0, len(list))
+	for _, s := range list {
+		ids = append(ids, s.Id)
+	}
+	return ids
+}
+
+func testReadAllFilter(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	var ids []int64
+	for i, url := range []string{"https://a.example.com", "https://b.example.org", "https://c.example.com/api"} {
+		s := newSite(url)
+		s.Frequency = int64(i+1) * 60
+		if err := siteRepo.Create(ctx, s, nil); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if s.CreatedAt.IsZero() {
+			t.Errorf("Create(%s) did not set the time of creation", url)
+		}
+		ids = append(ids, s.Id)
+	}
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: ids[1]}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	tests := []struct {
+		filter sites.Filter
+		want   []int64
+	}{
+		{sites.Filter{}, []int64{ids[0], ids[2]}},
+		{sites.Filter{State: sites.StateDeleted}, []int64{ids[1]}},
+		{sites.Filter{State: sites.StateAll}, ids},
+		{sites.Filter{State: sites.StateAll, UrlContains: "example.com"}, []int64{ids[0], ids[2]}},
+		{sites.Filter{UrlContains: "/api"}, []int64{ids[2]}},
+		{sites.Filter{UrlContains: "%"}, []int64{}},
+		{sites.Filter{State: sites.StateAll, MinFrequency: 120}, []int64{ids[1], ids[2]}},
+		{sites.Filter{State: sites.StateAll, MaxFrequency: 120}, []int64{ids[0], ids[1]}},
+		{sites.Filter{State: sites.StateAll, MinFrequency: 100, MaxFrequency: 150}, []int64{ids[1]}},
+	}
+	for _, tt := range tests {
+		list, err := siteRepo.ReadAll(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("ReadAll(%+v): %v", tt.filter, err)
+		}
+		if got := siteIds(list); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReadAll(%+v) = sites %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	for _, filter := range []sites.Filter{
+		{State: "unknown"},
+		{Sort: "frequency"},
+		{MinFrequency: 120, MaxFrequency: 60},
+		{Limit: -1},
+	} {
+		if _, err := siteRepo.ReadAll(ctx, filter); !errors.Is(err, sites.ErrIncorrectFilter) {
+			t.Errorf("ReadAll(%+v) = %v, want %v", filter, err, sites.ErrIncorrectFilter)
+		}
+	}
+}
+
+func testReadAllPages(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	// the urls are not in the order of creation
+	var created []int64
+	byUrl := make(map[string]int64)
+	for _, url := range []string{"https://d.example.com", "https://b.example.com", "https://e.example.com",
+		"https://a.example.com", "https://c.example.com"} {
+		s := create(t, siteRepo, url)
+		created = append(created, s.Id)
+		byUrl[url] = s.Id
+		time.Sleep(time.Millisecond)
+	}
+	sortedByUrl := []int64{byUrl["https://a.example.com"], byUrl["https://b.example.com"],
+		byUrl["https://c.example.com"], byUrl["https://d.example.com"], byUrl["https://e.example.com"]}
+	reversed := func(ids []int64) []int64 {
+		list := make([]int64, 0, len(ids))
+		for i := len(ids) - 1; i >= 0; i-- {
+			list = append(list, ids[i])
+		}
+		return list
+	}
+
+	tests := []struct {
+		sort string
+		desc bool
+		want []int64
+	}{
+		{sites.SortId, false, created},
+		{sites.SortId, true, reversed(created)},
+		{sites.SortUrl, false, sortedByUrl},
+		{sites.SortUrl, true, reversed(sortedByUrl)},
+		{sites.SortCreated, false, created},
+		{sites.SortCreated, true, reversed(created)},
+	}
+	for _, tt := range tests {
+		filter := sites.Filter{Sort: tt.sort, Desc: tt.desc, Limit: 2}
+		var got []int64
+		for pages := 0; ; pages++ {
+			if pages > len(tt.want) {
+				t.Fatalf("ReadAll by %s does not stop", tt.sort)
+			}
+			list, err := siteRepo.ReadAll(ctx, filter)
+			if err != nil {
+				t.Fatalf("ReadAll(%+v): %v", filter, err)
+			}
+			if len(list) > 2 {
+				t.Fatalf("ReadAll returned %d sites, want at most 2", len(list))
+			}
+			got = append(got, siteIds(list)...)
+			if len(list) < 2 {
+				break
+			}
+			filter.After = list[len(list)-1]
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pages by %s (desc %v) = sites %v, want %v", tt.sort, tt.desc, got, tt.want)
+		}
+	}
+}
+
+func testStatusHistory(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	other := create(t, siteRepo, "https://example.org")
+	start := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		state := &statuses.State{
+			Date:     start.Add(time.Duration(i) * time.Minute),
+			Status:   200 + int64(i),
+			SiteId:   s.Id,
+			Latency:  time.Duration(i+1) * time.Millisecond,
+			Proxy:    "http://proxy:3128",
+			Insecure: i == 1,
+		}
+		if i == 1 {
+			state.Error = "unable to read CA bundle"
+		}
+		if err := statusRepo.Create(ctx, state); err != nil {
+			t.Fatalf("Create status: %v", err)
+		}
+	}
+	if err := statusRepo.Create(ctx, &statuses.State{Date: start, Status: 500, SiteId: other.Id}); err != nil {
+		t.Fatalf("Create status: %v", err)
+	}
+
+	history, err := statusRepo.ReadByUrl(ctx, s.Url, 2)
+	if err != nil {
+		t.Fatalf("ReadByUrl: %v", err)
+	}
+	if history.Url != s.Url || history.Frequency != s.Frequency {
+		t.Errorf("ReadByUrl = %s every %d, want %s every %d", history.Url, history.Frequency, s.Url, s.Frequency)
+	}
+	if len(history.States) != 2 {
+		t.Fatalf("ReadByUrl returned %d states, want 2", len(history.States))
+	}
+	// the latest states go first
+	for i, state := range history.States {
+		n := 2 - i
+		want := statuses.State{
+			Id:       state.Id,
+			Date:     start.Add(time.Duration(n) * time.Minute),
+			Status:   200 + int64(n),
+			SiteId:   s.Id,
+			Latency:  time.Duration(n+1) * time.Millisecond,
+			Proxy:    "http://proxy:3128",
+			Insecure: n == 1,
+		}
+		if n == 1 {
+			want.Error = "unable to read CA bundle"
+		}
+		if !state.Date.Equal(want.Date) {
+			t.Errorf("state %d date = %v, want %v", i, state.Date, want.Date)
+		}
+		state.Date = want.Date
+		if *state != want {
+			t.Errorf("state %d = %+v, want %+v", i, *state, want)
+		}
+	}
+
+	empty, err := statusRepo.ReadByUrl(ctx, "https://unknown.example.com", 10)
+	if err != nil {
+		t.Fatalf("ReadByUrl of unknown url: %v", err)
+	}
+	if len(empty.States) != 0 {
+		t.Errorf("ReadByUrl of unknown url returned %d states", len(empty.States))
+	}
+}
+
+func testStatusUnknownSite(t *testing.T, _ repository.SiteRepository, statusRepo repository.StatusRepository) {
+	state := &statuses.State{Date: time.Now().UTC(), Status: 200, SiteId: 100}
+	if err := statusRepo.Create(ctx, state); !errors.Is(err, statuses.ErrStatesRejected) {
+		t.Errorf("Create status of unknown site = %v, want %v", err, statuses.ErrStatesRejected)
+	}
+	if err := statusRepo.CreateBatch(ctx, []*statuses.State{state}); !errors.Is(err, statuses.ErrStatesRejected) {
+		t.Errorf("CreateBatch of unknown site = %v, want %v", err, statuses.ErrStatesRejected)
+	}
+}
+
+func testStatusBatch(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	start := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	// more states than a single statement inserts
+	batch := make([]*statuses.State, 0, 2500)
+	for i := 0; i < cap(batch); i++ {
+		batch = append(batch, &statuses.State{
+			Date:    start.Add(time.Duration(i) * time.Second),
+			Status:  200,
+			SiteId:  s.Id,
+			Latency: time.Duration(i%100) * time.Millisecond,
+		})
+	}
+	batch[len(batch)-1].Error = "unable to read CA bundle"
+	if err := statusRepo.CreateBatch(ctx, batch); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	history, err := statusRepo.ReadByUrl(ctx, s.Url, 5000)
+	if err != nil {
+		t.Fatalf("ReadByUrl: %v", err)
+	}
+	if len(history.States) != len(batch) {
+		t.Fatalf("ReadByUrl returned %d states, want %d", len(history.States), len(batch))
+	}
+	latest := history.States[0]
+	if !latest.Date.Equal(batch[len(batch)-1].Date) || latest.Latency != 99*time.Millisecond ||
+		latest.Error != batch[len(batch)-1].Error {
+		t.Errorf("latest state = %+v, want %+v", *latest, *batch[len(batch)-1])
+	}
+
+	// a state of unknown site fails the whole batch
+	failed := []*statuses.State{
+		{Date: start.Add(-time.Hour), Status: 200, SiteId: s.Id},
+		{Date: start.Add(-time.Hour), Status: 200, SiteId: s.Id + 100},
+	}
+	if err := statusRepo.CreateBatch(ctx, failed); err == nil {
+		t.Fatal("CreateBatch with unknown site succeeded")
+	}
+	oldest, err := statusRepo.OldestState(ctx)
+	if err != nil {
+		t.Fatalf("OldestState: %v", err)
+	}
+	if !oldest.Equal(start) {
+		t.Errorf("failed batch was partly saved, oldest state = %v", oldest)
+	}
+}
+
+func testStatesBySite(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	other := create(t, siteRepo, "https://example.org")
+	start := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	// the site is down at minutes 1 and 3
+	codes := []int64{200, 503, 301, 0, 204}
+	for i, code := range codes {
+		state := &statuses.State{Date: start.Add(time.Duration(i) * time.Minute), Status: code, SiteId: s.Id,
+			Latency: time.Duration(i+1) * time.Millisecond, Proxy: "http://proxy:3128"}
+		if err := statusRepo.Create(ctx, state); err != nil {
+			t.Fatalf("Create status: %v", err)
+		}
+	}
+	if err := statusRepo.Create(ctx, &statuses.State{Date: start, Status: 500, SiteId: other.Id}); err != nil {
+		t.Fatalf("Create status: %v", err)
+	}
+	// the history of the deleted site is kept
+	if err := siteRepo.Delete(ctx, &sites.Site{Id: s.Id}, nil); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	minute := func(n int) time.Time { return start.Add(time.Duration(n) * time.Minute) }
+	tests := []struct {
+		name    string
+		filter  statuses.Filter
+		minutes []int
+	}{
+		{"all", statuses.Filter{SiteId: s.Id}, []int{4, 3, 2, 1, 0}},
+		{"range", statuses.Filter{SiteId: s.Id, From: minute(1), To: minute(3)}, []int{2, 1}},
+		{"from", statuses.Filter{SiteId: s.Id, From: minute(3)}, []int{4, 3}},
+		{"up", statuses.Filter{SiteId: s.Id, Health: statuses.HealthUp}, []int{4, 2, 0}},
+		{"down", statuses.Filter{SiteId: s.Id, Health: statuses.HealthDown, To: minute(3)}, []int{1}},
+		{"limit", statuses.Filter{SiteId: s.Id, Limit: 2}, []int{4, 3}},
+		{"other", statuses.Filter{SiteId: other.Id}, []int{0}},
+		{"nothing", statuses.Filter{SiteId: s.Id, From: minute(5)}, nil},
+	}
+	for _, tt := range tests {
+		states, err := statusRepo.ReadBySite(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("ReadBySite(%s): %v", tt.name, err)
+		}
+		got := make([]int, 0, len(states))
+		for _, state := range states {
+			got = append(got, state.Date.Minute())
+		}
+		if !reflect.DeepEqual(got, append(make([]int, 0), tt.minutes...)) {
+			t.Errorf("ReadBySite(%s) returned states at minutes %v, want %v", tt.name, got, tt.minutes)
+		}
+	}
+
+	states, err := statusRepo.ReadBySite(ctx, statuses.Filter{SiteId: s.Id, Limit: 1})
+	if err != nil {
+		t.Fatalf("ReadBySite: %v", err)
+	}
+	want := statuses.State{Id: states[0].Id, Date: minute(4), Status: 204, SiteId: s.Id,
+		Latency: 5 * time.Millisecond, Proxy: "http://proxy:3128"}
+	if len(states) != 1 || !states[0].Date.Equal(want.Date) || states[0].Date.Location() != time.UTC {
+		t.Fatalf("ReadBySite = %+v, want %+v", states, want)
+	}
+	states[0].Date = want.Date
+	if *states[0] != want {
+		t.Errorf("ReadBySite = %+v, want %+v", *states[0], want)
+	}
+
+	for _, filter := range []statuses.Filter{{}, {SiteId: s.Id, Health: "flapping"},
+		{SiteId: s.Id, From: minute(1), To: minute(1)}, {SiteId: s.Id, Limit: -1}} {
+		if _, err := statusRepo.ReadBySite(ctx, filter); !errors.Is(err, statuses.ErrIncorrectFilter) {
+			t.Errorf("ReadBySite(%+v) error = %v, want ErrIncorrectFilter", filter, err)
+		}
+	}
+}
+
+func testStatesBySitePages(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	start := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	// the states of a date are ordered by id
+	var states []*statuses.State
+	for i := 0; i < 7; i++ {
+		states = append(states, &statuses.State{Date: start.Add(time.Duration(i/2) * time.Second),
+			Status: 200, SiteId: s.Id})
+	}
+	if err := statusRepo.CreateBatch(ctx, states); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+	all, err := statusRepo.ReadBySite(ctx, statuses.Filter{SiteId: s.Id})
+	if err != nil {
+		t.Fatalf("ReadBySite: %v", err)
+	}
+	var want []int64
+	for i, state := range all {
+		if i > 0 && !statuses.Later(all[i-1], state) {
+			t.Errorf("state %d goes after state %d", all[i-1].Id, state.Id)
+		}
+		want = append(want, state.Id)
+	}
+	if len(want) != len(states) {
+		t.Fatalf("ReadBySite returned %d states, want %d", len(want), len(states))
+	}
+
+	filter := statuses.Filter{SiteId: s.Id, Limit: 3}
+	var got []int64
+	for pages := 0; ; pages++ {
+		if pages > len(states) {
+			t.Fatal("ReadBySite does not stop")
+		}
+		list, err := statusRepo.ReadBySite(ctx, filter)
+		if err != nil {
+			t.Fatalf("ReadBySite(%+v): %v", filter, err)
+		}
+		for _, state := range list {
+			got = append(got, state.Id)
+		}
+		if len(list) < 3 {
+			break
+		}
+		filter.After = list[len(list)-1]
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("pages of states = %v, want %v", got, want)
+	}
+}
+
+func testLastChecks(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	checked := create(t, siteRepo, "https://example.com")
+	unchecked := create(t, siteRepo, "https://example.org")
+	last := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, date := range []time.Time{last.Add(-time.Hour), last, last.Add(-time.Minute)} {
+		if err := statusRepo.Create(ctx, &statuses.State{Date: date, Status: 200, SiteId: checked.Id}); err != nil {
+			t.Fatalf("Create status: %v", err)
+		}
+	}
+
+	checks, err := statusRepo.LastChecks(ctx)
+	if err != nil {
+		t.Fatalf("LastChecks: %v", err)
+	}
+	if len(checks) != 2 {
+		t.Errorf("LastChecks returned %d sites, want 2", len(checks))
+	}
+	if !checks[checked.Id].Equal(last) {
+		t.Errorf("last check = %v, want %v", checks[checked.Id], last)
+	}
+	if date, ok := checks[unchecked.Id]; !ok || !date.IsZero() {
+		t.Errorf("last check of unchecked site = %v, %t, want zero time", date, ok)
+	}
+}
+
+func testStatesRange(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	oldest, err := statusRepo.OldestState(ctx)
+	if err != nil {
+		t.Fatalf("OldestState of empty repository: %v", err)
+	}
+	if !oldest.IsZero() {
+		t.Errorf("OldestState of empty repository = %v, want zero time", oldest)
+	}
+
+	s := create(t, siteRepo, "https://example.com")
+	hour := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	for _, date := range []time.Time{hour.Add(-time.Second), hour, hour.Add(30 * time.Minute), hour.Add(time.Hour)} {
+		if err := statusRepo.Create(ctx, &statuses.State{Date: date, Status: 200, SiteId: s.Id}); err != nil {
+			t.Fatalf("Create status: %v", err)
+		}
+	}
+
+	oldest, err = statusRepo.OldestState(ctx)
+	if err != nil {
+		t.Fatalf("OldestState: %v", err)
+	}
+	if !oldest.Equal(hour.Add(-time.Second)) {
+		t.Errorf("OldestState = %v, want %v", oldest, hour.Add(-time.Second))
+	}
+	// the range includes from and excludes to
+	states, err := statusRepo.ReadStates(ctx, hour, hour.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ReadStates: %v", err)
+	}
+	if len(states) != 2 {
+		t.Errorf("ReadStates returned %d states, want 2", len(states))
+	}
+	if err := statusRepo.DeleteStates(ctx, hour.Add(time.Hour)); err != nil {
+		t.Fatalf("DeleteStates: %v", err)
+	}
+	if err := statusRepo.DeleteStates(ctx, hour); err != nil {
+		t.Fatalf("DeleteStates of nothing: %v", err)
+	}
+	oldest, err = statusRepo.OldestState(ctx)
+	if err != nil {
+		t.Fatalf("OldestState: %v", err)
+	}
+	if !oldest.Equal(hour.Add(time.Hour)) {
+		t.Errorf("OldestState after delete = %v, want %v", oldest, hour.Add(time.Hour))
+	}
+}
+
+func testRollups(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	oldest, err := statusRepo.OldestRollup(ctx, statuses.ResolutionHour)
+	if err != nil {
+		t.Fatalf("OldestRollup of empty repository: %v", err)
+	}
+	if !oldest.IsZero() {
+		t.Errorf("OldestRollup of empty repository = %v, want zero time", oldest)
+	}
+
+	s := create(t, siteRepo, "https://example.com")
+	other := create(t, siteRepo, "https://example.org")
+	day := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	rollup := func(siteId int64, resolution string, start time.Time, checks int64) *statuses.Rollup {
+		return &statuses.Rollup{
+			SiteId:     siteId,
+			Resolution: resolution,
+			Start:      start,
+			Checks:     checks,
+			Up:         checks - 1,
+			LatencyP50: 10 * time.Millisecond,
+			LatencyP90: 20 * time.Millisecond,
+			LatencyP99: 30 * time.Millisecond,
+		}
+	}
+	if err := statusRepo.SaveRollups(ctx, []*statuses.Rollup{
+		rollup(s.Id, statuses.ResolutionHour, day.Add(time.Hour), 6),
+		rollup(s.Id, statuses.ResolutionHour, day.Add(2*time.Hour), 6),
+		rollup(other.Id, statuses.ResolutionHour, day.Add(time.Hour), 6),
+		rollup(s.Id, statuses.ResolutionDay, day.AddDate(0, 0, -1), 144),
+	}); err != nil {
+		t.Fatalf("SaveRollups: %v", err)
+	}
+	// saving the rollup again replaces it
+	want := rollup(s.Id, statuses.ResolutionHour, day.Add(2*time.Hour), 12)
+	if err := statusRepo.SaveRollups(ctx, []*statuses.Rollup{want}); err != nil {
+		t.Fatalf("SaveRollups: %v", err)
+	}
+
+	oldest, err = statusRepo.OldestRollup(ctx, statuses.ResolutionHour)
+	if err != nil {
+		t.Fatalf("OldestRollup: %v", err)
+	}
+	if !oldest.Equal(day.Add(time.Hour)) {
+		t.Errorf("OldestRollup = %v, want %v", oldest, day.Add(time.Hour))
+	}
+	hourly, err := statusRepo.ReadRollups(ctx, statuses.ResolutionHour, day, day.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("ReadRollups: %v", err)
+	}
+	if len(hourly) != 2 {
+		t.Errorf("ReadRollups returned %d rollups, want 2", len(hourly))
+	}
+
+	// the rollups of the site are read from the oldest one
+	bySite, err := statusRepo.ReadSiteRollups(ctx, s.Id, statuses.ResolutionHour, day, day.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("ReadSiteRollups: %v", err)
+	}
+	if len(bySite) != 2 || !bySite[0].Start.Equal(day.Add(time.Hour)) || bySite[1].Checks != 12 ||
+		bySite[0].SiteId != s.Id || bySite[1].SiteId != s.Id {
+		t.Errorf("ReadSiteRollups = %+v, want 2 hourly rollups of site %d", bySite, s.Id)
+	}
+	if daily, err := statusRepo.ReadSiteRollups(ctx, other.Id, statuses.ResolutionDay, day.AddDate(0, 0, -1),
+		day); err != nil || len(daily) != 0 {
+		t.Errorf("ReadSiteRollups of other site = %+v, %v, want none", daily, err)
+	}
+
+	byUrl, err := statusRepo.ReadRollupsByUrl(ctx, s.Url, statuses.ResolutionHour, 1)
+	if err != nil {
+		t.Fatalf("ReadRollupsByUrl: %v", err)
+	}
+	if len(byUrl) != 1 {
+		t.Fatalf("ReadRollupsByUrl returned %d rollups, want 1", len(byUrl))
+	}
+	got := byUrl[0]
+	if !got.Start.Equal(want.Start) {
+		t.Errorf("latest rollup start = %v, want %v", got.Start, want.Start)
+	}
+	got.Start = want.Start
+	if *got != *want {
+		t.Errorf("latest rollup = %+v, want %+v", *got, *want)
+	}
+
+	if err := statusRepo.DeleteRollups(ctx, statuses.ResolutionHour, day.Add(2*time.Hour)); err != nil {
+		t.Fatalf("DeleteRollups: %v", err)
+	}
+	hourly, err = statusRepo.ReadRollupsByUrl(ctx, s.Url, statuses.ResolutionHour, 10)
+	if err != nil {
+		t.Fatalf("ReadRollupsByUrl: %v", err)
+	}
+	if len(hourly) != 1 {
+		t.Errorf("got %d hourly rollups after delete, want 1", len(hourly))
+	}
+	daily, err := statusRepo.ReadRollupsByUrl(ctx, s.Url, statuses.ResolutionDay, 10)
+	if err != nil {
+		t.Fatalf("ReadRollupsByUrl: %v", err)
+	}
+	if len(daily) != 1 || daily[0].Checks != 144 {
+		t.Errorf("daily rollups = %+v, want one of 144 checks", daily)
+	}
+}
+
+func testCompactStates(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	hour := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	save := func(dates ...time.Time) {
+		for _, date := range dates {
+			state := &statuses.State{Date: date, Status: 200, SiteId: s.Id, Latency: 100 * time.Millisecond}
+			if err := statusRepo.Create(ctx, state); err != nil {
+				t.Fatalf("Create status: %v", err)
+			}
+		}
+	}
+	save(hour, hour.Add(10*time.Minute), hour.Add(time.Hour))
+	count, err := statusRepo.CompactStates(ctx, hour, hour.Add(time.Hour))
+	if err != nil || count != 2 {
+		t.Fatalf("CompactStates = %d, %v, want 2", count, err)
+	}
+
+	// the late state is added to the rollup of the compacted hour
+	late := &statuses.State{Date: hour.Add(20 * time.Minute), Status: 500, SiteId: s.Id, Latency: 400 * time.Millisecond}
+	if err := statusRepo.Create(ctx, late); err != nil {
+		t.Fatalf("Create status: %v", err)
+	}
+	count, err = statusRepo.CompactStates(ctx, hour, hour.Add(time.Hour))
+	if err != nil || count != 1 {
+		t.Fatalf("CompactStates of late state = %d, %v, want 1", count, err)
+	}
+	rollups, err := statusRepo.ReadRollups(ctx, statuses.ResolutionHour, hour, hour.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("ReadRollups: %v", err)
+	}
+	if len(rollups) != 1 {
+		t.Fatalf("ReadRollups returned %d rollups, want 1", len(rollups))
+	}
+	got := rollups[0]
+	if got.Checks != 3 || got.Up != 2 {
+		t.Errorf("rollup of %d checks, %d up, want 3 checks, 2 up", got.Checks, got.Up)
+	}
+	// 2 checks of 100ms and 1 of 400ms
+	if got.LatencyP50 != 200*time.Millisecond {
+		t.Errorf("rollup p50 = %v, want %v", got.LatencyP50, 200*time.Millisecond)
+	}
+
+	// the states after the range are kept
+	oldest, err := statusRepo.OldestState(ctx)
+	if err != nil {
+		t.Fatalf("OldestState: %v", err)
+	}
+	if !oldest.Equal(hour.Add(time.Hour)) {
+		t.Errorf("OldestState = %v, want %v", oldest, hour.Add(time.Hour))
+	}
+	if count, err := statusRepo.CompactStates(ctx, hour, hour.Add(time.Hour)); err != nil || count != 0 {
+		t.Errorf("CompactStates of nothing = %d, %v, want 0", count, err)
+	}
+}
+
+func testCompactRollups(t *testing.T, siteRepo repository.SiteRepository, statusRepo repository.StatusRepository) {
+	s := create(t, siteRepo, "https://example.com")
+	day := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	rollup := func(start time.Time, checks int64, latency time.Duration) *statuses.Rollup {
+		return &statuses.Rollup{SiteId: s.Id, Resolution: statuses.ResolutionHour, Start: start, Checks: checks,
+			Up: checks, LatencyP50: latency, LatencyP90: latency, LatencyP99: latency}
+	}
+	if err := statusRepo.SaveRollups(ctx, []*statuses.Rollup{
+		rollup(day.Add(time.Hour), 6, 100*time.Millisecond),
+		rollup(day.Add(2*time.Hour), 6, 100*time.Millisecond),
+		rollup(day.Add(24*time.Hour), 6, 100*time.Millisecond),
+	}); err != nil {
+		t.Fatalf("SaveRollups: %v", err)
+	}
+	count, err := statusRepo.CompactRollups(ctx, day, day.Add(24*time.Hour))
+	if err != nil || count != 2 {
+		t.Fatalf("CompactRollups = %d, %v, want 2", count, err)
+	}
+
+	// the late hourly rollup is added to the daily rollup
+	if err := statusRepo.SaveRollups(ctx, []*statuses.Rollup{rollup(day.Add(3*time.Hour), 12, 400*time.Millisecond)}); err != nil {
+		t.Fatalf("SaveRollups: %v", err)
+	}
+	count, err = statusRepo.CompactRollups(ctx, day, day.Add(24*time.Hour))
+	if err != nil || count != 1 {
+		t.Fatalf("CompactRollups of late rollup = %d, %v, want 1", count, err)
+	}
+	daily, err := statusRepo.ReadRollups(ctx, statuses.ResolutionDay, day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("ReadRollups: %v", err)
+	}
+	if len(daily) != 1 || daily[0].Checks != 24 || daily[0].Up != 24 {
+		t.Fatalf("daily rollups = %+v, want one of 24 checks", daily)
+	}
+	if daily[0].LatencyP50 != 250*time.Millisecond {
+		t.Errorf("daily p50 = %v, want %v", daily[0].LatencyP50, 250*time.Millisecond)
+	}
+	oldest, err := statusRepo.OldestRollup(ctx, statuses.ResolutionHour)
+	if err != nil {
+		t.Fatalf("OldestRollup: %v", err)
+	}
+	if !oldest.Equal(day.Add(24 * time.Hour)) {
+		t.Errorf("OldestRollup = %v, want %v", oldest, day.Add(24*time.Hour))
+	}
+}
+
+func testObjectives(t *testing.T, siteRepo repository.SiteRepository, _ repository.StatusRepository) {
+	availability := &slo.Objective{Name: "api", Selector: "team=payments", Kind: slo.KindAvailability,
+		Target: 99.9, Window: 30 * 24 * time.Hour}
+	latency := &slo.Objective{Name: "fast", SiteId: 7, Kind: slo.KindLatency,
+		Target: 95, Threshold: 500 * time.Millisecond, Window: 7 * 24 * time.Hour}
+	for _, o := range []*slo.Objective{availability, latency} {
+		if err := siteRepo.CreateObjective(ctx, o); err != nil {
+			t.Fatalf("CreateObjective %s: %v", o.Name, err)
+		}
+		if o.Id == 0 || o.CreatedAt.IsZero() {
+			t.Errorf("CreateObjective did not set id and time: %+v", *o)
+		}
+	}
+	if err := siteRepo.CreateObjective(ctx, &slo.Objective{Name: "api", Kind: slo.KindAvailability,
+		Target: 99, Window: time.Hour}); !errors.Is(err, slo.ErrObjectiveExists) {
+		t.Errorf("CreateObjective with the taken name: err = %v, want ErrObjectiveExists", err)
+	}
+
+	list, err := siteRepo.ListObjectives(ctx)
+	if err != nil {
+		t.Fatalf("ListObjectives: %v", err)
+	}
+	if len(list) != 2 || !reflect.DeepEqual(*list[0], *availability) || !reflect.DeepEqual(*list[1], *latency) {
+		t.Errorf("ListObjectives = %+v, want %+v and %+v", list, *availability, *latency)
+	}
+
+	deleted := &slo.Objective{Id: availability.Id}
+	if err := siteRepo.DeleteObjective(ctx, deleted); err != nil {
+		t.Fatalf("DeleteObjective: %v", err)
+	}
+	if !reflect.DeepEqual(*deleted, *availability) {
+		t.Errorf("DeleteObjective returned %+v, want %+v", *deleted, *availability)
+	}
+	if err := siteRepo.DeleteObjective(ctx, &slo.Objective{Id: availability.Id}); !errors.Is(err, slo.ErrObjectiveNotFound) {
+		t.Errorf("second DeleteObjective: err = %v, want ErrObjectiveNotFound", err)
+	}
+	if list, err = siteRepo.ListObjectives(ctx); err != nil || len(list) != 1 || list[0].Id != latency.Id {
+		t.Errorf("ListObjectives after delete = %+v, %v", list, err)
+	}
+}
@@ -1,165 +1,259 @@
 package sites
 
 import (
-	"CheckUrls/pkg/db"
-	"CheckUrls/pkg/logging"
-	"database/sql"
+	"CheckUrls/pkg/repository/labels"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 )
 
 const (
-	sqlSiteCreate = "INSERT INTO sites (url, frequency, deleted) VALUES ($1, $2, $3) RETURNING id;"
-	sqlSiteRead   = "SELECT * FROM sites WHERE id=$1 AND deleted=$2;"
-	sqlSiteUpdate = "UPDATE sites SET url=$1, frequency=$2, deleted=$4 WHERE id=$3;"
-	sqlSiteDelete = "UPDATE sites SET deleted=$2 WHERE id=$1;"
-	sqlSiteList   = "SELECT * FROM sites WHERE deleted=$1;"
-	sqlSiteFind   = "SELECT id FROM sites WHERE url=$1 AND deleted=$2;"
+	// CheckModeWarm reuses keep-alive connections between checks
+	CheckModeWarm = "warm"
+	// CheckModeCold dials a new connection and handshakes TLS on every check
+	CheckModeCold = "cold"
+	// ProxyDirect disables both global and environment proxy
+	ProxyDirect = "direct"
 )
 
-var ErrSitesNotFound = fmt.Errorf("sites not found")
+// States of the sites selected by the filter
+const (
+	// StateActive selects the checked sites
+	StateActive  = "active"
+	StateDeleted = "deleted"
+	StateAll     = "all"
+)
+
+// Orders of the sites, the sites with the same key are ordered by id
+const (
+	SortId      = "id"
+	SortUrl     = "url"
+	SortCreated = "created"
+)
+
+var (
+	ErrSitesNotFound      = fmt.Errorf("sites not found")
+	ErrSiteExists         = fmt.Errorf("site with the url is already checked")
+	ErrIncorrectUrl       = fmt.Errorf("incorrect url")
+	ErrIncorrectFrequency = fmt.Errorf("frequency must not be negative")
+	ErrIncorrectMode      = fmt.Errorf("incorrect check mode")
+	ErrIncorrectProxy     = fmt.Errorf("incorrect proxy")
+	ErrIncorrectCert      = fmt.Errorf("client certificate and key must be set together")
+	ErrIncorrectAuth      = fmt.Errorf("basic auth user and secret must be set together")
+	ErrIncorrectFilter    = fmt.Errorf("incorrect filter of sites")
+	ErrVersionConflict    = fmt.Errorf("site was changed since the version")
+)
 
 type Site struct {
 	Id        int64
 	Url       string
 	Frequency int64
 	Deleted   bool
+	CheckMode string
+	// Proxy of the site checks, "direct" disables the global proxy,
+	// it has no password, the password of its user is the ProxySecret
+	Proxy string
+	// ProxySecret is the name of the secret with the proxy password
+	ProxySecret string
+	// ClientCert, ClientKey and CaBundle are file paths
+	// or references in the form "<scheme>:<name>"
+	ClientCert string
+	ClientKey  string
+	CaBundle   string
+	SkipVerify bool
+	// AuthSecret is the name of the secret sent as Authorization header
+	AuthSecret string
+	// BasicAuthSecret is the name of the secret with basic auth password
+	BasicAuthUser   string
+	BasicAuthSecret string
+	// Labels are key/value pairs, like team=payments,
+	// the sites are selected by them
+	Labels map[string]string
+	// CreatedAt is set by the repository when the site is created
+	CreatedAt time.Time
+	// Version is increased by the repository on every change of the site
+	Version int64
 }
 
-func CreateSites(conn *db.ConnectionManager, s *Site) error {
-	logger := logging.NewLoggers("sites", "createSite")
-	logger.DebugLog().Msg("find the site")
-	row, cancel, err := conn.QueryRow(sqlSiteFind, s.Url, true)
-	if err != nil {
-		logger.ErrorLog().Err(err).Str("when", "processing the sql request to find site").
-			Msg("unable to find site")
-		return err
+// Filter selects and orders the sites, the zero filter
+// selects all checked sites ordered by id
+type Filter struct {
+	Selector labels.Selector
+	// UrlContains selects the sites with the substring in the url
+	UrlContains string
+	// State is StateActive by default
+	State string
+	// MinFrequency and MaxFrequency are inclusive, 0 - without the bound
+	MinFrequency int64
+	MaxFrequency int64
+	// Sort is SortId by default
+	Sort string
+	Desc bool
+	// After is the last site of the previous page, only
+	// its id and the key of the order are used
+	After *Site
+	// Limit is the size of the page, 0 - all sites
+	Limit int64
+}
+
+// Validate fills the default state and order and checks the filter
+func (f *Filter) Validate() error {
+	switch f.State {
+	case "":
+		f.State = StateActive
+	case StateActive, StateDeleted, StateAll:
+	default:
+		return fmt.Errorf("%w: unknown state %q", ErrIncorrectFilter, f.State)
 	}
-	defer cancel()
-
-	if err := row.Scan(&s.Id); err != nil {
-		if err == sql.ErrNoRows {
-			logger.DebugLog().Str("when", "site not found").Msg("create new site")
-			row, cancel, err = conn.QueryRow(sqlSiteCreate, s.Url, s.Frequency, false)
-			if err != nil {
-				logger.ErrorLog().Err(err).Str("when", "processing sql request create site").
-					Msg("unable to create site")
-				return err
-			}
-			defer cancel()
-			if err := row.Scan(&s.Id); err != nil {
-				logger.ErrorLog().Err(err).Str("when", "scan site_id").
-					Msg("failed to scan site_id")
-				return err
-			}
-			return nil
-		}
-		logger.ErrorLog().Err(err).Msg("unable to create site")
-		return err
+	switch f.Sort {
+	case "":
+		f.Sort = SortId
+	case SortId, SortUrl, SortCreated:
+	default:
+		return fmt.Errorf("%w: unknown order %q", ErrIncorrectFilter, f.Sort)
+	}
+	if f.MinFrequency < 0 || f.MaxFrequency < 0 ||
+		(f.MaxFrequency != 0 && f.MinFrequency > f.MaxFrequency) {
+		return fmt.Errorf("%w: incorrect range of frequency", ErrIncorrectFilter)
 	}
+	if f.Limit < 0 {
+		return fmt.Errorf("%w: negative limit", ErrIncorrectFilter)
+	}
+	return nil
+}
 
-	logger.DebugLog().Str("when", "site found").Msg("processing sql request update site")
-	if err := conn.Exec(sqlSiteUpdate, s.Url, s.Frequency, s.Id, false); err != nil {
-		if err == db.ErrNothingDone {
-			logger.ErrorLog().Err(err).Str("when", "processing sql request update site").
-				Str("when", "site not found").Msg("unable to update site")
-			return ErrSitesNotFound
-		}
-		logger.ErrorLog().Err(err).Str("when", "processing sql request update site").
-			Msg("unable to update site")
-		return err
+// Match reports whether the site is selected by the filter, the page is not checked
+func (f Filter) Match(s *Site) bool {
+	switch {
+	case f.State != StateAll && s.Deleted != (f.State == StateDeleted):
+		return false
+	case f.UrlContains != "" && !strings.Contains(s.Url, f.UrlContains):
+		return false
+	case f.MinFrequency != 0 && s.Frequency < f.MinFrequency:
+		return false
+	case f.MaxFrequency != 0 && s.Frequency > f.MaxFrequency:
+		return false
 	}
+	return f.Selector.Matches(s.Labels)
+}
 
-	return nil
+// Less reports whether the site a goes before the site b in the order of the filter
+func (f Filter) Less(a, b *Site) bool {
+	less := a.Id < b.Id
+	switch {
+	case a.Id == b.Id:
+		return false
+	case f.Sort == SortUrl && a.Url != b.Url:
+		less = a.Url < b.Url
+	case f.Sort == SortCreated && !a.CreatedAt.Equal(b.CreatedAt):
+		less = a.CreatedAt.Before(b.CreatedAt)
+	}
+	return less != f.Desc
 }
 
-func ReadSites(conn *db.ConnectionManager, s *Site) error {
-	logger := logging.NewLoggers("sites", "readSite")
+// Interval returns the frequency of checks,
+// sites without frequency are checked once a day.
+func (s *Site) Interval() time.Duration {
+	if s.Frequency <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(s.Frequency) * time.Second
+}
 
-	logger.DebugLog().Msg("processing sql request read site")
-	row, cancel, err := conn.QueryRow(sqlSiteRead, s.Id, false)
-	if err != nil {
-		logger.ErrorLog().Err(err).Str("when", "processing sql request read site").
-			Msg("unable to read site")
+// Validate fills the default check mode
+// and checks the site settings
+func (s *Site) Validate() error {
+	if err := validateUrl(s.Url); err != nil {
 		return err
 	}
-	defer cancel()
-	logger.DebugLog().Msg("scan results")
-	if err := row.Scan(&s.Id, &s.Url, &s.Frequency, &s.Deleted); err != nil {
-		logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to scan results")
+	if s.Frequency < 0 {
+		return ErrIncorrectFrequency
+	}
+	switch s.CheckMode {
+	case "":
+		s.CheckMode = CheckModeWarm
+	case CheckModeWarm, CheckModeCold:
+	default:
+		return ErrIncorrectMode
+	}
+	if (s.ClientCert == "") != (s.ClientKey == "") {
+		return ErrIncorrectCert
+	}
+	if (s.BasicAuthUser == "") != (s.BasicAuthSecret == "") {
+		return ErrIncorrectAuth
+	}
+	if err := s.validateProxy(); err != nil {
 		return err
 	}
-	return nil
+	return labels.Validate(s.Labels)
 }
 
-func ReadAllSites(conn *db.ConnectionManager) ([]*Site, error) {
-	logger := logging.NewLoggers("sites", "readAllSites")
-	logger.DebugLog().Msg("processing sql request read all sites")
-
-	rows, cancel, err := conn.Query(sqlSiteList, false)
+// validateUrl checks that the url of the site is http or https with a host
+func validateUrl(s string) error {
+	u, err := url.Parse(s)
 	if err != nil {
-		if err == db.ErrNothingDone {
-			logger.ErrorLog().Err(err).Str("when", "processing sql request read all sites").
-				Str("when", "site not found").Msg("unable to read all sites")
-			return nil, ErrSitesNotFound
-		}
-		logger.ErrorLog().Err(err).Str("when", "processing sql request read all sites").
-			Msg("unable to read all sites")
-		return nil, err
+		return fmt.Errorf("%w: %v", ErrIncorrectUrl, err)
 	}
-	defer cancel()
-	defer func() {
-		logger.DebugLog().Msg("close rows")
-		if err := rows.Close(); err != nil {
-			logger.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
-			err.Error()
-		}
-	}()
-
-	logger.DebugLog().Msg("getting list of sites")
-	list := make([]*Site, 0)
-	for rows.Next() {
-		s := new(Site)
-		logger.DebugLog().Str("when", "getting list of sites")
-		if err := rows.Scan(&s.Id, &s.Url, &s.Frequency, &s.Deleted); err != nil {
-			logger.ErrorLog().Err(err).Str("when", "scan results").
-				Str("when", "getting list of sites").Msg("unable to scan results")
-			return nil, err
-		}
-		list = append(list, s)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https", ErrIncorrectUrl)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%w: empty host", ErrIncorrectUrl)
 	}
-	return list, nil
+	return nil
 }
 
-func UpdateSites(conn *db.ConnectionManager, s *Site) error {
-	logger := logging.NewLoggers("sites", "updateSites")
-
-	logger.DebugLog().Msg("processing sql request update site")
-	if err := conn.Exec(sqlSiteUpdate, s.Url, s.Frequency, s.Id, false); err != nil {
-		if err == db.ErrNothingDone {
-			logger.ErrorLog().Err(err).Str("when", "processing sql request update site").
-				Str("when", "site not found").Msg("unable to update site")
-			return ErrSitesNotFound
+// validateProxy checks the proxy of the site, the password of the proxy
+// is not saved with the site, it is read from the secret store
+func (s *Site) validateProxy() error {
+	if s.Proxy == "" || s.Proxy == ProxyDirect {
+		if s.ProxySecret != "" {
+			return fmt.Errorf("%w: proxy secret is set without proxy", ErrIncorrectProxy)
 		}
-		logger.ErrorLog().Err(err).Str("when", "processing sql request update site").
-			Msg("unable to update site")
+		return nil
+	}
+	proxy, err := ParseProxy(s.Proxy)
+	if err != nil {
 		return err
 	}
+	if _, ok := proxy.User.Password(); ok {
+		return fmt.Errorf("%w: password must be set by proxy secret", ErrIncorrectProxy)
+	}
+	if s.ProxySecret != "" && proxy.User.Username() == "" {
+		return fmt.Errorf("%w: proxy secret is set without proxy user", ErrIncorrectProxy)
+	}
 	return nil
 }
 
-func DeleteSites(conn *db.ConnectionManager, s *Site) error {
-	logger := logging.NewLoggers("sites", "deleteSites")
+// ParseProxy parses proxy address, supported schemes
+// are http, https and socks5 with optional credentials.
+func ParseProxy(s string) (*url.URL, error) {
+	proxy, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrIncorrectProxy, proxy.Scheme)
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("%w: empty host", ErrIncorrectProxy)
+	}
+	return proxy, nil
+}
 
-	logger.DebugLog().Msg("processing sql request delete site")
-	if err := conn.Exec(sqlSiteDelete, s.Id, true); err != nil {
-		if err == db.ErrNothingDone {
-			logger.ErrorLog().Err(err).Str("when", "processing sql request delete site").
-				Str("when", "site not found").Msg("unable to delete site")
-			return ErrSitesNotFound
-		}
-		logger.ErrorLog().Err(err).Str("when", "processing sql request delete site").
-			Msg("unable to delete site")
-		return err
+// RedactedProxy returns the proxy with the password replaced by "xxxxx",
+// the proxy is returned as is when it has no password. Only the proxies
+// saved before the proxy secrets have passwords.
+func (s *Site) RedactedProxy() string {
+	proxy, err := url.Parse(s.Proxy)
+	if err != nil {
+		return s.Proxy
 	}
-	return nil
+	if _, ok := proxy.User.Password(); !ok {
+		return s.Proxy
+	}
+	return proxy.Redacted()
 }
@@ -0,0 +1,107 @@
+// Package slo describes the service level objectives of sites.
+package slo
+
+import (
+	"CheckUrls/pkg/repository/labels"
+	statuses "CheckUrls/pkg/repository/status"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Kinds of the objectives
+const (
+	// KindAvailability counts the checks when the site was up as good
+	KindAvailability = "availability"
+	// KindLatency counts the checks when the site was up
+	// and answered within the threshold as good
+	KindLatency = "latency"
+)
+
+// MaxWindow is the longest window of the objective
+const MaxWindow = 90 * 24 * time.Hour
+
+var (
+	ErrObjectiveNotFound  = fmt.Errorf("objective not found")
+	ErrObjectiveExists    = fmt.Errorf("objective with the name already exists")
+	ErrIncorrectObjective = fmt.Errorf("incorrect objective")
+)
+
+// Objective is the percent of good checks of the selected sites
+// over the rolling window, like 99.9% of checks are up over 30 days
+type Objective struct {
+	Id   int64
+	Name string
+	// SiteId selects the site, the sites are selected
+	// by Selector when it is 0, the empty selector selects all sites
+	SiteId   int64
+	Selector string
+	Kind     string
+	// Target is the percent of good checks, from 0 to 100 exclusive
+	Target float64
+	// Threshold is the latency of a good check of the latency objective
+	Threshold time.Duration
+	Window    time.Duration
+	// CreatedAt is set by the repository when the objective is created
+	CreatedAt time.Time
+}
+
+// Validate reports the first incorrect field of the objective
+func (o *Objective) Validate() error {
+	switch {
+	case o.Name == "":
+		return fmt.Errorf("%w: name is required", ErrIncorrectObjective)
+	case o.SiteId < 0:
+		return fmt.Errorf("%w: incorrect site id", ErrIncorrectObjective)
+	case o.SiteId != 0 && o.Selector != "":
+		return fmt.Errorf("%w: site id and selector are exclusive", ErrIncorrectObjective)
+	case o.Kind != KindAvailability && o.Kind != KindLatency:
+		return fmt.Errorf("%w: kind must be %s or %s", ErrIncorrectObjective, KindAvailability, KindLatency)
+	case o.Kind == KindLatency && o.Threshold <= 0:
+		return fmt.Errorf("%w: latency objective requires a threshold", ErrIncorrectObjective)
+	case o.Kind == KindAvailability && o.Threshold != 0:
+		return fmt.Errorf("%w: availability objective has no threshold", ErrIncorrectObjective)
+	case !(o.Target > 0 && o.Target < 100):
+		return fmt.Errorf("%w: target must be above 0 and below 100", ErrIncorrectObjective)
+	case o.Window < time.Hour || o.Window > MaxWindow:
+		return fmt.Errorf("%w: window must be from 1h to %v", ErrIncorrectObjective, MaxWindow)
+	}
+	if _, err := labels.Parse(o.Selector); err != nil {
+		return fmt.Errorf("%w: %v", ErrIncorrectObjective, err)
+	}
+	return nil
+}
+
+// Good reports whether the check meets the objective
+func (o *Objective) Good(s *statuses.State) bool {
+	if o.Kind == KindLatency {
+		return s.Up() && s.Latency <= o.Threshold
+	}
+	return s.Up()
+}
+
+// GoodChecks returns the number of good checks of the rollup. The rollup
+// does not keep the latency of every check, so the up checks slower than
+// the threshold of the latency objective are estimated by the percentiles
+// at their lower bound: none over p99, 1% over p90, 10% over p50, else 50%.
+func (o *Objective) GoodChecks(r *statuses.Rollup) int64 {
+	if o.Kind != KindLatency {
+		return r.Up
+	}
+	var slow float64
+	switch {
+	case o.Threshold >= r.LatencyP99:
+	case o.Threshold >= r.LatencyP90:
+		slow = 0.01
+	case o.Threshold >= r.LatencyP50:
+		slow = 0.1
+	default:
+		slow = 0.5
+	}
+	return r.Up - int64(math.Round(float64(r.Up)*slow))
+}
+
+// Budget returns the share of bad checks allowed by the target
+func (o *Objective) Budget() float64 {
+	return 1 - o.Target/100
+}
@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/repository/sqlrepo"
+	"errors"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// dialect of SQLite
+var dialect = sqlrepo.Dialect{
+	Name: "sqlite",
+	// the transactions take the write lock of the database on begin
+	Lock:     "",
+	Contains: "instr(url, $%d) > 0",
+	// the driver looks up numbered parameters by name, so long
+	// statements are slower than several short ones
+	BatchRows:           20,
+	UniqueViolation:     uniqueViolation,
+	ConstraintViolation: constraintViolation,
+}
+
+// NewSiteRepository returns the repository of sites stored in SQLite
+func NewSiteRepository(conn *db.ConnectionManager) *sqlrepo.SiteRepository {
+	return sqlrepo.NewSiteRepository(conn, dialect)
+}
+
+// NewStatusRepository returns the repository of states stored in SQLite
+func NewStatusRepository(conn *db.ConnectionManager) *sqlrepo.StatusRepository {
+	return sqlrepo.NewStatusRepository(conn, dialect)
+}
+
+// uniqueViolation reports whether err is caused by a unique index
+func uniqueViolation(err error) bool {
+	var sqliteErr *driver.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
+
+// constraintViolation reports whether err is caused by any constraint,
+// the extended result codes of constraints share the primary code
+func constraintViolation(err error) bool {
+	var sqliteErr *driver.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_CONSTRAINT
+}
@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"CheckUrls/pkg/config"
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/migrations"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/repotest"
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func connect(t *testing.T) *db.ConnectionManager {
+	conn := db.NewConnectionManager()
+	cfg := &config.EnvCache{DbDriver: db.DriverSqlite, DbPath: filepath.Join(t.TempDir(), "checkUrls.db")}
+	if err := conn.Connect(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	migrator, err := migrations.NewMigrator(conn, migrations.DialectSqlite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+func TestConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) (repository.SiteRepository, repository.StatusRepository) {
+		conn := connect(t)
+		return NewSiteRepository(conn), NewStatusRepository(conn)
+	})
+}
+
+func TestCanceled(t *testing.T) {
+	conn := connect(t)
+	siteRepo, statusRepo := NewSiteRepository(conn), NewStatusRepository(conn)
+	site := &sites.Site{Url: "https://example.com"}
+	if err := siteRepo.Create(context.Background(), site, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := siteRepo.ReadAll(ctx, sites.Filter{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ReadAll = %v, want %v", err, context.Canceled)
+	}
+	if err := siteRepo.Read(ctx, &sites.Site{Id: site.Id}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Read = %v, want %v", err, context.Canceled)
+	}
+	state := &statuses.State{Date: time.Now(), Status: 200, SiteId: site.Id}
+	if err := statusRepo.CreateBatch(ctx, []*statuses.State{state}); !errors.Is(err, context.Canceled) {
+		t.Errorf("CreateBatch = %v, want %v", err, context.Canceled)
+	}
+	if checks, err := statusRepo.LastChecks(context.Background()); err != nil || !checks[site.Id].IsZero() {
+		t.Errorf("LastChecks = %v, %v, want no checks", checks, err)
+	}
+}
@@ -0,0 +1,92 @@
+package sqlrepo
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository/audit"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	sqlAuditCreate = "INSERT INTO audit_events (date, actor, address, operation, site_id, site_before, site_after) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;"
+	sqlAuditList = "SELECT id, date, actor, address, operation, site_id, site_before, site_after FROM audit_events"
+	// sqlAuditErase erases the sites in the events of the purged site
+	sqlAuditErase = "UPDATE audit_events SET site_before='', site_after='' WHERE site_id=$1;"
+)
+
+// createEvent saves the event in the transaction of the change
+func createEvent(ctx context.Context, tx *db.Tx, e *audit.Event) error {
+	return tx.QueryRowContext(ctx, sqlAuditCreate, e.Date.UTC(), e.Actor, e.Address, e.Operation, e.SiteId,
+		e.Before, e.After).Scan(&e.Id)
+}
+
+// ListEvents returns the events selected by the filter from the latest one
+func (r *SiteRepository) ListEvents(ctx context.Context, filter audit.Filter) ([]*audit.Event, error) {
+	logger := logging.NewLoggers(r.dialect.Name, "listEvents")
+	logger.DebugLog().Msg("processing sql request list events")
+	query, args := eventsQuery(filter)
+	rows, cancel, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "processing sql request list events").
+			Msg("unable to list events")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	events := make([]*audit.Event, 0)
+	for rows.Next() {
+		e := new(audit.Event)
+		err := rows.Scan(&e.Id, &e.Date, &e.Actor, &e.Address, &e.Operation, &e.SiteId, &e.Before, &e.After)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to scan results")
+			return nil, err
+		}
+		e.Date = e.Date.UTC()
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorLog().Err(err).Str("when", "read rows").Msg("unable to list events")
+		return nil, err
+	}
+	return events, nil
+}
+
+// eventsQuery builds the query of the events selected by the filter
+func eventsQuery(f audit.Filter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if f.SiteId != 0 {
+		where("site_id=$%d", f.SiteId)
+	}
+	if f.Actor != "" {
+		where("actor=$%d", f.Actor)
+	}
+	if f.Operation != "" {
+		where("operation=$%d", f.Operation)
+	}
+	if !f.From.IsZero() {
+		where("date>=$%d", f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		where("date<$%d", f.To.UTC())
+	}
+
+	query := sqlAuditList
+	if len(conditions) != 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, f.Size())
+	return query + fmt.Sprintf(" ORDER BY date DESC, id DESC LIMIT $%d;", len(args)), args
+}
@@ -0,0 +1,65 @@
+package sqlrepo
+
+import (
+	"fmt"
+	"time"
+)
+
+// Dialect is what differs in the SQL of the databases,
+// the rest of the queries are shared by the repositories
+type Dialect struct {
+	// Name is the module of the logs
+	Name string
+	// Lock is added to the reads of the site before the change
+	// to lock it until the end of the transaction
+	Lock string
+	// Contains is the condition of the url containing the value of the placeholder
+	Contains string
+	// BatchRows is the number of states inserted by one statement
+	BatchRows int
+	// UniqueViolation reports whether err is caused by a unique index
+	UniqueViolation func(err error) bool
+	// ConstraintViolation reports whether err is caused by any constraint,
+	// such as a foreign key, so the statement fails however often it runs
+	ConstraintViolation func(err error) bool
+}
+
+// lock returns the query reading the site before the change
+func (d Dialect) lock(query string) string {
+	return fmt.Sprintf(query, d.Lock)
+}
+
+// timeFormat is the format of times written by the SQLite driver with _time_format=sqlite
+const timeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// aggregateTime scans the time returned by an aggregate such as min, which is
+// NULL without rows. PostgreSQL keeps the type of the column, while SQLite
+// loses it and returns the time as text.
+type aggregateTime struct {
+	time.Time
+}
+
+func (t *aggregateTime) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		t.Time = time.Time{}
+	case time.Time:
+		t.Time = v
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("unable to scan %T into time", src)
+	}
+	return nil
+}
+
+func (t *aggregateTime) parse(value string) error {
+	date, err := time.Parse(timeFormat, value)
+	if err != nil {
+		return err
+	}
+	t.Time = date
+	return nil
+}
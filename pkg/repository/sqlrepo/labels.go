@@ -0,0 +1,96 @@
+package sqlrepo
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/repository/labels"
+	"CheckUrls/pkg/repository/sites"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const (
+	sqlLabelsDelete = "DELETE FROM site_labels WHERE site_id=$1;"
+	sqlLabelsCreate = "INSERT INTO site_labels (site_id, key, value) VALUES ($1, $2, $3);"
+	sqlLabelsRead   = "SELECT site_id, key, value FROM site_labels WHERE site_id=$1;"
+	// sqlLabelsList reads the labels of the sites selected by the condition
+	sqlLabelsList = "SELECT site_id, key, value FROM site_labels WHERE site_id IN (SELECT id FROM sites WHERE %s);"
+	// sqlLabelExists is the condition of the label of the site, the values are added by IN
+	sqlLabelExists = "EXISTS (SELECT 1 FROM site_labels l WHERE l.site_id=sites.id AND l.key=$%d"
+)
+
+// saveLabels replaces the labels of the site
+func saveLabels(ctx context.Context, tx *db.Tx, s *sites.Site) error {
+	if _, err := tx.ExecContext(ctx, sqlLabelsDelete, s.Id); err != nil {
+		return err
+	}
+	for key, value := range s.Labels {
+		if _, err := tx.ExecContext(ctx, sqlLabelsCreate, s.Id, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSite reads the site with its labels in the transaction
+func readSite(ctx context.Context, tx *db.Tx, query string, args ...interface{}) (*sites.Site, error) {
+	s, err := scanSite(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.QueryContext(ctx, sqlLabelsRead, s.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	if err := scanLabels(rows.Rows, map[int64]*sites.Site{s.Id: s}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// scanLabels adds the labels in rows to the sites
+func scanLabels(rows *sql.Rows, list map[int64]*sites.Site) error {
+	for rows.Next() {
+		var id int64
+		var key, value string
+		if err := rows.Scan(&id, &key, &value); err != nil {
+			return err
+		}
+		s, ok := list[id]
+		if !ok {
+			continue
+		}
+		if s.Labels == nil {
+			s.Labels = make(map[string]string)
+		}
+		s.Labels[key] = value
+	}
+	return rows.Err()
+}
+
+// selectorCondition returns the condition of the sites selected
+// by the selector, the values of the condition are added to args
+func selectorCondition(selector labels.Selector, args []interface{}) (string, []interface{}) {
+	conditions := make([]string, 0, len(selector))
+	for _, r := range selector {
+		args = append(args, r.Key)
+		condition := fmt.Sprintf(sqlLabelExists, len(args))
+		if len(r.Values) != 0 {
+			placeholders := make([]string, 0, len(r.Values))
+			for _, value := range r.Values {
+				args = append(args, value)
+				placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+			}
+			condition += " AND l.value IN (" + strings.Join(placeholders, ", ") + ")"
+		}
+		condition += ")"
+		switch r.Operator {
+		case labels.DoesNotExist, labels.NotEquals, labels.NotIn:
+			condition = "NOT " + condition
+		}
+		conditions = append(conditions, condition)
+	}
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,107 @@
+package sqlrepo
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository/slo"
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	sqlObjectiveColumns = "id, name, site_id, selector, kind, target, threshold, time_window, created_at"
+	sqlObjectiveCreate  = "INSERT INTO slo_objectives (name, site_id, selector, kind, target, threshold, " +
+		"time_window, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;"
+	sqlObjectiveList   = "SELECT " + sqlObjectiveColumns + " FROM slo_objectives ORDER BY id;"
+	sqlObjectiveDelete = "DELETE FROM slo_objectives WHERE id=$1 RETURNING " + sqlObjectiveColumns + ";"
+)
+
+// CreateObjective saves the objective, the name is unique
+func (r *SiteRepository) CreateObjective(ctx context.Context, o *slo.Objective) error {
+	logger := logging.NewLoggers(r.dialect.Name, "createObjective")
+	logger.DebugLog().Msg("processing sql request create objective")
+	created := time.Now().UTC().Truncate(time.Microsecond)
+	row, cancel, err := r.conn.QueryRow(ctx, sqlObjectiveCreate, o.Name, o.SiteId, o.Selector, o.Kind,
+		o.Target, o.Threshold, o.Window, created)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "processing sql request create objective").
+			Msg("unable to create objective")
+		return err
+	}
+	defer cancel()
+	if err := row.Scan(&o.Id); err != nil {
+		if r.dialect.UniqueViolation(err) {
+			logger.WarnLog().Str("name", o.Name).Msg("objective with the name exists")
+			return slo.ErrObjectiveExists
+		}
+		logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to create objective")
+		return err
+	}
+	o.CreatedAt = created
+	return nil
+}
+
+// ListObjectives returns the objectives ordered by id
+func (r *SiteRepository) ListObjectives(ctx context.Context) ([]*slo.Objective, error) {
+	logger := logging.NewLoggers(r.dialect.Name, "listObjectives")
+	logger.DebugLog().Msg("processing sql request list objectives")
+	rows, cancel, err := r.conn.Query(ctx, sqlObjectiveList)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "processing sql request list objectives").
+			Msg("unable to list objectives")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	list := make([]*slo.Objective, 0)
+	for rows.Next() {
+		o := new(slo.Objective)
+		if err := scanObjective(rows, o); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to scan results")
+			return nil, err
+		}
+		list = append(list, o)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorLog().Err(err).Str("when", "read rows").Msg("unable to list objectives")
+		return nil, err
+	}
+	return list, nil
+}
+
+// DeleteObjective removes the objective and returns it in o
+func (r *SiteRepository) DeleteObjective(ctx context.Context, o *slo.Objective) error {
+	logger := logging.NewLoggers(r.dialect.Name, "deleteObjective")
+	logger.DebugLog().Msg("processing sql request delete objective")
+	row, cancel, err := r.conn.QueryRow(ctx, sqlObjectiveDelete, o.Id)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "processing sql request delete objective").
+			Msg("unable to delete objective")
+		return err
+	}
+	defer cancel()
+	if err := scanObjective(row, o); err != nil {
+		if err == sql.ErrNoRows {
+			logger.DebugLog().Int64("objective_id", o.Id).Msg("objective not found")
+			return slo.ErrObjectiveNotFound
+		}
+		logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to delete objective")
+		return err
+	}
+	return nil
+}
+
+// scanObjective reads the objective from the row of sqlObjectiveColumns
+func scanObjective(row interface{ Scan(...interface{}) error }, o *slo.Objective) error {
+	if err := row.Scan(&o.Id, &o.Name, &o.SiteId, &o.Selector, &o.Kind, &o.Target, &o.Threshold,
+		&o.Window, &o.CreatedAt); err != nil {
+		return err
+	}
+	o.CreatedAt = o.CreatedAt.UTC()
+	return nil
+}
@@ -0,0 +1,271 @@
+package sqlrepo
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/logging"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	sqlOldestState = "SELECT min(date) FROM status;"
+	sqlReadStates  = "SELECT id, date, status_code, site_id, latency, proxy, insecure, error FROM status " +
+		"WHERE date >= $1 AND date < $2;"
+	sqlDeleteStates = "DELETE FROM status WHERE date < $1;"
+	sqlOldestRollup = "SELECT min(bucket) FROM status_rollups WHERE resolution=$1;"
+	sqlReadRollups  = "SELECT site_id, resolution, bucket, checks, up, latency_p50, latency_p90, latency_p99 " +
+		"FROM status_rollups WHERE resolution=$1 AND bucket >= $2 AND bucket < $3;"
+	sqlReadSiteRollups = "SELECT site_id, resolution, bucket, checks, up, latency_p50, latency_p90, latency_p99 " +
+		"FROM status_rollups WHERE site_id=$1 AND resolution=$2 AND bucket >= $3 AND bucket < $4 ORDER BY bucket;"
+	sqlSaveRollup = "INSERT INTO status_rollups " +
+		"(site_id, resolution, bucket, checks, up, latency_p50, latency_p90, latency_p99) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (site_id, resolution, bucket) DO UPDATE SET " +
+		"checks=excluded.checks, up=excluded.up, latency_p50=excluded.latency_p50, " +
+		"latency_p90=excluded.latency_p90, latency_p99=excluded.latency_p99;"
+	sqlDeleteRollups = "DELETE FROM status_rollups WHERE resolution=$1 AND bucket < $2;"
+	// sqlCompactStates and sqlCompactRollups delete the rows of the range and return
+	// them in one statement, so rows inserted meanwhile are neither lost nor counted
+	sqlCompactStates = "DELETE FROM status WHERE date >= $1 AND date < $2 " +
+		"RETURNING date, status_code, site_id, latency;"
+	sqlCompactRollups = "DELETE FROM status_rollups WHERE resolution=$1 AND bucket >= $2 AND bucket < $3 " +
+		"RETURNING site_id, resolution, bucket, checks, up, latency_p50, latency_p90, latency_p99;"
+	// sqlAddRollup adds the rollup to the saved one of the bucket, the percentiles
+	// are approximated by the mean weighted by checks as by statuses.Rollup.Add
+	sqlAddRollup = "INSERT INTO status_rollups " +
+		"(site_id, resolution, bucket, checks, up, latency_p50, latency_p90, latency_p99) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) ON CONFLICT (site_id, resolution, bucket) DO UPDATE SET " +
+		"checks=status_rollups.checks+excluded.checks, up=status_rollups.up+excluded.up, " +
+		"latency_p50=" + sqlWeighted50 + ", latency_p90=" + sqlWeighted90 + ", latency_p99=" + sqlWeighted99 + ";"
+	sqlWeighted50 = "COALESCE((status_rollups.latency_p50*status_rollups.checks+excluded.latency_p50*excluded.checks)" +
+		"/NULLIF(status_rollups.checks+excluded.checks, 0), 0)"
+	sqlWeighted90 = "COALESCE((status_rollups.latency_p90*status_rollups.checks+excluded.latency_p90*excluded.checks)" +
+		"/NULLIF(status_rollups.checks+excluded.checks, 0), 0)"
+	sqlWeighted99 = "COALESCE((status_rollups.latency_p99*status_rollups.checks+excluded.latency_p99*excluded.checks)" +
+		"/NULLIF(status_rollups.checks+excluded.checks, 0), 0)"
+	sqlRollupsByUrl = "SELECT r.site_id, r.resolution, r.bucket, r.checks, r.up, " +
+		"r.latency_p50, r.latency_p90, r.latency_p99 " +
+		"FROM status_rollups r JOIN sites s ON s.id=r.site_id WHERE s.url=$1 AND r.resolution=$2 " +
+		"ORDER BY r.bucket DESC LIMIT $3;"
+)
+
+func (r *StatusRepository) OldestState(ctx context.Context) (time.Time, error) {
+	return r.oldest(ctx, "oldestState", sqlOldestState)
+}
+
+func (r *StatusRepository) ReadStates(ctx context.Context, from, to time.Time) ([]*statuses.State, error) {
+	log := logging.NewLoggers(r.dialect.Name, "readStates")
+	rows, cancel, err := r.conn.Query(ctx, sqlReadStates, from.UTC(), to.UTC())
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to get rows")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	list := make([]*statuses.State, 0)
+	for rows.Next() {
+		s := new(statuses.State)
+		var latency int64
+		if err := rows.Scan(&s.Id, &s.Date, &s.Status, &s.SiteId, &latency, &s.Proxy, &s.Insecure,
+			&s.Error); err != nil {
+			log.ErrorLog().Err(err).Str("when", "scan rows").Msg("unable to scan results")
+			return nil, err
+		}
+		s.Latency = time.Duration(latency) * time.Millisecond
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+func (r *StatusRepository) DeleteStates(ctx context.Context, before time.Time) error {
+	return r.delete(ctx, "deleteStates", sqlDeleteStates, before.UTC())
+}
+
+func (r *StatusRepository) OldestRollup(ctx context.Context, resolution string) (time.Time, error) {
+	return r.oldest(ctx, "oldestRollup", sqlOldestRollup, resolution)
+}
+
+func (r *StatusRepository) ReadRollups(ctx context.Context, resolution string, from, to time.Time) ([]*statuses.Rollup, error) {
+	return r.rollups(ctx, "readRollups", sqlReadRollups, resolution, from.UTC(), to.UTC())
+}
+
+func (r *StatusRepository) ReadSiteRollups(ctx context.Context, siteId int64, resolution string,
+	from, to time.Time) ([]*statuses.Rollup, error) {
+	return r.rollups(ctx, "readSiteRollups", sqlReadSiteRollups, siteId, resolution, from.UTC(), to.UTC())
+}
+
+func (r *StatusRepository) SaveRollups(ctx context.Context, rollups []*statuses.Rollup) error {
+	log := logging.NewLoggers(r.dialect.Name, "saveRollups")
+	log.DebugLog().Int("rollups", len(rollups)).Msg("processing the sql request")
+	err := r.conn.Tx(ctx, func(tx *db.Tx) error {
+		for _, rollup := range rollups {
+			if _, err := tx.ExecContext(ctx, sqlSaveRollup, rollup.SiteId, rollup.Resolution, rollup.Start.UTC(),
+				rollup.Checks, rollup.Up, rollup.LatencyP50.Milliseconds(), rollup.LatencyP90.Milliseconds(),
+				rollup.LatencyP99.Milliseconds()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to save rollups")
+		return err
+	}
+	return nil
+}
+
+func (r *StatusRepository) DeleteRollups(ctx context.Context, resolution string, before time.Time) error {
+	return r.delete(ctx, "deleteRollups", sqlDeleteRollups, resolution, before.UTC())
+}
+
+func (r *StatusRepository) CompactStates(ctx context.Context, from, to time.Time) (int, error) {
+	log := logging.NewLoggers(r.dialect.Name, "compactStates")
+	var count int
+	err := r.conn.Tx(ctx, func(tx *db.Tx) error {
+		rows, err := tx.QueryContext(ctx, sqlCompactStates, from.UTC(), to.UTC())
+		if err != nil {
+			return err
+		}
+		states, err := scanCompacted(rows.Rows)
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		count = len(states)
+		return addRollups(ctx, tx, statuses.Aggregate(states))
+	})
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to compact states")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *StatusRepository) CompactRollups(ctx context.Context, from, to time.Time) (int, error) {
+	log := logging.NewLoggers(r.dialect.Name, "compactRollups")
+	var count int
+	err := r.conn.Tx(ctx, func(tx *db.Tx) error {
+		rows, err := tx.QueryContext(ctx, sqlCompactRollups, statuses.ResolutionHour, from.UTC(), to.UTC())
+		if err != nil {
+			return err
+		}
+		hourly, err := scanRollups(rows.Rows)
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		count = len(hourly)
+		return addRollups(ctx, tx, statuses.Merge(hourly))
+	})
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to compact rollups")
+		return 0, err
+	}
+	return count, nil
+}
+
+// scanCompacted reads the states returned by sqlCompactStates
+func scanCompacted(rows *sql.Rows) ([]*statuses.State, error) {
+	states := make([]*statuses.State, 0)
+	for rows.Next() {
+		s := new(statuses.State)
+		var latency int64
+		if err := rows.Scan(&s.Date, &s.Status, &s.SiteId, &latency); err != nil {
+			return nil, err
+		}
+		s.Latency = time.Duration(latency) * time.Millisecond
+		states = append(states, s)
+	}
+	return states, rows.Err()
+}
+
+// addRollups adds the rollups to the saved rollups of the same buckets
+func addRollups(ctx context.Context, tx *db.Tx, rollups []*statuses.Rollup) error {
+	for _, rollup := range rollups {
+		if _, err := tx.ExecContext(ctx, sqlAddRollup, rollup.SiteId, rollup.Resolution, rollup.Start.UTC(),
+			rollup.Checks, rollup.Up, rollup.LatencyP50.Milliseconds(), rollup.LatencyP90.Milliseconds(),
+			rollup.LatencyP99.Milliseconds()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *StatusRepository) ReadRollupsByUrl(ctx context.Context, url, resolution string, count int64) ([]*statuses.Rollup, error) {
+	return r.rollups(ctx, "readRollupsByUrl", sqlRollupsByUrl, url, resolution, count)
+}
+
+func (r *StatusRepository) oldest(ctx context.Context, operation, query string, args ...interface{}) (time.Time, error) {
+	log := logging.NewLoggers(r.dialect.Name, operation)
+	row, cancel, err := r.conn.QueryRow(ctx, query, args...)
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to get row")
+		return time.Time{}, err
+	}
+	defer cancel()
+	var oldest aggregateTime
+	if err := row.Scan(&oldest); err != nil {
+		log.ErrorLog().Err(err).Str("when", "scan row").Msg("unable to scan result")
+		return time.Time{}, err
+	}
+	return oldest.Time, nil
+}
+
+func (r *StatusRepository) delete(ctx context.Context, operation, query string, args ...interface{}) error {
+	log := logging.NewLoggers(r.dialect.Name, operation)
+	if err := r.conn.Exec(ctx, query, args...); err != nil && err != db.ErrNothingDone {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to delete rows")
+		return err
+	}
+	return nil
+}
+
+func (r *StatusRepository) rollups(ctx context.Context, operation, query string, args ...interface{}) ([]*statuses.Rollup, error) {
+	log := logging.NewLoggers(r.dialect.Name, operation)
+	rows, cancel, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to get rows")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	list, err := scanRollups(rows)
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "scan rows").Msg("unable to scan results")
+		return nil, err
+	}
+	return list, nil
+}
+
+// scanRollups reads the rollups from the rows
+func scanRollups(rows *sql.Rows) ([]*statuses.Rollup, error) {
+	list := make([]*statuses.Rollup, 0)
+	for rows.Next() {
+		rollup := new(statuses.Rollup)
+		var p50, p90, p99 int64
+		if err := rows.Scan(&rollup.SiteId, &rollup.Resolution, &rollup.Start, &rollup.Checks, &rollup.Up,
+			&p50, &p90, &p99); err != nil {
+			return nil, err
+		}
+		rollup.LatencyP50 = time.Duration(p50) * time.Millisecond
+		rollup.LatencyP90 = time.Duration(p90) * time.Millisecond
+		rollup.LatencyP99 = time.Duration(p99) * time.Millisecond
+		list = append(list, rollup)
+	}
+	return list, rows.Err()
+}
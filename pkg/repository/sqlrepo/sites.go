@@ -0,0 +1,499 @@
+package sqlrepo
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/audit"
+	"CheckUrls/pkg/repository/sites"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	sqlSiteColumns = "id, url, frequency, deleted, check_mode, proxy, client_cert, client_key, ca_bundle, " +
+		"skip_verify, auth_secret, basic_user, basic_secret, proxy_secret, created_at, version"
+	// sqlSiteCreate inserts nothing if the url is checked by another site, deleted sites
+	// are not conflicting, as the unique index sites_url_active_idx covers only checked sites.
+	// It waits for the transaction inserting the same url, so concurrent creations of the url
+	// make one site.
+	sqlSiteCreate = "INSERT INTO sites (url, frequency, deleted, check_mode, proxy, client_cert, client_key, " +
+		"ca_bundle, skip_verify, auth_secret, basic_user, basic_secret, created_at, proxy_secret) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) " +
+		"ON CONFLICT (url) WHERE NOT deleted DO NOTHING RETURNING id, created_at, version;"
+	sqlSiteRestore = "UPDATE sites SET frequency=$2, deleted=$3, check_mode=$4, proxy=$5, client_cert=$6, " +
+		"client_key=$7, ca_bundle=$8, skip_verify=$9, auth_secret=$10, basic_user=$11, basic_secret=$12, " +
+		"proxy_secret=$13, version=version+1 WHERE id=$1 AND deleted;"
+	sqlSiteRead = "SELECT " + sqlSiteColumns + " FROM sites WHERE id=$1 AND deleted=$2;"
+	// sqlSiteLock, sqlSiteByUrl and sqlSiteLastDeleted read the site before the change,
+	// locking it until the end of the transaction by the lock of the dialect
+	sqlSiteLock        = "SELECT " + sqlSiteColumns + " FROM sites WHERE id=$1 AND deleted=$2%s;"
+	sqlSiteLockAny     = "SELECT " + sqlSiteColumns + " FROM sites WHERE id=$1%s;"
+	sqlSiteByUrl       = "SELECT " + sqlSiteColumns + " FROM sites WHERE url=$1 AND NOT deleted%s;"
+	sqlSiteLastDeleted = "SELECT " + sqlSiteColumns + " FROM sites " +
+		"WHERE id=(SELECT max(id) FROM sites WHERE url=$1 AND deleted)%s;"
+	sqlSiteUpdate = "UPDATE sites SET url=$1, frequency=$2, check_mode=$5, proxy=$6, " +
+		"client_cert=$7, client_key=$8, ca_bundle=$9, skip_verify=$10, auth_secret=$11, basic_user=$12, " +
+		"basic_secret=$13, proxy_secret=$14, version=version+1 WHERE id=$3 AND deleted=$4;"
+	sqlSiteDelete = "UPDATE sites SET deleted=$2, version=version+1 WHERE id=$1;"
+	// sqlSiteList reads the sites selected by the condition, which is ordered and limited
+	sqlSiteList        = "SELECT " + sqlSiteColumns + " FROM sites WHERE %s;"
+	sqlPurgeRollups    = "DELETE FROM status_rollups WHERE site_id=$1;"
+	sqlPurgeStates     = "DELETE FROM status WHERE site_id=$1;"
+	sqlPurgeObjectives = "DELETE FROM slo_objectives WHERE site_id=$1;"
+	sqlPurgeSite       = "DELETE FROM sites WHERE id=$1;"
+)
+
+var (
+	_ repository.SiteRepository   = (*SiteRepository)(nil)
+	_ repository.StatusRepository = (*StatusRepository)(nil)
+)
+
+// SiteRepository stores sites in the SQL database of the dialect
+type SiteRepository struct {
+	conn    *db.ConnectionManager
+	dialect Dialect
+}
+
+func NewSiteRepository(conn *db.ConnectionManager, dialect Dialect) *SiteRepository {
+	return &SiteRepository{conn: conn, dialect: dialect}
+}
+
+// Create saves the site or updates the checked site with the same url,
+// concurrent creations of the url make one site
+func (r *SiteRepository) Create(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	logger := logging.NewLoggers(r.dialect.Name, "createSite")
+	logger.DebugLog().Msg("processing sql request create site")
+	return r.change(ctx, logger, s, event, func(tx *db.Tx) (*sites.Site, error) {
+		return r.createSite(ctx, tx, s)
+	})
+}
+
+// CreateBatch creates or updates the sites in one transaction
+func (r *SiteRepository) CreateBatch(ctx context.Context, list []*sites.Site,
+	events []*audit.Event) ([]bool, error) {
+	logger := logging.NewLoggers(r.dialect.Name, "createSites")
+	logger.DebugLog().Int("sites", len(list)).Msg("processing sql request create sites")
+	created := make([]bool, len(list))
+	err := r.conn.Tx(ctx, func(tx *db.Tx) error {
+		for i, s := range list {
+			before, err := r.createSite(ctx, tx, s)
+			if err != nil {
+				return err
+			}
+			created[i] = before == nil
+			if events == nil || events[i] == nil {
+				continue
+			}
+			events[i].Record(before, s)
+			if err := createEvent(ctx, tx, events[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "processing sql request create sites").
+			Msg("unable to create sites")
+		return nil, err
+	}
+	return created, nil
+}
+
+// createAttempts limits the attempts to create the site, while
+// the checked site of the url is deleted by other transactions
+const createAttempts = 3
+
+// createSite saves the site or updates the checked site with the same url,
+// it returns the site before the change, nil if the site is new. The site
+// is read after the insert finds the url taken, so the site before the change
+// includes the changes of the concurrent transactions.
+func (r *SiteRepository) createSite(ctx context.Context, tx *db.Tx, s *sites.Site) (*sites.Site, error) {
+	for attempt := 1; ; attempt++ {
+		err := tx.QueryRowContext(ctx, sqlSiteCreate, s.Url, s.Frequency, false, s.CheckMode, s.Proxy, s.ClientCert,
+			s.ClientKey, s.CaBundle, s.SkipVerify, s.AuthSecret, s.BasicAuthUser, s.BasicAuthSecret,
+			time.Now().UTC().Truncate(time.Microsecond), s.ProxySecret).Scan(&s.Id, &s.CreatedAt, &s.Version)
+		if err == nil {
+			s.Deleted, s.CreatedAt = false, s.CreatedAt.UTC()
+			return nil, saveLabels(ctx, tx, s)
+		} else if err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		before, err := readSite(ctx, tx, r.dialect.lock(sqlSiteByUrl), s.Url)
+		if err == sql.ErrNoRows && attempt < createAttempts {
+			// the site is deleted since the insert
+			continue
+		} else if err == sql.ErrNoRows {
+			return nil, sites.ErrSiteExists
+		} else if err != nil {
+			return nil, err
+		}
+		// the time of the checked site is kept
+		if _, err := tx.ExecContext(ctx, sqlSiteUpdate, s.Url, s.Frequency, before.Id, false, s.CheckMode, s.Proxy,
+			s.ClientCert, s.ClientKey, s.CaBundle, s.SkipVerify, s.AuthSecret, s.BasicAuthUser,
+			s.BasicAuthSecret, s.ProxySecret); err != nil {
+			return nil, err
+		}
+		s.Id, s.Deleted, s.CreatedAt, s.Version = before.Id, false, before.CreatedAt, before.Version+1
+		return before, saveLabels(ctx, tx, s)
+	}
+}
+
+// Restore restores the last deleted site with the url of s and saves the settings of s in it
+func (r *SiteRepository) Restore(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	logger := logging.NewLoggers(r.dialect.Name, "restoreSite")
+	logger.DebugLog().Msg("processing sql request restore site")
+	return r.change(ctx, logger, s, event, func(tx *db.Tx) (*sites.Site, error) {
+		before, err := readSite(ctx, tx, r.dialect.lock(sqlSiteLastDeleted), s.Url)
+		if err != nil {
+			return nil, err
+		}
+		s.Id, s.CreatedAt, s.Version = before.Id, before.CreatedAt, before.Version+1
+		if _, err := tx.ExecContext(ctx, sqlSiteRestore, s.Id, s.Frequency, false, s.CheckMode, s.Proxy, s.ClientCert,
+			s.ClientKey, s.CaBundle, s.SkipVerify, s.AuthSecret, s.BasicAuthUser, s.BasicAuthSecret,
+			s.ProxySecret); err != nil {
+			return nil, err
+		}
+		s.Deleted = false
+		return before, saveLabels(ctx, tx, s)
+	})
+}
+
+func (r *SiteRepository) Read(ctx context.Context, s *sites.Site) error {
+	logger := logging.NewLoggers(r.dialect.Name, "readSite")
+
+	logger.DebugLog().Msg("processing sql request read site")
+	row, cancel, err := r.conn.QueryRow(ctx, sqlSiteRead, s.Id, false)
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "processing sql request read site").
+			Msg("unable to read site")
+		return err
+	}
+	defer cancel()
+	logger.DebugLog().Msg("scan results")
+	if err := row.Scan(&s.Id, &s.Url, &s.Frequency, &s.Deleted, &s.CheckMode, &s.Proxy, &s.ClientCert,
+		&s.ClientKey, &s.CaBundle, &s.SkipVerify, &s.AuthSecret, &s.BasicAuthUser, &s.BasicAuthSecret, &s.ProxySecret,
+		&s.CreatedAt, &s.Version); err != nil {
+		if err == sql.ErrNoRows {
+			logger.DebugLog().Int64("site_id", s.Id).Msg("site not found")
+			return sites.ErrSitesNotFound
+		}
+		logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to scan results")
+		return err
+	}
+	s.CreatedAt, s.Labels = s.CreatedAt.UTC(), nil
+	if err := r.readLabels(ctx, sqlLabelsRead, []*sites.Site{s}, s.Id); err != nil {
+		logger.ErrorLog().Err(err).Str("when", "read labels").Msg("unable to read labels")
+		return err
+	}
+	return nil
+}
+
+// ReadAll returns the page of the sites selected by the filter
+func (r *SiteRepository) ReadAll(ctx context.Context, filter sites.Filter) ([]*sites.Site, error) {
+	return r.list(ctx, "readAllSites", filter)
+}
+
+// ListDeleted returns the deleted sites, which can be restored or purged
+func (r *SiteRepository) ListDeleted(ctx context.Context) ([]*sites.Site, error) {
+	return r.list(ctx, "listDeletedSites", sites.Filter{State: sites.StateDeleted})
+}
+
+// Undelete restores the deleted site with its settings
+func (r *SiteRepository) Undelete(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	logger := logging.NewLoggers(r.dialect.Name, "undeleteSite")
+	logger.DebugLog().Msg("processing sql request undelete site")
+	return r.change(ctx, logger, s, event, func(tx *db.Tx) (*sites.Site, error) {
+		before, err := readSite(ctx, tx, r.dialect.lock(sqlSiteLock), s.Id, true)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, sqlSiteDelete, s.Id, false); err != nil {
+			return nil, err
+		}
+		*s = *before
+		s.Deleted, s.Version = false, before.Version+1
+		return before, nil
+	})
+}
+
+// Purge removes the site with its states, rollups and objectives,
+// the event is saved in the same transaction
+func (r *SiteRepository) Purge(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	logger := logging.NewLoggers(r.dialect.Name, "purgeSite")
+	logger.DebugLog().Msg("processing sql request purge site")
+	err := r.conn.Tx(ctx, func(tx *db.Tx) error {
+		for _, query := range []string{sqlPurgeRollups, sqlPurgeStates, sqlPurgeObjectives, sqlLabelsDelete} {
+			if _, err := tx.ExecContext(ctx, query, s.Id); err != nil {
+				return err
+			}
+		}
+		result, err := tx.ExecContext(ctx, sqlPurgeSite, s.Id)
+		if err != nil {
+			return err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sites.ErrSitesNotFound
+		}
+		if _, err := tx.ExecContext(ctx, sqlAuditErase, s.Id); err != nil {
+			return err
+		}
+		return createEvent(ctx, tx, event)
+	})
+	if err != nil {
+		if err == sites.ErrSitesNotFound {
+			logger.DebugLog().Int64("site_id", s.Id).Msg("site not found")
+			return err
+		}
+		logger.ErrorLog().Err(err).Str("when", "processing sql request purge site").
+			Msg("unable to purge site")
+		return err
+	}
+	return nil
+}
+
+func (r *SiteRepository) list(ctx context.Context, operation string, filter sites.Filter) ([]*sites.Site, error) {
+	logger := logging.NewLoggers(r.dialect.Name, operation)
+	logger.DebugLog().Msg("processing sql request read all sites")
+
+	if err := filter.Validate(); err != nil {
+		logger.WarnLog().Err(err).Str("when", "validate filter").Msg("unable to read all sites")
+		return nil, err
+	}
+	where, args := r.filterCondition(filter)
+	rows, cancel, err := r.conn.Query(ctx, fmt.Sprintf(sqlSiteList, where), args...)
+	if err != nil {
+		if err == db.ErrNothingDone {
+			logger.ErrorLog().Err(err).Str("when", "processing sql request read all sites").
+				Str("when", "site not found").Msg("unable to read all sites")
+			return nil, sites.ErrSitesNotFound
+		}
+		logger.ErrorLog().Err(err).Str("when", "processing sql request read all sites").
+			Msg("unable to read all sites")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		logger.DebugLog().Msg("close rows")
+		if err := rows.Close(); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	logger.DebugLog().Msg("getting list of sites")
+	list := make([]*sites.Site, 0)
+	for rows.Next() {
+		s := new(sites.Site)
+		logger.DebugLog().Str("when", "getting list of sites")
+		if err := rows.Scan(&s.Id, &s.Url, &s.Frequency, &s.Deleted, &s.CheckMode, &s.Proxy, &s.ClientCert,
+			&s.ClientKey, &s.CaBundle, &s.SkipVerify, &s.AuthSecret, &s.BasicAuthUser, &s.BasicAuthSecret, &s.ProxySecret,
+			&s.CreatedAt, &s.Version); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "scan results").
+				Str("when", "getting list of sites").Msg("unable to scan results")
+			return nil, err
+		}
+		s.CreatedAt = s.CreatedAt.UTC()
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorLog().Err(err).Str("when", "getting list of sites").Msg("unable to read all sites")
+		return nil, err
+	}
+	if err := r.readLabels(ctx, fmt.Sprintf(sqlLabelsList, where), list, args...); err != nil {
+		logger.ErrorLog().Err(err).Str("when", "getting labels of sites").Msg("unable to read labels")
+		return nil, err
+	}
+	return list, nil
+}
+
+// readLabels adds the labels read by the query to the sites
+func (r *SiteRepository) readLabels(ctx context.Context, query string, list []*sites.Site,
+	args ...interface{}) error {
+	if len(list) == 0 {
+		return nil
+	}
+	rows, cancel, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer rows.Close()
+	byId := make(map[int64]*sites.Site, len(list))
+	for _, s := range list {
+		byId[s.Id] = s
+	}
+	return scanLabels(rows, byId)
+}
+
+// Update changes the site, deleted sites are not changed. The site
+// is not changed if s.Version is set and the site has another version.
+func (r *SiteRepository) Update(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	logger := logging.NewLoggers(r.dialect.Name, "updateSites")
+	logger.DebugLog().Msg("processing sql request update site")
+	return r.change(ctx, logger, s, event, func(tx *db.Tx) (*sites.Site, error) {
+		before, err := readSite(ctx, tx, r.dialect.lock(sqlSiteLock), s.Id, false)
+		if err != nil {
+			return nil, err
+		}
+		if s.Version != 0 && s.Version != before.Version {
+			return nil, sites.ErrVersionConflict
+		}
+		if _, err := tx.ExecContext(ctx, sqlSiteUpdate, s.Url, s.Frequency, s.Id, false, s.CheckMode, s.Proxy, s.ClientCert,
+			s.ClientKey, s.CaBundle, s.SkipVerify, s.AuthSecret, s.BasicAuthUser, s.BasicAuthSecret,
+			s.ProxySecret); err != nil {
+			return nil, err
+		}
+		s.Deleted, s.CreatedAt, s.Version = false, before.CreatedAt, before.Version+1
+		return before, saveLabels(ctx, tx, s)
+	})
+}
+
+func (r *SiteRepository) Delete(ctx context.Context, s *sites.Site, event *audit.Event) error {
+	logger := logging.NewLoggers(r.dialect.Name, "deleteSites")
+	logger.DebugLog().Msg("processing sql request delete site")
+	return r.change(ctx, logger, s, event, func(tx *db.Tx) (*sites.Site, error) {
+		before, err := readSite(ctx, tx, r.dialect.lock(sqlSiteLockAny), s.Id)
+		if err != nil {
+			return nil, err
+		}
+		if before.Deleted {
+			*s = *before
+			return nil, errUnchanged
+		}
+		if _, err := tx.ExecContext(ctx, sqlSiteDelete, s.Id, true); err != nil {
+			return nil, err
+		}
+		*s = *before
+		s.Deleted, s.Version = true, before.Version+1
+		return before, nil
+	})
+}
+
+// errUnchanged is returned by the change of the site which is already done,
+// such as the second deletion, nothing is recorded then
+var errUnchanged = errors.New("site is not changed")
+
+// change runs fn, which changes the site s and returns the site before the change,
+// and saves the event of the change in the same transaction
+func (r *SiteRepository) change(ctx context.Context, logger *logging.Loggers, s *sites.Site, event *audit.Event,
+	fn func(tx *db.Tx) (*sites.Site, error)) error {
+	err := r.conn.Tx(ctx, func(tx *db.Tx) error {
+		before, err := fn(tx)
+		if err == errUnchanged {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if event == nil {
+			return nil
+		}
+		event.Record(before, s)
+		return createEvent(ctx, tx, event)
+	})
+	switch {
+	case err == nil:
+		return nil
+	case err == sql.ErrNoRows:
+		logger.DebugLog().Int64("site_id", s.Id).Str("url", s.Url).Msg("site not found")
+		return sites.ErrSitesNotFound
+	case r.dialect.UniqueViolation(err), err == sites.ErrSiteExists:
+		logger.WarnLog().Str("url", s.Url).Msg("site with the url is checked")
+		return sites.ErrSiteExists
+	case err == sites.ErrVersionConflict:
+		logger.WarnLog().Int64("site_id", s.Id).Int64("version", s.Version).Msg("site has another version")
+		return err
+	}
+	logger.ErrorLog().Err(err).Str("when", "processing sql request").Msg("unable to change site")
+	return err
+}
+
+// filterCondition returns the condition of the page of the sites selected by the filter,
+// which is ordered and limited, and the values of the condition
+func (r *SiteRepository) filterCondition(filter sites.Filter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			args = append(args, value)
+			placeholders = append(placeholders, len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+
+	switch filter.State {
+	case sites.StateActive:
+		conditions = append(conditions, "NOT deleted")
+	case sites.StateDeleted:
+		conditions = append(conditions, "deleted")
+	}
+	if filter.UrlContains != "" {
+		where(r.dialect.Contains, filter.UrlContains)
+	}
+	if filter.MinFrequency != 0 {
+		where("frequency>=$%d", filter.MinFrequency)
+	}
+	if filter.MaxFrequency != 0 {
+		where("frequency<=$%d", filter.MaxFrequency)
+	}
+	if len(filter.Selector) != 0 {
+		var condition string
+		condition, args = selectorCondition(filter.Selector, args)
+		conditions = append(conditions, condition)
+	}
+
+	// the page starts after the site by the key of the order and id
+	key, compare, direction := "", ">", ""
+	if filter.Desc {
+		compare, direction = "<", " DESC"
+	}
+	switch filter.Sort {
+	case sites.SortUrl:
+		key = "url"
+	case sites.SortCreated:
+		key = "created_at"
+	}
+	order := "id" + direction
+	if key != "" {
+		order = key + direction + ", " + order
+	}
+	if filter.After != nil {
+		switch filter.Sort {
+		case sites.SortUrl:
+			where("(url, id)"+compare+"($%d, $%d)", filter.After.Url, filter.After.Id)
+		case sites.SortCreated:
+			where("(created_at, id)"+compare+"($%d, $%d)", filter.After.CreatedAt.UTC(), filter.After.Id)
+		default:
+			where("id"+compare+"$%d", filter.After.Id)
+		}
+	}
+
+	condition := "true"
+	if len(conditions) != 0 {
+		condition = strings.Join(conditions, " AND ")
+	}
+	condition += " ORDER BY " + order
+	if filter.Limit != 0 {
+		condition += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	return condition, args
+}
+
+// scanSite reads the site from the row
+func scanSite(row interface{ Scan(...interface{}) error }) (*sites.Site, error) {
+	s := new(sites.Site)
+	if err := row.Scan(&s.Id, &s.Url, &s.Frequency, &s.Deleted, &s.CheckMode, &s.Proxy, &s.ClientCert,
+		&s.ClientKey, &s.CaBundle, &s.SkipVerify, &s.AuthSecret, &s.BasicAuthUser, &s.BasicAuthSecret, &s.ProxySecret,
+		&s.CreatedAt, &s.Version); err != nil {
+		return nil, err
+	}
+	s.CreatedAt = s.CreatedAt.UTC()
+	return s, nil
+}
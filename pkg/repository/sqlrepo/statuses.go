@@ -0,0 +1,234 @@
+package sqlrepo
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/logging"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	sqlCreateStatus = "INSERT INTO status (date, status_code, site_id, latency, proxy, insecure, error) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7);"
+	// sqlCreateStatuses is followed by a tuple of values for every state
+	sqlCreateStatuses = "INSERT INTO status (date, status_code, site_id, latency, proxy, insecure, error) VALUES "
+	sqlGetStatus      = "SELECT st.id, st.date, st.status_code, s.id AS site_id, st.latency, st.proxy, st.insecure, " +
+		"st.error, s.url, s.frequency " +
+		"FROM status st JOIN sites s ON s.id=st.site_id WHERE s.url=$1 ORDER BY st.date DESC LIMIT $2;"
+	sqlStatesBySite = "SELECT id, date, status_code, site_id, latency, proxy, insecure, error FROM status"
+	sqlLastChecks   = "SELECT s.id, st.date FROM sites s LEFT JOIN " +
+		"(SELECT max(date) AS date, site_id FROM status GROUP BY site_id) st on s.id = st.site_id " +
+		"WHERE s.deleted=$1;"
+)
+
+// StatusRepository stores states in the SQL database of the dialect
+type StatusRepository struct {
+	conn    *db.ConnectionManager
+	dialect Dialect
+}
+
+func NewStatusRepository(conn *db.ConnectionManager, dialect Dialect) *StatusRepository {
+	return &StatusRepository{conn: conn, dialect: dialect}
+}
+
+func (r *StatusRepository) Create(ctx context.Context, status *statuses.State) error {
+	log := logging.NewLoggers(r.dialect.Name, "createStatus")
+	log.DebugLog().Msg("processing the sql request")
+	err := r.conn.Exec(ctx, sqlCreateStatus, status.Date.UTC(), status.Status, status.SiteId,
+		status.Latency.Milliseconds(), status.Proxy, status.Insecure, status.Error)
+	if err != nil {
+		if err == db.ErrNothingDone {
+			err = statuses.ErrStatusNotFound
+		} else if r.dialect.ConstraintViolation(err) {
+			err = fmt.Errorf("%w: %v", statuses.ErrStatesRejected, err)
+		}
+		log.ErrorLog().Str("when", "processing the sql request").
+			Err(err).Msg("unable to get row")
+		return err
+	}
+
+	return nil
+}
+
+// CreateBatch inserts states by multi-row statements in one transaction
+func (r *StatusRepository) CreateBatch(ctx context.Context, states []*statuses.State) error {
+	log := logging.NewLoggers(r.dialect.Name, "createStatuses")
+	log.DebugLog().Int("states", len(states)).Msg("processing the sql request")
+	err := r.conn.Tx(ctx, func(tx *db.Tx) error {
+		for start := 0; start < len(states); start += r.dialect.BatchRows {
+			end := start + r.dialect.BatchRows
+			if end > len(states) {
+				end = len(states)
+			}
+			var query strings.Builder
+			query.WriteString(sqlCreateStatuses)
+			args := make([]interface{}, 0, 7*(end-start))
+			for i, status := range states[start:end] {
+				if i > 0 {
+					query.WriteString(", ")
+				}
+				n := len(args)
+				fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7)
+				args = append(args, status.Date.UTC(), status.Status, status.SiteId,
+					status.Latency.Milliseconds(), status.Proxy, status.Insecure, status.Error)
+			}
+			if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if r.dialect.ConstraintViolation(err) {
+			err = fmt.Errorf("%w: %v", statuses.ErrStatesRejected, err)
+		}
+		log.ErrorLog().Str("when", "processing the sql request").
+			Err(err).Msg("unable to create statuses")
+		return err
+	}
+	return nil
+}
+
+func (r *StatusRepository) ReadByUrl(ctx context.Context, url string, count int64) (*statuses.History, error) {
+	log := logging.NewLoggers(r.dialect.Name, "readStatus")
+	log.DebugLog().Msg("processing the sql request")
+	rows, cancel, err := r.conn.Query(ctx, sqlGetStatus, url, count)
+	if err != nil {
+		if err == db.ErrNothingDone {
+			err = statuses.ErrStatusNotFound
+		}
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").
+			Msg("unable to get rows")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+	history := &statuses.History{Url: url, States: make([]*statuses.State, 0)}
+	log.DebugLog().Msg("getting all rows")
+	for rows.Next() {
+		s := new(statuses.State)
+		var latency int64
+		if err := rows.Scan(&s.Id, &s.Date, &s.Status, &s.SiteId, &latency, &s.Proxy, &s.Insecure,
+			&s.Error, &history.Url, &history.Frequency); err != nil {
+			log.ErrorLog().Err(err).Str("when", "getting all rows").Msg("unable to get rows")
+			return nil, err
+		}
+		s.Latency = time.Duration(latency) * time.Millisecond
+		history.States = append(history.States, s)
+	}
+
+	return history, nil
+}
+
+// ReadBySite returns the page of the states of the site selected by the filter
+func (r *StatusRepository) ReadBySite(ctx context.Context, filter statuses.Filter) ([]*statuses.State, error) {
+	log := logging.NewLoggers(r.dialect.Name, "readStatesBySite")
+	log.DebugLog().Msg("processing the sql request")
+	if err := filter.Validate(); err != nil {
+		log.WarnLog().Err(err).Str("when", "validate filter").Msg("unable to get rows")
+		return nil, err
+	}
+	query, args := statesQuery(filter)
+	rows, cancel, err := r.conn.Query(ctx, query, args...)
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to get rows")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	list := make([]*statuses.State, 0)
+	for rows.Next() {
+		s := new(statuses.State)
+		var latency int64
+		if err := rows.Scan(&s.Id, &s.Date, &s.Status, &s.SiteId, &latency, &s.Proxy, &s.Insecure,
+			&s.Error); err != nil {
+			log.ErrorLog().Err(err).Str("when", "scan rows").Msg("unable to scan results")
+			return nil, err
+		}
+		s.Date, s.Latency = s.Date.UTC(), time.Duration(latency)*time.Millisecond
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		log.ErrorLog().Err(err).Str("when", "read rows").Msg("unable to get rows")
+		return nil, err
+	}
+	return list, nil
+}
+
+// statesQuery builds the query of the states selected by the filter,
+// the page starts after the state by date and id
+func statesQuery(f statuses.Filter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, values ...interface{}) {
+		placeholders := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			args = append(args, value)
+			placeholders = append(placeholders, len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf(condition, placeholders...))
+	}
+	where("site_id=$%d", f.SiteId)
+	if !f.From.IsZero() {
+		where("date>=$%d", f.From.UTC())
+	}
+	if !f.To.IsZero() {
+		where("date<$%d", f.To.UTC())
+	}
+	switch f.Health {
+	case statuses.HealthUp:
+		conditions = append(conditions, "status_code>=200 AND status_code<400")
+	case statuses.HealthDown:
+		conditions = append(conditions, "NOT (status_code>=200 AND status_code<400)")
+	}
+	if f.After != nil {
+		where("(date, id)<($%d, $%d)", f.After.Date.UTC(), f.After.Id)
+	}
+
+	query := sqlStatesBySite + " WHERE " + strings.Join(conditions, " AND ") + " ORDER BY date DESC, id DESC"
+	if f.Limit != 0 {
+		query += fmt.Sprintf(" LIMIT %d", f.Limit)
+	}
+	return query + ";", args
+}
+
+// LastChecks returns the time of the last check of every
+// site which is not deleted, the time is zero if there were no checks
+func (r *StatusRepository) LastChecks(ctx context.Context) (map[int64]time.Time, error) {
+	log := logging.NewLoggers(r.dialect.Name, "lastChecks")
+	rows, cancel, err := r.conn.Query(ctx, sqlLastChecks, false)
+	if err != nil {
+		log.ErrorLog().Err(err).Str("when", "processing the sql request").Msg("unable to get rows")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	checks := make(map[int64]time.Time)
+	for rows.Next() {
+		var id int64
+		var date aggregateTime
+		if err := rows.Scan(&id, &date); err != nil {
+			log.ErrorLog().Err(err).Str("when", "scan rows").Msg("unable to scan results")
+			return nil, err
+		}
+		checks[id] = date.Time
+	}
+	return checks, nil
+}
@@ -0,0 +1,143 @@
+package statuses
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// ResolutionHour is the resolution of the rollups of raw states
+	ResolutionHour = "hour"
+	// ResolutionDay is the resolution of the rollups of hourly rollups
+	ResolutionDay = "day"
+)
+
+// Rollup aggregates the states of the site in the bucket
+// starting at Start and lasting one hour or one day
+type Rollup struct {
+	SiteId     int64
+	Resolution string
+	Start      time.Time
+	Checks     int64
+	// Up is the number of checks when the site was up
+	Up         int64
+	LatencyP50 time.Duration
+	LatencyP90 time.Duration
+	LatencyP99 time.Duration
+}
+
+// Up reports whether the site answered with 2xx or 3xx,
+// failed requests and all other codes are down
+func (s *State) Up() bool {
+	return s.Status >= 200 && s.Status < 400
+}
+
+// UpRatio returns the share of checks when the site was up
+func (r *Rollup) UpRatio() float64 {
+	if r.Checks == 0 {
+		return 0
+	}
+	return float64(r.Up) / float64(r.Checks)
+}
+
+// Bucket returns the start of the bucket of the resolution, buckets are in UTC
+func Bucket(t time.Time, resolution string) time.Time {
+	if resolution == ResolutionDay {
+		return t.UTC().Truncate(24 * time.Hour)
+	}
+	return t.UTC().Truncate(time.Hour)
+}
+
+type bucketKey struct {
+	siteId int64
+	start  int64
+}
+
+// Aggregate builds the hourly rollups of the states
+func Aggregate(states []*State) []*Rollup {
+	latencies := make(map[bucketKey][]time.Duration)
+	rollups := make(map[bucketKey]*Rollup)
+	for _, s := range states {
+		start := Bucket(s.Date, ResolutionHour)
+		key := bucketKey{siteId: s.SiteId, start: start.Unix()}
+		r, ok := rollups[key]
+		if !ok {
+			r = &Rollup{SiteId: s.SiteId, Resolution: ResolutionHour, Start: start}
+			rollups[key] = r
+		}
+		r.Checks++
+		if s.Up() {
+			r.Up++
+		}
+		latencies[key] = append(latencies[key], s.Latency)
+	}
+	for key, r := range rollups {
+		l := latencies[key]
+		sort.Slice(l, func(i, j int) bool { return l[i] < l[j] })
+		r.LatencyP50, r.LatencyP90, r.LatencyP99 = Percentile(l, 50), Percentile(l, 90), Percentile(l, 99)
+	}
+	return sorted(rollups)
+}
+
+// Add adds the checks of the rollup o of the same site and bucket,
+// such as late states rolled up after the bucket was compacted.
+// Percentiles can not be added exactly, so they
+// are approximated by the mean weighted by checks.
+func (r *Rollup) Add(o *Rollup) {
+	r.LatencyP50 = weighted(r.LatencyP50, r.Checks, o.LatencyP50, o.Checks)
+	r.LatencyP90 = weighted(r.LatencyP90, r.Checks, o.LatencyP90, o.Checks)
+	r.LatencyP99 = weighted(r.LatencyP99, r.Checks, o.LatencyP99, o.Checks)
+	r.Checks += o.Checks
+	r.Up += o.Up
+}
+
+// Merge builds the daily rollups of the hourly rollups,
+// percentiles are approximated as by Add.
+func Merge(hourly []*Rollup) []*Rollup {
+	rollups := make(map[bucketKey]*Rollup)
+	for _, h := range hourly {
+		start := Bucket(h.Start, ResolutionDay)
+		key := bucketKey{siteId: h.SiteId, start: start.Unix()}
+		r, ok := rollups[key]
+		if !ok {
+			r = &Rollup{SiteId: h.SiteId, Resolution: ResolutionDay, Start: start}
+			rollups[key] = r
+		}
+		r.Add(h)
+	}
+	return sorted(rollups)
+}
+
+// Percentile returns the nearest-rank percentile of the sorted latencies
+func Percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func weighted(a time.Duration, aWeight int64, b time.Duration, bWeight int64) time.Duration {
+	if aWeight+bWeight == 0 {
+		return 0
+	}
+	return time.Duration((int64(a)*aWeight + int64(b)*bWeight) / (aWeight + bWeight))
+}
+
+// sorted returns rollups ordered by site and start
+func sorted(rollups map[bucketKey]*Rollup) []*Rollup {
+	list := make([]*Rollup, 0, len(rollups))
+	for _, r := range rollups {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SiteId != list[j].SiteId {
+			return list[i].SiteId < list[j].SiteId
+		}
+		return list[i].Start.Before(list[j].Start)
+	})
+	return list
+}
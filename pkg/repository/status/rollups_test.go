@@ -0,0 +1,89 @@
+package statuses
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUp(t *testing.T) {
+	for code, want := range map[int64]bool{199: false, 200: true, 302: true, 399: true, 404: false, 500: false, 600: false} {
+		if got := (&State{Status: code}).Up(); got != want {
+			t.Errorf("Up() of %d = %t, want %t", code, got, want)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	hour := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	var states []*State
+	// 100 checks with latency 1ms..100ms, every tenth is down
+	for i := 1; i <= 100; i++ {
+		code := int64(200)
+		if i%10 == 0 {
+			code = 503
+		}
+		states = append(states, &State{
+			Date:    hour.Add(time.Duration(i) * time.Second),
+			Status:  code,
+			SiteId:  1,
+			Latency: time.Duration(i) * time.Millisecond,
+		})
+	}
+	states = append(states, &State{Date: hour.Add(time.Hour), Status: 200, SiteId: 1, Latency: time.Second})
+	states = append(states, &State{Date: hour, Status: 600, SiteId: 2})
+
+	rollups := Aggregate(states)
+	if len(rollups) != 3 {
+		t.Fatalf("Aggregate returned %d rollups, want 3", len(rollups))
+	}
+	want := Rollup{
+		SiteId:     1,
+		Resolution: ResolutionHour,
+		Start:      hour,
+		Checks:     100,
+		Up:         90,
+		LatencyP50: 50 * time.Millisecond,
+		LatencyP90: 90 * time.Millisecond,
+		LatencyP99: 99 * time.Millisecond,
+	}
+	if *rollups[0] != want {
+		t.Errorf("rollup = %+v, want %+v", *rollups[0], want)
+	}
+	if r := rollups[1]; !r.Start.Equal(hour.Add(time.Hour)) || r.Checks != 1 || r.LatencyP99 != time.Second {
+		t.Errorf("rollup of the next hour = %+v", *r)
+	}
+	if r := rollups[2]; r.SiteId != 2 || r.Up != 0 || r.UpRatio() != 0 {
+		t.Errorf("rollup of the failed site = %+v", *r)
+	}
+	if ratio := rollups[0].UpRatio(); ratio != 0.9 {
+		t.Errorf("UpRatio() = %v, want 0.9", ratio)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	day := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+	hourly := []*Rollup{
+		{SiteId: 1, Resolution: ResolutionHour, Start: day, Checks: 3, Up: 3,
+			LatencyP50: 10 * time.Millisecond, LatencyP90: 10 * time.Millisecond, LatencyP99: 10 * time.Millisecond},
+		{SiteId: 1, Resolution: ResolutionHour, Start: day.Add(23 * time.Hour), Checks: 1, Up: 0,
+			LatencyP50: 50 * time.Millisecond, LatencyP90: 50 * time.Millisecond, LatencyP99: 50 * time.Millisecond},
+		{SiteId: 1, Resolution: ResolutionHour, Start: day.Add(24 * time.Hour), Checks: 1, Up: 1},
+	}
+	daily := Merge(hourly)
+	if len(daily) != 2 {
+		t.Fatalf("Merge returned %d rollups, want 2", len(daily))
+	}
+	want := Rollup{
+		SiteId:     1,
+		Resolution: ResolutionDay,
+		Start:      day,
+		Checks:     4,
+		Up:         3,
+		LatencyP50: 20 * time.Millisecond,
+		LatencyP90: 20 * time.Millisecond,
+		LatencyP99: 20 * time.Millisecond,
+	}
+	if *daily[0] != want {
+		t.Errorf("daily rollup = %+v, want %+v", *daily[0], want)
+	}
+}
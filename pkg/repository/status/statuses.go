@@ -1,82 +1,103 @@
 package statuses
 
 import (
-	"CheckUrls/pkg/db"
-	"CheckUrls/pkg/logging"
-	"CheckUrls/pkg/proto"
 	"fmt"
-	"google.golang.org/protobuf/types/known/timestamppb"
 	"time"
 )
 
-const (
-	sqlCreateStatus = "INSERT INTO status (date, status_code, site_id) VALUES ($1, $2, $3);"
-	sqlGetStatus    = "SELECT st.id, st.date, st.status_code, s.id AS site_id, s.url, s.frequency " +
-		"FROM status st JOIN sites s ON s.id=st.site_id WHERE s.url=$1 ORDER BY st.date DESC LIMIT $2;"
+var (
+	ErrStatusNotFound  = fmt.Errorf("status not found")
+	ErrIncorrectFilter = fmt.Errorf("incorrect filter of states")
+	// ErrStatesRejected is returned when the states break a constraint of the
+	// database, e.g. their site is purged, so saving them again fails as well
+	ErrStatesRejected = fmt.Errorf("states are rejected")
 )
 
-var ErrStatusNotFound = fmt.Errorf("status not found")
+// health of the states selected by the filter
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
 
 type State struct {
-	Id     int64
-	Date   time.Time
-	Status int64
+	Id      int64
+	Date    time.Time
+	Status  int64
+	SiteId  int64
+	Latency time.Duration
+	// Proxy used by the check without password
+	Proxy string
+	// Insecure is set when TLS verification was skipped
+	Insecure bool
+	// Error is why the check failed before the request was sent,
+	// e.g. the client certificate was not read
+	Error string
+}
+
+// History is the latest states of the site,
+// older checks are returned as rollups when
+// raw states were compacted
+type History struct {
+	Url       string
+	Frequency int64
+	States    []*State
+	Rollups   []*Rollup
+}
+
+// Filter selects the states of the site, they
+// are ordered from the latest one by date and id
+type Filter struct {
 	SiteId int64
+	// From is inclusive and To is exclusive, zero - without the bound
+	From time.Time
+	To   time.Time
+	// Health is HealthUp, HealthDown or empty for all states
+	Health string
+	// After is the last state of the previous page,
+	// only its date and id are used
+	After *State
+	// Limit is the size of the page, 0 - all states
+	Limit int64
 }
 
-func CreateStatus(conn *db.ConnectionManager, status *State) error {
-	log := logging.NewLoggers("statuses", "createStatus")
-	log.DebugLog().Msg("processing the sql request")
-	err := conn.Exec(sqlCreateStatus, status.Date, status.Status, status.SiteId)
-	if err != nil {
-		if err == db.ErrNothingDone {
-			err = ErrStatusNotFound
-		}
-		log.ErrorLog().Str("when", "processing the sql request").
-			Err(err).Msg("unable to get row")
-		return err
+// Validate checks the filter
+func (f Filter) Validate() error {
+	switch {
+	case f.SiteId <= 0:
+		return fmt.Errorf("%w: no site", ErrIncorrectFilter)
+	case f.Health != "" && f.Health != HealthUp && f.Health != HealthDown:
+		return fmt.Errorf("%w: unknown health %q", ErrIncorrectFilter, f.Health)
+	case !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To):
+		return fmt.Errorf("%w: from must be before to", ErrIncorrectFilter)
+	case f.Limit < 0:
+		return fmt.Errorf("%w: negative limit", ErrIncorrectFilter)
 	}
-
 	return nil
 }
 
-func ReadStatus(conn *db.ConnectionManager, url string, count int64) (*proto.StatusResponse, error) {
-	log := logging.NewLoggers("statuses", "readStatus")
-	log.DebugLog().Msg("processing the sql request")
-	rows, cancel, err := conn.Query(sqlGetStatus, url, count)
-	if err != nil {
-		if err == db.ErrNothingDone {
-			err = ErrStatusNotFound
-		}
-		log.ErrorLog().Err(err).Str("when", "processing the sql request").
-			Msg("unable to get rows")
-		return nil, err
-	}
-	defer cancel()
-	defer func() {
-		if err := rows.Close(); err != nil {
-			log.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
-			err.Error()
-		}
-	}()
-	var frequency int64
-	list := make([]*proto.State, 0)
-	log.DebugLog().Msg("getting all rows")
-	for rows.Next() {
-		s := new(proto.State)
-		var checkTime time.Time
-		if err := rows.Scan(&s.Id, &checkTime, &s.Status, &s.SiteId, &url, &frequency); err != nil {
-			log.ErrorLog().Err(err).Str("when", "getting all rows").Msg("unable to get rows")
-			return nil, err
-		}
-		s.Date = timestamppb.New(checkTime)
-		list = append(list, s)
+// Match reports whether the state is selected by the filter
+// and goes after the previous page
+func (f Filter) Match(s *State) bool {
+	switch {
+	case s.SiteId != f.SiteId:
+		return false
+	case !f.From.IsZero() && s.Date.Before(f.From):
+		return false
+	case !f.To.IsZero() && !s.Date.Before(f.To):
+		return false
+	case f.Health != "" && s.Up() != (f.Health == HealthUp):
+		return false
+	case f.After != nil && !Later(f.After, s):
+		return false
 	}
+	return true
+}
 
-	return &proto.StatusResponse{
-		Url:       url,
-		Frequency: frequency,
-		States:    list,
-	}, nil
-
+// Later reports whether the state a goes before the state b
+// in the order of the history, which is from the latest one
+func Later(a, b *State) bool {
+	if !a.Date.Equal(b.Date) {
+		return a.Date.After(b.Date)
+	}
+	return a.Id > b.Id
 }
@@ -0,0 +1,146 @@
+// Package retention compacts the history of checks: raw states
+// are replaced by hourly rollups and hourly rollups by daily ones.
+package retention
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/metrics"
+	"CheckUrls/pkg/repository"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"fmt"
+	"time"
+)
+
+var ErrNoProgress = fmt.Errorf("compaction did not remove compacted rows")
+
+type RetentionConfig interface {
+	GetRawRetentionDays() int
+	GetHourlyRetentionMonths() int
+	GetCompactInterval() time.Duration
+}
+
+type Compactor struct {
+	statuses     repository.StatusRepository
+	rawDays      int
+	hourlyMonths int
+	interval     time.Duration
+}
+
+func NewCompactor(cfg RetentionConfig, statusRepo repository.StatusRepository) *Compactor {
+	return &Compactor{
+		statuses:     statusRepo,
+		rawDays:      cfg.GetRawRetentionDays(),
+		hourlyMonths: cfg.GetHourlyRetentionMonths(),
+		interval:     cfg.GetCompactInterval(),
+	}
+}
+
+// Run compacts the history on start and then every interval until ctx is done,
+// nothing is compacted if the interval is not positive.
+// Failed compactions are logged and retried on the next run.
+func (c *Compactor) Run(ctx context.Context) error {
+	logger := logging.NewLoggers("retention", "run")
+	if c.interval <= 0 {
+		logger.DebugLog().Msg("compaction is disabled")
+		return nil
+	}
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Compact(ctx, time.Now()); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "compact history").Msg("unable to compact history")
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// RawFrom returns the start of the raw states kept at now, the older ones
+// are compacted into rollups. It is zero when the raw states are kept forever.
+func (c *Compactor) RawFrom(now time.Time) time.Time {
+	if c.rawDays <= 0 || c.interval <= 0 {
+		return time.Time{}
+	}
+	return statuses.Bucket(now.AddDate(0, 0, -c.rawDays), statuses.ResolutionHour)
+}
+
+// Compact rolls up the raw states older than the raw retention into hourly
+// rollups and the hourly rollups older than the hourly retention into daily ones.
+// Only complete hours and days are rolled up, so compaction can be repeated.
+func (c *Compactor) Compact(ctx context.Context, now time.Time) error {
+	if c.rawDays <= 0 {
+		return nil
+	}
+	rawCutoff := statuses.Bucket(now.AddDate(0, 0, -c.rawDays), statuses.ResolutionHour)
+	if err := c.compactStates(ctx, rawCutoff); err != nil {
+		return err
+	}
+
+	if c.hourlyMonths <= 0 {
+		return nil
+	}
+	// a day is merged once, so it must not get new hourly rollups later
+	hourlyCutoff := statuses.Bucket(now.AddDate(0, -c.hourlyMonths, 0), statuses.ResolutionDay)
+	if rawDay := statuses.Bucket(rawCutoff, statuses.ResolutionDay); rawDay.Before(hourlyCutoff) {
+		hourlyCutoff = rawDay
+	}
+	return c.compactRollups(ctx, hourlyCutoff)
+}
+
+// compactStates replaces the raw states checked before cutoff hour by hour
+func (c *Compactor) compactStates(ctx context.Context, cutoff time.Time) error {
+	logger := logging.NewLoggers("retention", "compactStates")
+	var done time.Time
+	for {
+		oldest, err := c.statuses.OldestState(ctx)
+		if err != nil {
+			return err
+		}
+		if oldest.IsZero() || !oldest.Before(cutoff) {
+			return nil
+		}
+		if oldest.Before(done) {
+			return ErrNoProgress
+		}
+		start := statuses.Bucket(oldest, statuses.ResolutionHour)
+		done = start.Add(time.Hour)
+
+		// late states of a compacted hour are added to its rollup
+		count, err := c.statuses.CompactStates(ctx, start, done)
+		if err != nil {
+			return err
+		}
+		metrics.StatesCompacted.Add(int64(count))
+		logger.DebugLog().Time("hour", start).Int("states", count).Msg("states compacted")
+	}
+}
+
+// compactRollups replaces the hourly rollups started before cutoff day by day
+func (c *Compactor) compactRollups(ctx context.Context, cutoff time.Time) error {
+	logger := logging.NewLoggers("retention", "compactRollups")
+	var done time.Time
+	for {
+		oldest, err := c.statuses.OldestRollup(ctx, statuses.ResolutionHour)
+		if err != nil {
+			return err
+		}
+		if oldest.IsZero() || !oldest.Before(cutoff) {
+			return nil
+		}
+		if oldest.Before(done) {
+			return ErrNoProgress
+		}
+		start := statuses.Bucket(oldest, statuses.ResolutionDay)
+		done = start.Add(24 * time.Hour)
+
+		count, err := c.statuses.CompactRollups(ctx, start, done)
+		if err != nil {
+			return err
+		}
+		logger.DebugLog().Time("day", start).Int("rollups", count).Msg("rollups compacted")
+	}
+}
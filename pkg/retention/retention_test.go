@@ -0,0 +1,157 @@
+package retention
+
+import (
+	"CheckUrls/pkg/repository/memory"
+	"CheckUrls/pkg/repository/sites"
+	statuses "CheckUrls/pkg/repository/status"
+	"context"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	rawDays, hourlyMonths int
+}
+
+func (c testConfig) GetRawRetentionDays() int          { return c.rawDays }
+func (c testConfig) GetHourlyRetentionMonths() int     { return c.hourlyMonths }
+func (c testConfig) GetCompactInterval() time.Duration { return time.Hour }
+
+func TestCompact(t *testing.T) {
+	ctx := context.Background()
+	siteRepo, statusRepo := memory.NewRepositories()
+	site := &sites.Site{Url: "https://example.com", Frequency: 600}
+	if err := siteRepo.Create(ctx, site, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2021, 6, 15, 12, 30, 0, 0, time.UTC)
+	// a check every 10 minutes for 60 days
+	start := now.AddDate(0, 0, -60)
+	for date := start; date.Before(now); date = date.Add(10 * time.Minute) {
+		state := &statuses.State{Date: date, Status: 200, SiteId: site.Id, Latency: time.Millisecond}
+		if date.Hour() == 3 {
+			state.Status = 500
+		}
+		if err := statusRepo.Create(ctx, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := NewCompactor(testConfig{rawDays: 7, hourlyMonths: 1}, statusRepo)
+	for i := 0; i < 2; i++ {
+		if err := c.Compact(ctx, now); err != nil {
+			t.Fatalf("Compact #%d: %v", i, err)
+		}
+	}
+
+	rawCutoff := time.Date(2021, 6, 8, 12, 0, 0, 0, time.UTC)
+	oldest, err := statusRepo.OldestState(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !oldest.Equal(rawCutoff) {
+		t.Errorf("oldest raw state = %v, want %v", oldest, rawCutoff)
+	}
+
+	dayCutoff := time.Date(2021, 5, 15, 0, 0, 0, 0, time.UTC)
+	hourly, err := statusRepo.ReadRollups(ctx, statuses.ResolutionHour, start, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// hours from the first kept day to the raw cutoff
+	if want := int(rawCutoff.Sub(dayCutoff) / time.Hour); len(hourly) != want {
+		t.Errorf("got %d hourly rollups, want %d", len(hourly), want)
+	}
+	for _, r := range hourly {
+		if r.Start.Before(dayCutoff) || !r.Start.Before(rawCutoff) || r.Checks != 6 {
+			t.Errorf("unexpected hourly rollup %+v", *r)
+		}
+	}
+
+	daily, err := statusRepo.ReadRollups(ctx, statuses.ResolutionDay, start.AddDate(0, 0, -1), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first day was checked from 12:30
+	if want := int(dayCutoff.Sub(statuses.Bucket(start, statuses.ResolutionDay)) / (24 * time.Hour)); len(daily) != want {
+		t.Errorf("got %d daily rollups, want %d", len(daily), want)
+	}
+	for _, r := range daily {
+		if r.Start.After(start) && r.Checks != 144 {
+			t.Errorf("daily rollup %v has %d checks, want 144", r.Start, r.Checks)
+		}
+		if r.Start.After(start) && r.Checks-r.Up != 6 {
+			t.Errorf("daily rollup %v has %d failed checks, want 6", r.Start, r.Checks-r.Up)
+		}
+	}
+}
+
+func TestCompactKeepsRawDays(t *testing.T) {
+	ctx := context.Background()
+	siteRepo, statusRepo := memory.NewRepositories()
+	site := &sites.Site{Url: "https://example.com"}
+	if err := siteRepo.Create(ctx, site, nil); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2021, 6, 15, 12, 30, 0, 0, time.UTC)
+	old := now.AddDate(-1, 0, 0)
+	if err := statusRepo.Create(ctx, &statuses.State{Date: old, Status: 200, SiteId: site.Id}); err != nil {
+		t.Fatal(err)
+	}
+
+	// raw states are kept forever without raw retention
+	if err := NewCompactor(testConfig{hourlyMonths: 1}, statusRepo).Compact(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if oldest, _ := statusRepo.OldestState(ctx); !oldest.Equal(old) {
+		t.Errorf("oldest raw state = %v, want %v", oldest, old)
+	}
+
+	// hourly rollups are kept forever without hourly retention
+	if err := NewCompactor(testConfig{rawDays: 1}, statusRepo).Compact(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	if oldest, _ := statusRepo.OldestRollup(ctx, statuses.ResolutionHour); !oldest.Equal(old.Truncate(time.Hour)) {
+		t.Errorf("oldest hourly rollup = %v, want %v", oldest, old.Truncate(time.Hour))
+	}
+}
+
+func TestCompactLateState(t *testing.T) {
+	ctx := context.Background()
+	siteRepo, statusRepo := memory.NewRepositories()
+	site := &sites.Site{Url: "https://example.com"}
+	if err := siteRepo.Create(ctx, site, nil); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2021, 6, 15, 12, 30, 0, 0, time.UTC)
+	hour := time.Date(2021, 6, 1, 8, 0, 0, 0, time.UTC)
+	for _, date := range []time.Time{hour, hour.Add(10 * time.Minute)} {
+		if err := statusRepo.Create(ctx, &statuses.State{Date: date, Status: 200, SiteId: site.Id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewCompactor(testConfig{rawDays: 7}, statusRepo)
+	if err := c.Compact(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+
+	// a state of the compacted hour comes late, e.g. replayed from the spool
+	if err := statusRepo.Create(ctx, &statuses.State{Date: hour.Add(20 * time.Minute), Status: 500,
+		SiteId: site.Id}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Compact(ctx, now); err != nil {
+		t.Fatal(err)
+	}
+	rollups, err := statusRepo.ReadRollups(ctx, statuses.ResolutionHour, hour, hour.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rollups) != 1 || rollups[0].Checks != 3 || rollups[0].Up != 2 {
+		t.Errorf("rollups = %+v, want one of 3 checks and 2 up", rollups)
+	}
+	if oldest, _ := statusRepo.OldestState(ctx); !oldest.IsZero() {
+		t.Errorf("oldest raw state = %v, want none", oldest)
+	}
+}
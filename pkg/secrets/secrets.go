@@ -0,0 +1,300 @@
+package secrets
+
+import (
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/logging"
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+)
+
+const (
+	sqlSecretSet = "INSERT INTO secrets (name, key_id, nonce, value) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (name) DO UPDATE SET key_id=$2, nonce=$3, value=$4;"
+	sqlSecretGet    = "SELECT key_id, nonce, value FROM secrets WHERE name=$1;"
+	sqlSecretDelete = "DELETE FROM secrets WHERE name=$1;"
+	sqlSecretList   = "SELECT name FROM secrets ORDER BY name;"
+	sqlSecretStale  = "SELECT name FROM secrets WHERE key_id<>$1;"
+	// sqlSecretCreate fails if the secret exists, so no secret is replaced by a moved password
+	sqlSecretCreate = "INSERT INTO secrets (name, key_id, nonce, value) VALUES ($1, $2, $3, $4);"
+	// sqlProxyPasswords reads the proxies of the sites saved before the proxy secrets
+	sqlProxyPasswords = "SELECT id, proxy FROM sites WHERE proxy_secret='' AND proxy LIKE '%@%';"
+	sqlProxyMove      = "UPDATE sites SET proxy=$2, proxy_secret=$3, version=version+1 WHERE id=$1;"
+)
+
+var (
+	ErrSecretNotFound = fmt.Errorf("secret not found")
+	ErrNoKeys         = fmt.Errorf("no keys in key file")
+	ErrUnknownKey     = fmt.Errorf("secret is encrypted with unknown key")
+)
+
+type SecretsConfig interface {
+	GetSecretsKeyFile() string
+}
+
+// Keyring holds AES keys from the key file. Every line
+// of the file is "<key_id>:<base64 of 32 bytes>", the first
+// key encrypts new values, the others only decrypt old ones.
+type Keyring struct {
+	primary string
+	keys    map[string]cipher.AEAD
+}
+
+func LoadKeyring(path string) (*Keyring, error) {
+	logger := logging.NewLoggers("secrets", "loadKeyring")
+	file, err := os.Open(path)
+	if err != nil {
+		logger.ErrorLog().Str("when", "open key file").Err(err).Msg("unable to open key file")
+		return nil, err
+	}
+	defer file.Close()
+	return ParseKeyring(file)
+}
+
+func ParseKeyring(r io.Reader) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]cipher.AEAD)}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("incorrect key line, expected <key_id>:<base64 key>")
+		}
+		raw, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", parts[0], err)
+		}
+		if len(raw) != 32 {
+			return nil, fmt.Errorf("key %q: AES-256 key must be 32 bytes", parts[0])
+		}
+		block, err := aes.NewCipher(raw)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		if k.primary == "" {
+			k.primary = parts[0]
+		}
+		k.keys[parts[0]] = aead
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if k.primary == "" {
+		return nil, ErrNoKeys
+	}
+	return k, nil
+}
+
+// seal encrypts the value with the primary key,
+// the name is authenticated so values can't be swapped
+func (k *Keyring) seal(name string, value logging.Secret) (string, []byte, []byte, error) {
+	aead := k.keys[k.primary]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, nil, err
+	}
+	return k.primary, nonce, aead.Seal(nil, nonce, []byte(value), []byte(name)), nil
+}
+
+func (k *Keyring) open(name, keyId string, nonce, value []byte) (logging.Secret, error) {
+	aead, ok := k.keys[keyId]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, keyId)
+	}
+	plain, err := aead.Open(nil, nonce, value, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return logging.Secret(plain), nil
+}
+
+// Store keeps secrets encrypted in the secrets table
+type Store struct {
+	conn    *db.ConnectionManager
+	keyring *Keyring
+}
+
+func NewStore(conn *db.ConnectionManager, keyring *Keyring) *Store {
+	return &Store{conn: conn, keyring: keyring}
+}
+
+func (s *Store) Set(ctx context.Context, name string, value logging.Secret) error {
+	logger := logging.NewLoggers("secrets", "set")
+	keyId, nonce, sealed, err := s.keyring.seal(name, value)
+	if err != nil {
+		logger.ErrorLog().Str("when", "encrypt secret").Str("name", name).Err(err).Msg("unable to encrypt secret")
+		return err
+	}
+	if err := s.conn.Exec(ctx, sqlSecretSet, name, keyId, nonce, sealed); err != nil {
+		logger.ErrorLog().Str("when", "processing the sql request").Str("name", name).Err(err).
+			Msg("unable to save secret")
+		return err
+	}
+	return nil
+}
+
+func (s *Store) Get(ctx context.Context, name string) (logging.Secret, error) {
+	logger := logging.NewLoggers("secrets", "get")
+	row, cancel, err := s.conn.QueryRow(ctx, sqlSecretGet, name)
+	if err != nil {
+		logger.ErrorLog().Str("when", "processing the sql request").Str("name", name).Err(err).
+			Msg("unable to get secret")
+		return "", err
+	}
+	defer cancel()
+
+	var keyId string
+	var nonce, sealed []byte
+	if err := row.Scan(&keyId, &nonce, &sealed); err != nil {
+		if err == sql.ErrNoRows {
+			err = ErrSecretNotFound
+		}
+		logger.WarnLog().Str("when", "scan secret").Str("name", name).Err(err).Msg("unable to get secret")
+		return "", err
+	}
+	value, err := s.keyring.open(name, keyId, nonce, sealed)
+	if err != nil {
+		logger.ErrorLog().Str("when", "decrypt secret").Str("name", name).Err(err).Msg("unable to decrypt secret")
+		return "", err
+	}
+	return value, nil
+}
+
+func (s *Store) Delete(ctx context.Context, name string) error {
+	logger := logging.NewLoggers("secrets", "delete")
+	if err := s.conn.Exec(ctx, sqlSecretDelete, name); err != nil {
+		if err == db.ErrNothingDone {
+			err = ErrSecretNotFound
+		}
+		logger.WarnLog().Str("when", "processing the sql request").Str("name", name).Err(err).
+			Msg("unable to delete secret")
+		return err
+	}
+	return nil
+}
+
+// List returns names of secrets, values are never listed
+func (s *Store) List(ctx context.Context) ([]string, error) {
+	return s.names(ctx, sqlSecretList)
+}
+
+// Rotate encrypts all secrets which use
+// old keys with the primary key.
+func (s *Store) Rotate(ctx context.Context) (int, error) {
+	logger := logging.NewLoggers("secrets", "rotate")
+	names, err := s.names(ctx, sqlSecretStale, s.keyring.primary)
+	if err != nil {
+		return 0, err
+	}
+	for i, name := range names {
+		value, err := s.Get(ctx, name)
+		if err != nil {
+			return i, err
+		}
+		if err := s.Set(ctx, name, value); err != nil {
+			return i, err
+		}
+		logger.InfoLog().Str("name", name).Msg("secret is rotated")
+	}
+	return len(names), nil
+}
+
+// MoveProxies moves the proxy passwords saved with the sites to the
+// secrets "proxy-site-<id>", the sites reference them as proxy secrets.
+// All passwords are moved in one transaction.
+func (s *Store) MoveProxies(ctx context.Context) (int, error) {
+	logger := logging.NewLoggers("secrets", "moveProxies")
+	count := 0
+	err := s.conn.Tx(ctx, func(tx *db.Tx) error {
+		rows, err := tx.QueryContext(ctx, sqlProxyPasswords)
+		if err != nil {
+			return err
+		}
+		proxies := make(map[int64]string)
+		for rows.Next() {
+			var id int64
+			var proxy string
+			if err := rows.Scan(&id, &proxy); err != nil {
+				_ = rows.Close()
+				return err
+			}
+			proxies[id] = proxy
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+
+		for id, proxy := range proxies {
+			u, err := url.Parse(proxy)
+			if err != nil {
+				return fmt.Errorf("proxy of the site %d: %w", id, err)
+			}
+			password, ok := u.User.Password()
+			if !ok {
+				continue
+			}
+			name := fmt.Sprintf("proxy-site-%d", id)
+			keyId, nonce, sealed, err := s.keyring.seal(name, logging.Secret(password))
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, sqlSecretCreate, name, keyId, nonce, sealed); err != nil {
+				return fmt.Errorf("secret %q: %w", name, err)
+			}
+			u.User = url.User(u.User.Username())
+			if _, err := tx.ExecContext(ctx, sqlProxyMove, id, u.String(), name); err != nil {
+				return err
+			}
+			logger.InfoLog().Int64("site_id", id).Str("name", name).Msg("proxy password is moved")
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		logger.ErrorLog().Str("when", "processing the sql request").Err(err).Msg("unable to move proxy passwords")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *Store) names(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	logger := logging.NewLoggers("secrets", "names")
+	rows, cancel, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		logger.ErrorLog().Str("when", "processing the sql request").Err(err).Msg("unable to list secrets")
+		return nil, err
+	}
+	defer cancel()
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "close rows").Msg("unable to close rows")
+		}
+	}()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			logger.ErrorLog().Err(err).Str("when", "scan results").Msg("unable to scan results")
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"CheckUrls/pkg/logging"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const (
+	oldKey = "old:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+	newKey = "new:AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE="
+)
+
+func TestKeyringRotation(t *testing.T) {
+	old, err := ParseKeyring(strings.NewReader(oldKey + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyId, nonce, sealed, err := old.seal("token", "s3cret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyId != "old" {
+		t.Errorf("key_id = %q, want old", keyId)
+	}
+
+	rotated, err := ParseKeyring(strings.NewReader("# primary first\n" + newKey + "\n" + oldKey + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := rotated.open("token", keyId, nonce, sealed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(value) != "s3cret" {
+		t.Errorf("value = %q, want s3cret", string(value))
+	}
+	if keyId, _, _, _ := rotated.seal("token", value); keyId != "new" {
+		t.Errorf("key_id = %q, want new", keyId)
+	}
+
+	if _, err := rotated.open("other", keyId, nonce, sealed); err == nil {
+		t.Error("value of another secret must not be decrypted")
+	}
+
+	fresh, err := ParseKeyring(strings.NewReader(newKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fresh.open("token", keyId, nonce, sealed); err == nil {
+		t.Error("value encrypted with removed key must not be decrypted")
+	}
+}
+
+func TestParseKeyringErrors(t *testing.T) {
+	for _, keys := range []string{"", "# only comment", "nokey", "short:AAAA", "bad:!!!"} {
+		if _, err := ParseKeyring(strings.NewReader(keys)); err == nil {
+			t.Errorf("expected error for %q", keys)
+		}
+	}
+}
+
+func TestSecretIsRedacted(t *testing.T) {
+	value := logging.Secret("s3cret")
+	b, err := json.Marshal(map[string]interface{}{"value": value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, out := range []string{fmt.Sprint(value), fmt.Sprintf("%v %s %#v", value, value, value), string(b)} {
+		if strings.Contains(out, "s3cret") {
+			t.Errorf("secret is not redacted: %s", out)
+		}
+	}
+}
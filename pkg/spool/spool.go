@@ -0,0 +1,312 @@
+// Package spool keeps the results of checks on disk
+// while they can not be saved in the database.
+//
+// The spool is a directory of append-only segment files with
+// a state per line in JSON. Segments are replayed in the order
+// of their names and removed when they are saved, a segment
+// saved just before a crash can be saved again after restart.
+package spool
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/metrics"
+	statuses "CheckUrls/pkg/repository/status"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentExt = ".spool"
+	// segmentSize is the size after which a new segment is started
+	segmentSize = 1 << 20
+	// replayBatch is the max number of states saved at once on replay
+	replayBatch = 1000
+)
+
+var ErrSpoolFull = fmt.Errorf("spool is full")
+
+type SpoolConfig interface {
+	GetSpoolDir() string
+	GetSpoolMaxBytes() int64
+}
+
+// record is the state on disk
+type record struct {
+	Date     time.Time `json:"date"`
+	Status   int64     `json:"status"`
+	SiteId   int64     `json:"site_id"`
+	Latency  int64     `json:"latency_ms"`
+	Proxy    string    `json:"proxy,omitempty"`
+	Insecure bool      `json:"insecure,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type Spool struct {
+	mu       sync.Mutex
+	dir      string
+	maxBytes int64
+	// current is the segment open for appending
+	current     *os.File
+	currentSize int64
+	nextSeq     int64
+	bytes       int64
+	depth       int64
+}
+
+// Open opens the spool in the directory, which is created if
+// it does not exist, and counts the states left by the last run
+func Open(cfg SpoolConfig) (*Spool, error) {
+	logger := logging.NewLoggers("spool", "open")
+	s := &Spool{dir: cfg.GetSpoolDir(), maxBytes: cfg.GetSpoolMaxBytes(), nextSeq: 1}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		logger.ErrorLog().Err(err).Str("when", "create spool directory").Msg("unable to open spool")
+		return nil, err
+	}
+	segments, err := s.segments()
+	if err != nil {
+		logger.ErrorLog().Err(err).Str("when", "list segments").Msg("unable to open spool")
+		return nil, err
+	}
+	for _, segment := range segments {
+		states, size, err := s.read(segment)
+		if err != nil {
+			logger.ErrorLog().Err(err).Str("segment", segment).Msg("unable to read segment")
+			return nil, err
+		}
+		s.depth += int64(len(states))
+		s.bytes += size
+		if seq := sequence(segment); seq >= s.nextSeq {
+			s.nextSeq = seq + 1
+		}
+	}
+	s.report()
+	if s.depth > 0 {
+		logger.WarnLog().Int64("states", s.depth).Msg("spool has states of the last run")
+	}
+	return s, nil
+}
+
+// Len returns the number of spooled states
+func (s *Spool) Len() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.depth
+}
+
+// Append writes the states to disk, they are kept
+// if they fit in the size limit or dropped otherwise
+func (s *Spool) Append(states []*statuses.State) error {
+	data, err := encode(states)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxBytes > 0 && s.bytes+int64(len(data)) > s.maxBytes {
+		return ErrSpoolFull
+	}
+	if s.current == nil || s.currentSize >= segmentSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.current.Write(data)
+	s.currentSize += int64(n)
+	s.bytes += int64(n)
+	if err != nil {
+		return err
+	}
+	if err := s.current.Sync(); err != nil {
+		return err
+	}
+	s.depth += int64(len(states))
+	metrics.StatesSpooled.Add(int64(len(states)))
+	s.report()
+	return nil
+}
+
+// Replay saves the spooled states in order, segment by segment, it stops
+// on the first error and returns the number of states removed from the spool.
+// save returns the number of the states it is done with from the first one,
+// they are saved or dropped for good, so they are removed on error as well.
+func (s *Spool) Replay(save func(states []*statuses.State) (int, error)) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.closeCurrent(); err != nil {
+		return 0, err
+	}
+	segments, err := s.segments()
+	if err != nil {
+		return 0, err
+	}
+
+	var saved int64
+	for _, segment := range segments {
+		states, size, err := s.read(segment)
+		if err != nil {
+			return saved, err
+		}
+		for start := 0; start < len(states); start += replayBatch {
+			end := start + replayBatch
+			if end > len(states) {
+				end = len(states)
+			}
+			done, err := save(states[start:end])
+			if err != nil {
+				if done += start; done > 0 {
+					// the saved part is removed, so it is not saved twice
+					if rwErr := s.rewrite(segment, states[done:]); rwErr != nil {
+						return saved, rwErr
+					}
+					saved += int64(done)
+					s.depth -= int64(done)
+				}
+				return saved, err
+			}
+		}
+		if err := os.Remove(segment); err != nil {
+			return saved, err
+		}
+		saved += int64(len(states))
+		s.depth -= int64(len(states))
+		s.bytes -= size
+		s.report()
+	}
+	return saved, nil
+}
+
+// Close closes the current segment, the spooled states are kept
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeCurrent()
+}
+
+// rewrite replaces the segment by the states
+func (s *Spool) rewrite(segment string, states []*statuses.State) error {
+	data, err := encode(states)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(segment)
+	if err != nil {
+		return err
+	}
+	tmp := segment + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, segment); err != nil {
+		return err
+	}
+	s.bytes += int64(len(data)) - info.Size()
+	s.report()
+	return nil
+}
+
+func (s *Spool) rotate() error {
+	if err := s.closeCurrent(); err != nil {
+		return err
+	}
+	name := filepath.Join(s.dir, fmt.Sprintf("%020d%s", s.nextSeq, segmentExt))
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	s.nextSeq++
+	s.current = f
+	s.currentSize = 0
+	return nil
+}
+
+func (s *Spool) closeCurrent() error {
+	if s.current == nil {
+		return nil
+	}
+	err := s.current.Close()
+	s.current = nil
+	return err
+}
+
+// segments returns the segment files in the order of writing
+func (s *Spool) segments() ([]string, error) {
+	segments, err := filepath.Glob(filepath.Join(s.dir, "*"+segmentExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(segments)
+	return segments, nil
+}
+
+// read returns the states of the segment and its size. A torn
+// line left by a crash during append is skipped.
+func (s *Spool) read(segment string) ([]*statuses.State, int64, error) {
+	logger := logging.NewLoggers("spool", "read")
+	data, err := os.ReadFile(segment)
+	if err != nil {
+		return nil, 0, err
+	}
+	states := make([]*statuses.State, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		var r record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			logger.WarnLog().Err(err).Str("segment", segment).Msg("skip damaged state")
+			continue
+		}
+		states = append(states, &statuses.State{
+			Date:     r.Date,
+			Status:   r.Status,
+			SiteId:   r.SiteId,
+			Latency:  time.Duration(r.Latency) * time.Millisecond,
+			Proxy:    r.Proxy,
+			Insecure: r.Insecure,
+			Error:    r.Error,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	return states, int64(len(data)), nil
+}
+
+// encode returns the states in JSON, a state per line
+func encode(states []*statuses.State) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, state := range states {
+		if err := enc.Encode(record{
+			Date:     state.Date,
+			Status:   state.Status,
+			SiteId:   state.SiteId,
+			Latency:  state.Latency.Milliseconds(),
+			Proxy:    state.Proxy,
+			Insecure: state.Insecure,
+			Error:    state.Error,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *Spool) report() {
+	metrics.SpoolDepth.Set(s.depth)
+	metrics.SpoolBytes.Set(s.bytes)
+}
+
+func sequence(segment string) int64 {
+	seq, _ := strconv.ParseInt(strings.TrimSuffix(filepath.Base(segment), segmentExt), 10, 64)
+	return seq
+}
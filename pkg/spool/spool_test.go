@@ -0,0 +1,226 @@
+package spool
+
+import (
+	statuses "CheckUrls/pkg/repository/status"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	dir      string
+	maxBytes int64
+}
+
+func (c testConfig) GetSpoolDir() string     { return c.dir }
+func (c testConfig) GetSpoolMaxBytes() int64 { return c.maxBytes }
+
+func open(t *testing.T, cfg testConfig) *Spool {
+	s, err := Open(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func states(from, count int) []*statuses.State {
+	date := time.Date(2021, 6, 15, 12, 0, 0, 0, time.UTC)
+	result := make([]*statuses.State, 0, count)
+	for i := from; i < from+count; i++ {
+		result = append(result, &statuses.State{
+			Date:    date.Add(time.Duration(i) * time.Second),
+			Status:  200,
+			SiteId:  int64(i),
+			Latency: time.Duration(i) * time.Millisecond,
+		})
+	}
+	return result
+}
+
+// collect saves the replayed states and fails after limit states,
+// the states up to the limit are saved if partly is set
+type collect struct {
+	saved  []*statuses.State
+	limit  int
+	partly bool
+}
+
+var errSave = errors.New("DB is down")
+
+func (c *collect) save(states []*statuses.State) (int, error) {
+	if c.limit > 0 && len(c.saved)+len(states) > c.limit {
+		if !c.partly {
+			return 0, errSave
+		}
+		done := c.limit - len(c.saved)
+		c.saved = append(c.saved, states[:done]...)
+		return done, errSave
+	}
+	c.saved = append(c.saved, states...)
+	return len(states), nil
+}
+
+func checkOrder(t *testing.T, got []*statuses.State, from, count int) {
+	t.Helper()
+	if len(got) != count {
+		t.Fatalf("got %d states, want %d", len(got), count)
+	}
+	for i, state := range got {
+		if state.SiteId != int64(from+i) || !state.Date.Equal(states(from+i, 1)[0].Date) ||
+			state.Latency != time.Duration(from+i)*time.Millisecond {
+			t.Fatalf("state #%d = %+v, want site %d", i, *state, from+i)
+		}
+	}
+}
+
+func TestReplayInOrder(t *testing.T) {
+	cfg := testConfig{dir: t.TempDir()}
+	s := open(t, cfg)
+	// enough states for several segments
+	for i := 0; i < 30; i++ {
+		if err := s.Append(states(i*1000, 1000)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if segments, _ := s.segments(); len(segments) < 2 {
+		t.Fatalf("got %d segments, want a few", len(segments))
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the states are kept after restart
+	s = open(t, cfg)
+	if s.Len() != 30000 {
+		t.Fatalf("Len = %d after reopen, want 30000", s.Len())
+	}
+	c := &collect{}
+	saved, err := s.Replay(c.save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != 30000 || s.Len() != 0 || s.bytes != 0 {
+		t.Errorf("saved %d, left %d states and %d bytes", saved, s.Len(), s.bytes)
+	}
+	checkOrder(t, c.saved, 0, 30000)
+	if segments, _ := s.segments(); len(segments) != 0 {
+		t.Errorf("%d segments left after replay", len(segments))
+	}
+}
+
+func TestPartialReplay(t *testing.T) {
+	s := open(t, testConfig{dir: t.TempDir()})
+	if err := s.Append(states(0, 2500)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the DB goes down after the first replay batch
+	c := &collect{limit: replayBatch}
+	saved, err := s.Replay(c.save)
+	if err != errSave {
+		t.Fatalf("Replay error = %v, want %v", err, errSave)
+	}
+	if saved != replayBatch || s.Len() != 2500-replayBatch {
+		t.Fatalf("saved %d, left %d", saved, s.Len())
+	}
+
+	// new states go after the spooled ones
+	if err := s.Append(states(2500, 10)); err != nil {
+		t.Fatal(err)
+	}
+	c.limit = 0
+	if _, err := s.Replay(c.save); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, c.saved, 0, 2510)
+}
+
+func TestReplayPartlySaved(t *testing.T) {
+	s := open(t, testConfig{dir: t.TempDir()})
+	if err := s.Append(states(0, 2500)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the DB goes down in the middle of the second replay batch
+	c := &collect{limit: replayBatch + 10, partly: true}
+	saved, err := s.Replay(c.save)
+	if err != errSave {
+		t.Fatalf("Replay error = %v, want %v", err, errSave)
+	}
+	if saved != replayBatch+10 || s.Len() != 2500-replayBatch-10 {
+		t.Fatalf("saved %d, left %d", saved, s.Len())
+	}
+
+	// the states are saved once
+	c.limit = 0
+	if _, err := s.Replay(c.save); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, c.saved, 0, 2500)
+}
+
+func TestSizeLimit(t *testing.T) {
+	s := open(t, testConfig{dir: t.TempDir(), maxBytes: 1000})
+	var err error
+	appended := 0
+	for ; appended < 100; appended++ {
+		if err = s.Append(states(appended, 1)); err != nil {
+			break
+		}
+	}
+	if err != ErrSpoolFull {
+		t.Fatalf("Append error = %v, want %v", err, ErrSpoolFull)
+	}
+	if s.Len() != int64(appended) || s.bytes > 1000 {
+		t.Errorf("Len = %d, bytes = %d, appended %d", s.Len(), s.bytes, appended)
+	}
+
+	// the replay frees the space
+	if _, err := s.Replay((&collect{}).save); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Append(states(0, 1)); err != nil {
+		t.Errorf("Append after replay: %v", err)
+	}
+}
+
+func TestTornLine(t *testing.T) {
+	cfg := testConfig{dir: t.TempDir()}
+	s := open(t, cfg)
+	if err := s.Append(states(0, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// a crash in the middle of the append
+	segments, _ := s.segments()
+	f, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"date":"2021-06-15T12:00:03Z","sta`); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	s = open(t, cfg)
+	if s.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", s.Len())
+	}
+	// appends go to a new segment after the damaged one
+	if err := s.Append(states(3, 1)); err != nil {
+		t.Fatal(err)
+	}
+	c := &collect{}
+	if _, err := s.Replay(c.save); err != nil {
+		t.Fatal(err)
+	}
+	checkOrder(t, c.saved, 0, 4)
+	if left, _ := filepath.Glob(filepath.Join(cfg.dir, "*")); len(left) != 0 {
+		t.Errorf("files left after replay: %v", left)
+	}
+}
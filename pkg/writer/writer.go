@@ -0,0 +1,275 @@
+// Package writer saves the results of checks asynchronously in batches.
+package writer
+
+import (
+	"CheckUrls/pkg/logging"
+	"CheckUrls/pkg/metrics"
+	"CheckUrls/pkg/repository"
+	statuses "CheckUrls/pkg/repository/status"
+	"CheckUrls/pkg/spool"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrWriterClosed = fmt.Errorf("result writer is closed")
+
+type WriterConfig interface {
+	GetWriteBatchSize() int
+	GetWriteFlushInterval() time.Duration
+	GetWriteQueueSize() int
+}
+
+// Writer collects states into batches, which are saved
+// when the batch is full or the flush interval passes
+type Writer struct {
+	statuses  repository.StatusRepository
+	queue     chan *statuses.State
+	batchSize int
+	interval  time.Duration
+	spool     *spool.Spool
+	// retryAt is the time of the next replay of the spool
+	retryAt time.Time
+	// pending counts Write calls in progress, they are
+	// waited for on close, so no state is lost on shutdown
+	pending int64
+	closed  int32
+	// purged are the ids of the purged sites, their states are dropped
+	mu     sync.Mutex
+	purged map[int64]bool
+}
+
+func NewWriter(cfg WriterConfig, statusRepo repository.StatusRepository) *Writer {
+	batchSize := cfg.GetWriteBatchSize()
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	queueSize := cfg.GetWriteQueueSize()
+	if queueSize < batchSize {
+		queueSize = batchSize
+	}
+	interval := cfg.GetWriteFlushInterval()
+	if interval <= 0 {
+		interval = time.Second
+	}
+	return &Writer{
+		statuses:  statusRepo,
+		queue:     make(chan *statuses.State, queueSize),
+		batchSize: batchSize,
+		interval:  interval,
+		purged:    make(map[int64]bool),
+	}
+}
+
+// SetSpool makes the writer keep the states on disk, while they
+// can not be saved in DB. The spool is replayed every flush interval,
+// after a failure the replay waits for replayDelay flush intervals.
+func (w *Writer) SetSpool(sp *spool.Spool) {
+	w.spool = sp
+}
+
+const (
+	// replayDelay is the number of flush intervals between failed replays
+	replayDelay = 5
+	// saveTimeout limits saving of a batch, batches are saved on
+	// shutdown too, so they are not saved with the context of Run
+	saveTimeout = 30 * time.Second
+)
+
+// Write queues the state. It blocks while the queue is full, so checks
+// slow down when the DB can not keep up with them, or until ctx is done.
+func (w *Writer) Write(ctx context.Context, state *statuses.State) error {
+	atomic.AddInt64(&w.pending, 1)
+	defer atomic.AddInt64(&w.pending, -1)
+	if atomic.LoadInt32(&w.closed) == 1 {
+		return ErrWriterClosed
+	}
+	select {
+	case w.queue <- state:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Forget drops the states of the purged site, which are queued or spooled
+// and not saved yet. The ids of the sites are not reused, so they are kept
+// until restart, then the states left in the spool are rejected by DB.
+func (w *Writer) Forget(siteId int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.purged[siteId] = true
+}
+
+// Run saves batches until ctx is done, then it
+// stops accepting states and saves the queued ones.
+func (w *Writer) Run(ctx context.Context) error {
+	logger := logging.NewLoggers("writer", "run")
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	batch := make([]*statuses.State, 0, w.batchSize)
+	for {
+		select {
+		case state := <-w.queue:
+			batch = append(batch, state)
+			if len(batch) >= w.batchSize {
+				batch = w.flush(batch)
+			}
+		case <-ticker.C:
+			w.replay()
+			batch = w.flush(batch)
+		case <-ctx.Done():
+			logger.DebugLog().Msg("flush on shutdown")
+			atomic.StoreInt32(&w.closed, 1)
+			batch = w.drain(batch)
+			w.retryAt = time.Time{}
+			w.replay()
+			w.flush(batch)
+			return nil
+		}
+	}
+}
+
+// drain takes the queued states until no Write is in progress
+func (w *Writer) drain(batch []*statuses.State) []*statuses.State {
+	for {
+		select {
+		case state := <-w.queue:
+			batch = append(batch, state)
+			if len(batch) >= w.batchSize {
+				batch = w.flush(batch)
+			}
+		default:
+			if atomic.LoadInt64(&w.pending) == 0 && len(w.queue) == 0 {
+				return batch
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+// flush saves the batch and returns it emptied for reuse
+func (w *Writer) flush(batch []*statuses.State) []*statuses.State {
+	metrics.WriteQueueDepth.Set(int64(len(w.queue)))
+	if len(batch) == 0 {
+		return batch
+	}
+	logger := logging.NewLoggers("writer", "flush")
+	if w.spool != nil && w.spool.Len() > 0 {
+		// new states go after the spooled ones
+		w.toSpool(batch)
+	} else if done, err := w.save(batch); err != nil {
+		logger.ErrorLog().Err(err).Int("states", len(batch)-done).Msg("unable to save states")
+		w.retryAt = time.Now().Add(replayDelay * w.interval)
+		w.toSpool(batch[done:])
+	}
+	for i := range batch {
+		batch[i] = nil
+	}
+	return batch[:0]
+}
+
+// save saves the states in DB and returns the number of the states it is
+// done with from the first one. A batch rejected by DB, e.g. with states of
+// a purged site, is saved state by state and the rejected states are dropped,
+// so they do not block the rest of the batch or the spool. On other errors,
+// such as the lost connection, the states are kept to be saved again.
+// The states of the purged sites are dropped without saving.
+func (w *Writer) save(states []*statuses.State) (int, error) {
+	kept, at := w.forget(states)
+	done, err := w.saveKept(kept)
+	switch {
+	case done == len(kept):
+		return len(states), err
+	case at != nil:
+		return at[done], err
+	default:
+		return done, err
+	}
+}
+
+// forget drops the states of the purged sites, at are the indexes of the
+// kept states in states, they are nil while no site is purged
+func (w *Writer) forget(states []*statuses.State) (kept []*statuses.State, at []int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.purged) == 0 {
+		return states, nil
+	}
+	for i, state := range states {
+		if !w.purged[state.SiteId] {
+			kept = append(kept, state)
+			at = append(at, i)
+		}
+	}
+	return kept, at
+}
+
+// saveKept saves the states, which are not dropped by forget
+func (w *Writer) saveKept(states []*statuses.State) (int, error) {
+	logger := logging.NewLoggers("writer", "save")
+	if len(states) == 0 {
+		return 0, nil
+	}
+	err := w.createBatch(states)
+	if err == nil {
+		metrics.StatesWritten.Add(int64(len(states)))
+		return len(states), nil
+	}
+	if !errors.Is(err, statuses.ErrStatesRejected) {
+		return 0, err
+	}
+	logger.WarnLog().Err(err).Int("states", len(states)).Msg("batch is rejected, saving states one by one")
+	for i, state := range states {
+		err := w.createBatch(states[i : i+1])
+		switch {
+		case err == nil:
+			metrics.StatesWritten.Add(1)
+		case errors.Is(err, statuses.ErrStatesRejected):
+			logger.WarnLog().Err(err).Int64("site_id", state.SiteId).Time("date", state.Date).
+				Msg("state is rejected and dropped")
+			metrics.StatesRejected.Add(1)
+		default:
+			return i, err
+		}
+	}
+	return len(states), nil
+}
+
+func (w *Writer) createBatch(states []*statuses.State) error {
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+	return w.statuses.CreateBatch(ctx, states)
+}
+
+// toSpool keeps the states on disk, they are dropped without spool
+func (w *Writer) toSpool(batch []*statuses.State) {
+	logger := logging.NewLoggers("writer", "toSpool")
+	if w.spool == nil {
+		metrics.StatesDropped.Add(int64(len(batch)))
+		return
+	}
+	if err := w.spool.Append(batch); err != nil {
+		logger.ErrorLog().Err(err).Int("states", len(batch)).Msg("unable to spool states")
+		metrics.StatesDropped.Add(int64(len(batch)))
+	}
+}
+
+// replay saves the spooled states in DB
+func (w *Writer) replay() {
+	logger := logging.NewLoggers("writer", "replay")
+	if w.spool == nil || w.spool.Len() == 0 || time.Now().Before(w.retryAt) {
+		return
+	}
+	replayed, err := w.spool.Replay(w.save)
+	if err != nil {
+		logger.WarnLog().Err(err).Int64("replayed", replayed).Int64("spooled", w.spool.Len()).
+			Msg("unable to replay spool")
+		w.retryAt = time.Now().Add(replayDelay * w.interval)
+		return
+	}
+	logger.InfoLog().Int64("replayed", replayed).Msg("spool replayed")
+}
@@ -0,0 +1,487 @@
+package writer
+
+import (
+	"CheckUrls/pkg/config"
+	"CheckUrls/pkg/db"
+	"CheckUrls/pkg/metrics"
+	"CheckUrls/pkg/migrations"
+	"CheckUrls/pkg/repository"
+	"CheckUrls/pkg/repository/memory"
+	"CheckUrls/pkg/repository/sites"
+	"CheckUrls/pkg/repository/sqlite"
+	statuses "CheckUrls/pkg/repository/status"
+	"CheckUrls/pkg/spool"
+	"context"
+	"errors"
+	"github.com/rs/zerolog"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	batch, queue int
+	interval     time.Duration
+}
+
+func (c testConfig) GetWriteBatchSize() int               { return c.batch }
+func (c testConfig) GetWriteFlushInterval() time.Duration { return c.interval }
+func (c testConfig) GetWriteQueueSize() int               { return c.queue }
+
+type spoolConfig struct {
+	dir string
+}
+
+func (c spoolConfig) GetSpoolDir() string     { return c.dir }
+func (c spoolConfig) GetSpoolMaxBytes() int64 { return 0 }
+
+var errDown = errors.New("DB is down")
+
+// recorder records the sizes of saved batches, it blocks
+// while gate is not nil and open and fails while down is set
+type recorder struct {
+	repository.StatusRepository
+	mu      sync.Mutex
+	batches []int
+	dates   []time.Time
+	gate    chan struct{}
+	down    int32
+}
+
+func (r *recorder) CreateBatch(ctx context.Context, states []*statuses.State) error {
+	if r.gate != nil {
+		<-r.gate
+	}
+	if atomic.LoadInt32(&r.down) == 1 {
+		return errDown
+	}
+	r.mu.Lock()
+	r.batches = append(r.batches, len(states))
+	for _, state := range states {
+		r.dates = append(r.dates, state.Date)
+	}
+	r.mu.Unlock()
+	return r.StatusRepository.CreateBatch(ctx, states)
+}
+
+func (r *recorder) sizes() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.batches...)
+}
+
+func newRecorder(t *testing.T) (*recorder, int64) {
+	siteRepo, statusRepo := memory.NewRepositories()
+	site := &sites.Site{Url: "https://example.com"}
+	if err := siteRepo.Create(context.Background(), site, nil); err != nil {
+		t.Fatal(err)
+	}
+	return &recorder{StatusRepository: statusRepo}, site.Id
+}
+
+func state(siteId int64) *statuses.State {
+	return &statuses.State{Date: time.Now(), Status: 200, SiteId: siteId}
+}
+
+func run(w *Writer) (context.CancelFunc, chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = w.Run(ctx)
+		close(done)
+	}()
+	return cancel, done
+}
+
+func TestBatchBySize(t *testing.T) {
+	repo, siteId := newRecorder(t)
+	w := NewWriter(testConfig{batch: 10, queue: 100, interval: time.Hour}, repo)
+	cancel, done := run(w)
+	for i := 0; i < 25; i++ {
+		if err := w.Write(context.Background(), state(siteId)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(repo.sizes()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the rest is saved on shutdown
+	cancel()
+	<-done
+	if got := repo.sizes(); len(got) != 3 || got[0] != 10 || got[1] != 10 || got[2] != 5 {
+		t.Errorf("batches = %v, want [10 10 5]", got)
+	}
+	if err := w.Write(context.Background(), state(siteId)); err != ErrWriterClosed {
+		t.Errorf("Write after shutdown = %v, want %v", err, ErrWriterClosed)
+	}
+}
+
+func TestBatchByInterval(t *testing.T) {
+	repo, siteId := newRecorder(t)
+	w := NewWriter(testConfig{batch: 100, queue: 100, interval: 10 * time.Millisecond}, repo)
+	cancel, done := run(w)
+	defer func() {
+		cancel()
+		<-done
+	}()
+	for i := 0; i < 3; i++ {
+		if err := w.Write(context.Background(), state(siteId)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(repo.sizes()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("states were not saved after the flush interval")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBackpressure(t *testing.T) {
+	repo, siteId := newRecorder(t)
+	repo.gate = make(chan struct{})
+	w := NewWriter(testConfig{batch: 1, queue: 2, interval: time.Hour}, repo)
+	cancel, done := run(w)
+
+	// one state is being saved and two are queued
+	for i := 0; i < 3; i++ {
+		if err := w.Write(context.Background(), state(siteId)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	written := make(chan error)
+	go func() { written <- w.Write(context.Background(), state(siteId)) }()
+	select {
+	case <-written:
+		t.Fatal("Write did not wait for the full queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(repo.gate)
+	if err := <-written; err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	<-done
+	if got := len(repo.sizes()); got != 4 {
+		t.Errorf("saved %d batches, want 4", got)
+	}
+}
+
+func TestWriteCanceled(t *testing.T) {
+	repo, siteId := newRecorder(t)
+	repo.gate = make(chan struct{})
+	w := NewWriter(testConfig{batch: 1, queue: 1, interval: time.Hour}, repo)
+	cancel, done := run(w)
+	defer func() {
+		close(repo.gate)
+		cancel()
+		<-done
+	}()
+
+	// one state is being saved and one is queued
+	for i := 0; i < 2; i++ {
+		if err := w.Write(context.Background(), state(siteId)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx, cancelWrite := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelWrite()
+	if err := w.Write(ctx, state(siteId)); err != context.DeadlineExceeded {
+		t.Errorf("Write = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFlushOnShutdown(t *testing.T) {
+	repo, siteId := newRecorder(t)
+	w := NewWriter(testConfig{batch: 7, queue: 10, interval: time.Hour}, repo)
+	cancel, done := run(w)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := w.Write(context.Background(), state(siteId)); err == nil {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	wg.Wait()
+	<-done
+
+	saved := 0
+	for _, size := range repo.sizes() {
+		saved += size
+	}
+	if saved != accepted {
+		t.Errorf("saved %d states, accepted %d", saved, accepted)
+	}
+}
+
+func TestSpoolWhileDown(t *testing.T) {
+	repo, siteId := newRecorder(t)
+	atomic.StoreInt32(&repo.down, 1)
+	sp, err := spool.Open(spoolConfig{dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sp.Close()
+	w := NewWriter(testConfig{batch: 5, queue: 10, interval: 5 * time.Millisecond}, repo)
+	w.SetSpool(sp)
+	cancel, done := run(w)
+
+	start := time.Date(2021, 6, 15, 12, 0, 0, 0, time.UTC)
+	write := func(from, to int) {
+		for i := from; i < to; i++ {
+			if err := w.Write(context.Background(), &statuses.State{Date: start.Add(time.Duration(i) * time.Minute),
+				Status: 200, SiteId: siteId}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write(0, 20)
+	deadline := time.Now().Add(5 * time.Second)
+	for sp.Len() < 20 {
+		if time.Now().After(deadline) {
+			t.Fatalf("spooled %d states, want 20", sp.Len())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// the spool is replayed when the DB is back
+	atomic.StoreInt32(&repo.down, 0)
+	write(20, 30)
+	cancel()
+	<-done
+	if sp.Len() != 0 {
+		t.Errorf("%d states left in spool", sp.Len())
+	}
+	if len(repo.dates) != 30 {
+		t.Fatalf("saved %d states, want 30", len(repo.dates))
+	}
+	for i, date := range repo.dates {
+		if want := start.Add(time.Duration(i) * time.Minute); !date.Equal(want) {
+			t.Fatalf("state #%d saved at %v, want %v", i, date, want)
+		}
+	}
+}
+
+func TestReplayRejected(t *testing.T) {
+	repo := newSqliteRepo(t, 2)
+	sp, err := spool.Open(spoolConfig{dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sp.Close()
+
+	// the states of site 3 break the foreign key, e.g. the site was purged
+	// while its states were spooled
+	start := time.Date(2021, 6, 15, 12, 0, 0, 0, time.UTC)
+	var spooled []*statuses.State
+	for i, siteId := range []int64{1, 3, 2, 3, 1} {
+		spooled = append(spooled, &statuses.State{Date: start.Add(time.Duration(i) * time.Minute),
+			Status: 200, SiteId: siteId})
+	}
+	if err := sp.Append(spooled); err != nil {
+		t.Fatal(err)
+	}
+	rejected := metrics.StatesRejected.Value()
+
+	w := NewWriter(testConfig{batch: 10, queue: 10, interval: 5 * time.Millisecond}, repo)
+	w.SetSpool(sp)
+	cancel, done := run(w)
+	if err := w.Write(context.Background(), &statuses.State{Date: start.Add(time.Hour), Status: 200,
+		SiteId: 2}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for sp.Len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if sp.Len() != 0 {
+		t.Errorf("%d states left in spool, want the rejected ones dropped", sp.Len())
+	}
+	if got := metrics.StatesRejected.Value() - rejected; got != 2 {
+		t.Errorf("%d states rejected, want 2", got)
+	}
+	for siteId, want := range map[int64]int{1: 2, 2: 2} {
+		states, err := repo.ReadBySite(context.Background(), statuses.Filter{SiteId: siteId})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(states) != want {
+			t.Errorf("site %d has %d states, want %d", siteId, len(states), want)
+		}
+	}
+}
+
+func TestForgetPurged(t *testing.T) {
+	repo := newSqliteRepo(t, 2)
+	sp, err := spool.Open(spoolConfig{dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sp.Close()
+
+	start := time.Date(2021, 6, 15, 12, 0, 0, 0, time.UTC)
+	var spooled []*statuses.State
+	for i, siteId := range []int64{2, 1, 2, 1} {
+		spooled = append(spooled, &statuses.State{Date: start.Add(time.Duration(i) * time.Minute),
+			Status: 200, SiteId: siteId})
+	}
+	if err := sp.Append(spooled); err != nil {
+		t.Fatal(err)
+	}
+	rejected := metrics.StatesRejected.Value()
+
+	// the queued and spooled states of the purged site are dropped
+	w := NewWriter(testConfig{batch: 10, queue: 10, interval: 5 * time.Millisecond}, repo)
+	w.SetSpool(sp)
+	w.Forget(2)
+	for _, siteId := range []int64{1, 2} {
+		if err := w.Write(context.Background(), &statuses.State{Date: start.Add(time.Hour), Status: 200,
+			SiteId: siteId}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancel, done := run(w)
+	cancel()
+	<-done
+
+	if sp.Len() != 0 {
+		t.Errorf("%d states left in spool", sp.Len())
+	}
+	if got := metrics.StatesRejected.Value() - rejected; got != 0 {
+		t.Errorf("%d states rejected, want the states of the purged site dropped before saving", got)
+	}
+	for siteId, want := range map[int64]int{1: 3, 2: 0} {
+		states, err := repo.ReadBySite(context.Background(), statuses.Filter{SiteId: siteId})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(states) != want {
+			t.Errorf("site %d has %d states, want %d", siteId, len(states), want)
+		}
+	}
+}
+
+// benchSites is the number of checked sites in benchmarks
+const benchSites = 10000
+
+// newBenchRepo returns SQLite repository with benchSites sites
+func newBenchRepo(b *testing.B) repository.StatusRepository {
+	level := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	b.Cleanup(func() { zerolog.SetGlobalLevel(level) })
+	return newSqliteRepo(b, benchSites)
+}
+
+// newSqliteRepo returns SQLite repository with the number of sites
+func newSqliteRepo(b testing.TB, count int) repository.StatusRepository {
+	conn := db.NewConnectionManager()
+	cfg := &config.EnvCache{DbDriver: db.DriverSqlite, DbPath: filepath.Join(b.TempDir(), "checkUrls.db")}
+	if err := conn.Connect(cfg); err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = conn.Close() })
+	migrator, err := migrations.NewMigrator(conn, migrations.DialectSqlite)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := conn.Tx(ctx, func(tx *db.Tx) error {
+		for i := 0; i < count; i++ {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO sites (url, frequency) VALUES ($1, 60);",
+				"https://example.com/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		b.Fatal(err)
+	}
+	return sqlite.NewStatusRepository(conn)
+}
+
+func benchState(i int) *statuses.State {
+	return &statuses.State{
+		Date:    time.Now(),
+		Status:  200,
+		SiteId:  int64(i%benchSites) + 1,
+		Latency: time.Duration(i%500) * time.Millisecond,
+	}
+}
+
+// BenchmarkDirect saves every state by a separate statement
+func BenchmarkDirect(b *testing.B) {
+	repo := newBenchRepo(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := repo.Create(context.Background(), benchState(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "states/s")
+}
+
+// BenchmarkWriter saves states of 64 concurrent checks through
+// the writer, the time includes the flush of the last batch
+func BenchmarkWriter(b *testing.B) {
+	for _, batch := range []int{1, 10, 100, 1000} {
+		b.Run("batch="+strconv.Itoa(batch), func(b *testing.B) {
+			repo := newBenchRepo(b)
+			w := NewWriter(testConfig{batch: batch, queue: 10 * batch, interval: 100 * time.Millisecond}, repo)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			b.ResetTimer()
+			go func() {
+				_ = w.Run(ctx)
+				close(done)
+			}()
+
+			var wg sync.WaitGroup
+			var next int64
+			for c := 0; c < 64; c++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for {
+						i := int(atomic.AddInt64(&next, 1)) - 1
+						if i >= b.N {
+							return
+						}
+						if err := w.Write(context.Background(), benchState(i)); err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			cancel()
+			<-done
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "states/s")
+		})
+	}
+}